@@ -21,6 +21,8 @@ import (
 	grafv1alpha1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -58,8 +60,10 @@ type PlatformMonitoringSpec struct {
 	PrometheusRules    *PrometheusRules   `json:"prometheusRules,omitempty"`
 	Promxy             *Promxy            `json:"promxy,omitempty"`
 	Pushgateway        *Pushgateway       `json:"pushgateway,omitempty"`
-	PublicCloudName    string             `json:"publicCloudName,omitempty"`
-	Victoriametrics    *Victoriametrics   `json:"victoriametrics,omitempty"`
+	// PublicCloudName is the name of the platform profile which enables or disables monitors, groups of rules
+	// and dashboards, and sets default parameters for the platform
+	PublicCloudName string           `json:"publicCloudName,omitempty"`
+	Victoriametrics *Victoriametrics `json:"victoriametrics,omitempty"`
 }
 
 // AlertManager defines the desired state for some part of prometheus-operator deployment
@@ -196,8 +200,139 @@ type Grafana struct {
 	// PriorityClassName assigned to the Pods
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Folders of dashboards with permissions of Grafana teams, OAuth groups and roles.
+	// Dashboards which are not in any folder are created in the General folder.
+	// +optional
+	Folders []GrafanaFolder `json:"folders,omitempty"`
+	// Datasources are additional datasources of Grafana, e.g. Loki, Tempo or other Prometheus-compatible storages.
+	// They are created in addition to the datasource of Prometheus or VictoriaMetrics installed by the operator.
+	// +optional
+	Datasources []GrafanaDatasource `json:"datasources,omitempty"`
+}
+
+// GrafanaDatasourceType is the type of the datasource in the catalogue of datasources
+// +kubebuilder:validation:Enum=prometheus;loki;tempo;elasticsearch;opensearch;alertmanager
+type GrafanaDatasourceType string
+
+// Types of datasources
+const (
+	GrafanaDatasourcePrometheus    GrafanaDatasourceType = "prometheus"
+	GrafanaDatasourceLoki          GrafanaDatasourceType = "loki"
+	GrafanaDatasourceTempo         GrafanaDatasourceType = "tempo"
+	GrafanaDatasourceElasticsearch GrafanaDatasourceType = "elasticsearch"
+	GrafanaDatasourceOpenSearch    GrafanaDatasourceType = "opensearch"
+	GrafanaDatasourceAlertmanager  GrafanaDatasourceType = "alertmanager"
+)
+
+// GrafanaDatasource is a datasource of Grafana. Only one of URL and Discovery must be set.
+type GrafanaDatasource struct {
+	// Name of the datasource in Grafana. If Discovery finds several Services, names of datasources
+	// are suffixed with namespaces and names of Services.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Type of the datasource. The prometheus type is used for all Prometheus-compatible storages,
+	// e.g. VictoriaMetrics, Thanos or Mimir. The opensearch type requires the grafana-opensearch-datasource plugin.
+	Type GrafanaDatasourceType `json:"type"`
+	// UID of the datasource, allows to refer to the datasource from dashboards.
+	// Must be unique and not longer than 40 characters. Ignored if Discovery finds several Services.
+	// +kubebuilder:validation:MaxLength=40
+	// +optional
+	UID string `json:"uid,omitempty"`
+	// URL of the datasource
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Discovery finds Services of the datasource by labels, a datasource is created for each Service
+	// +optional
+	Discovery *DatasourceDiscovery `json:"discovery,omitempty"`
+	// IsDefault makes the datasource default instead of the datasource of Prometheus or VictoriaMetrics
+	// +optional
+	IsDefault bool `json:"isDefault,omitempty"`
+	// BasicAuth allows to use the username and the password from Secrets in the namespace of PlatformMonitoring
+	// +optional
+	BasicAuth *promv1.BasicAuth `json:"basicAuth,omitempty"`
+	// BearerToken from the Secret in the namespace of PlatformMonitoring is sent in the Authorization header
+	// +optional
+	BearerToken *v1.SecretKeySelector `json:"bearerToken,omitempty"`
+	// TLSConfig allows to use certificates from Secrets in the namespace of PlatformMonitoring
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+	// Database is the index or the index pattern of Elasticsearch and OpenSearch
+	// +optional
+	Database string `json:"database,omitempty"`
+	// JSONData contains additional settings of the datasource which are merged into jsonData,
+	// see https://grafana.com/docs/grafana/latest/administration/provisioning/#data-sources
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	JSONData *apiextensionsv1.JSON `json:"jsonData,omitempty"`
+}
+
+// DatasourceDiscovery finds Services of the datasource by labels
+type DatasourceDiscovery struct {
+	// Selector of Services
+	Selector metav1.LabelSelector `json:"selector"`
+	// NamespaceSelector selects namespaces with Services. If not set, Services are found in the namespace
+	// of PlatformMonitoring. Other namespaces require privileged rights.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Port is the name of the port of the Service. If not set, the first port is used.
+	// +optional
+	Port string `json:"port,omitempty"`
+	// Scheme of URLs of Services: http or https
+	// +kubebuilder:validation:Enum=http;https
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+	// Path is added to URLs of Services, e.g. /select/0/prometheus
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
+// GrafanaFolder is a folder of dashboards in Grafana
+type GrafanaFolder struct {
+	// Title of the folder in Grafana
+	// +kubebuilder:validation:MinLength=1
+	Title string `json:"title"`
+	// Dashboards moved to the folder: names from grafanaDashboards.list or names of GrafanaDashboards
+	// created from grafanaDashboards.sources
+	// +optional
+	Dashboards []string `json:"dashboards,omitempty"`
+	// Permissions replace all permissions of the folder, so users without permissions can't see
+	// or edit its dashboards. Grafana admins have access to all folders.
+	// If empty, default permissions of Grafana are kept.
+	// +optional
+	Permissions []GrafanaFolderPermission `json:"permissions,omitempty"`
+}
+
+// GrafanaFolderPermission grants the permission on the folder. Only one of Team, Group and Role must be set.
+type GrafanaFolderPermission struct {
+	// Team is the name of the Grafana team, the team is created if it doesn't exist
+	// +optional
+	Team string `json:"team,omitempty"`
+	// Group is the OAuth group from the identity provider configured in auth. The operator creates
+	// the Grafana team with the name of the group and synchronizes its members with the group,
+	// team synchronization is available only in Grafana Enterprise.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Role is the organization role of users: Viewer or Editor
+	// +kubebuilder:validation:Enum=Viewer;Editor
+	// +optional
+	Role string `json:"role,omitempty"`
+	// Permission on the folder: View, Edit or Admin
+	Permission GrafanaPermission `json:"permission"`
+}
+
+// GrafanaPermission is the permission on the folder in Grafana
+// +kubebuilder:validation:Enum=View;Edit;Admin
+type GrafanaPermission string
+
+// Permissions on folders in Grafana
+const (
+	GrafanaPermissionView  GrafanaPermission = "View"
+	GrafanaPermissionEdit  GrafanaPermission = "Edit"
+	GrafanaPermissionAdmin GrafanaPermission = "Admin"
+)
+
 // GrafanaOperator defines the desired state for some part of grafana-operator deployment
 type GrafanaOperator struct {
 	// Image to use for a `grafana-operator` deployment.
@@ -491,8 +626,10 @@ type PrometheusOperator struct {
 
 type Victoriametrics struct {
 	TLSEnabled bool `json:"tlsEnabled,omitempty"`
-	//VmReplicas     *int32         `json:"vmReplicas,omitempty"`	//TODO: Revert this line when vmCluster is actualized
-	VmReplicas     *int32         `json:"-"`
+	// VmReplicas is the number of replicas of all VictoriaMetrics components if VmCluster is installed.
+	// It overrides replicas of the components.
+	// +optional
+	VmReplicas     *int32         `json:"vmReplicas,omitempty"`
 	VmOperator     VmOperator     `json:"vmOperator,omitempty"`
 	VmSingle       VmSingle       `json:"vmSingle,omitempty"`
 	VmAgent        VmAgent        `json:"vmAgent,omitempty"`
@@ -500,8 +637,26 @@ type Victoriametrics struct {
 	VmAlert        VmAlert        `json:"vmAlert,omitempty"`
 	VmAuth         VmAuth         `json:"vmAuth,omitempty"`
 	VmUser         VmUser         `json:"vmUser,omitempty"`
-	//VmCluster      VmCluster      `json:"vmCluster,omitempty"`	//TODO: Revert this line when vmCluster is actualized
-	VmCluster VmCluster `json:"-"`
+	// VmCluster is the clustered storage of VictoriaMetrics. It can't be installed together with VmSingle.
+	// +optional
+	VmCluster VmCluster `json:"vmCluster,omitempty"`
+	// Tenants of VmCluster with isolated metrics. Each tenant gets its own VMUser in vmAuth and
+	// metrics of selected namespaces are written to the tenant. Ignored if VmCluster is not installed.
+	// +optional
+	Tenants []VmTenant `json:"tenants,omitempty"`
+	// Migration copies existing data from VmSingle or Prometheus to the installed storage of VictoriaMetrics
+	// with a vmctl Job. The source is kept until the migration succeeds and its deletion is confirmed.
+	// +optional
+	Migration *VmMigration `json:"migration,omitempty"`
+	// Backup creates scheduled backups of the installed storage of VictoriaMetrics, vmsingle or each vmstorage,
+	// with vmbackup CronJobs and restores the storage from a backup with vmrestore before the storage starts.
+	// +optional
+	Backup *VmBackup `json:"backup,omitempty"`
+	// RetentionPolicy sets retention periods of series and tenants and downsampling of the installed storage
+	// of VictoriaMetrics, vmsingle or vmstorage, in addition to the global retentionPeriod.
+	// Retention filters and downsampling are features of the enterprise version of VictoriaMetrics.
+	// +optional
+	RetentionPolicy *VmRetentionPolicy `json:"retentionPolicy,omitempty"`
 }
 type VmOperator struct {
 	// Install indicates is victoriametrics-operator will be installed.
@@ -1186,6 +1341,251 @@ type VmUser struct {
 	TargetRefs []vmetricsv1b1.TargetRef `json:"targetRefs,omitempty"`
 }
 
+// VmTenant is a tenant of VmCluster, see https://docs.victoriametrics.com/cluster-victoriametrics/#multitenancy
+type VmTenant struct {
+	// Name of the tenant, the VMUser of the tenant is named k8s-<name>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+	// ID of the tenant in the format accountID or accountID:projectID
+	// +kubebuilder:validation:Pattern=`^[0-9]+(:[0-9]+)?$`
+	ID string `json:"id"`
+	// UserName of the VMUser, the name of the tenant is used by default
+	// +optional
+	UserName *string `json:"userName,omitempty"`
+	// PasswordRef refers to the password of the VMUser in the Secret in the namespace of PlatformMonitoring.
+	// If not set, the password is generated. Credentials are stored in the Secret vmuser-k8s-<name>
+	// with username and password keys in both cases.
+	// +optional
+	PasswordRef *v1.SecretKeySelector `json:"passwordRef,omitempty"`
+	// NamespaceSelector selects namespaces which metrics are written by vmagent to the tenant
+	// instead of the default tenant of VmCluster. Requires privileged rights.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Grafana creates the datasource of the tenant in Grafana
+	// +optional
+	Grafana *VmTenantGrafana `json:"grafana,omitempty"`
+}
+
+// VmTenantGrafana is the datasource of the tenant in Grafana
+type VmTenantGrafana struct {
+	// OrgID is the ID of the existing organisation of Grafana in which the datasource is created.
+	// The main organisation is used by default.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	OrgID int `json:"orgId,omitempty"`
+}
+
+// VmMigrationSource is a storage which data is migrated from
+// +kubebuilder:validation:Enum=VMSingle;Prometheus
+type VmMigrationSource string
+
+// Sources of the data migration
+const (
+	// VmMigrationSourceVMSingle migrates data of vmsingle to VmCluster with the native export and import
+	VmMigrationSourceVMSingle VmMigrationSource = "VMSingle"
+	// VmMigrationSourcePrometheus migrates data of Prometheus to VmSingle or VmCluster with the remote read API
+	VmMigrationSourcePrometheus VmMigrationSource = "Prometheus"
+)
+
+// VmMigration defines the migration of existing data to the storage of VictoriaMetrics with vmctl
+type VmMigration struct {
+	// Source is the storage which data is migrated from. VMSingle can be migrated only to VmCluster.
+	Source VmMigrationSource `json:"source"`
+	// Image to use for the vmctl Job.
+	// More info: https://docs.victoriametrics.com/vmctl/
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// TimeStart is the beginning of the migrated time range in RFC3339 format, e.g. 2024-01-01T00:00:00Z
+	// +kubebuilder:validation:MinLength=1
+	TimeStart string `json:"timeStart"`
+	// TimeEnd is the end of the migrated time range in RFC3339 format. The current time is used by default.
+	// +optional
+	TimeEnd string `json:"timeEnd,omitempty"`
+	// Match is the series selector of migrated data. All series are migrated by default.
+	// +optional
+	Match string `json:"match,omitempty"`
+	// ConfirmSourceDeletion allows to delete the source after the migration succeeds.
+	// The source is kept while the migration is not succeeded or the deletion is not confirmed.
+	// +optional
+	ConfirmSourceDeletion bool `json:"confirmSourceDeletion,omitempty"`
+	// BackoffLimit is the number of retries of the Job before the migration is considered failed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// ExtraArgs are additional flags of vmctl, e.g. vm-concurrency: "4"
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+	// Resources defines resources requests and limits for the pod of the Job.
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// SecurityContext holds pod-level security attributes.
+	// +optional
+	SecurityContext *SecurityContext `json:"securityContext,omitempty"`
+}
+
+// VmBackup defines scheduled backups of the storage of VictoriaMetrics with vmbackup
+// and the restore of the storage with vmrestore. Exactly one destination of backups must be set.
+type VmBackup struct {
+	// Image to use for vmbackup CronJobs.
+	// More info: https://docs.victoriametrics.com/vmbackup/
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// RestoreImage to use for the vmrestore init container. Required if the restore is requested.
+	// More info: https://docs.victoriametrics.com/vmrestore/
+	// +optional
+	RestoreImage string `json:"restoreImage,omitempty"`
+	// Schedule of backups in the cron format, e.g. "0 1 * * *"
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// Suspend stops creating new backups. Existing backups are kept.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Generations is the number of kept backups of each storage node. Backups are written to directories
+	// gen-0, gen-1 and so on in turn, so the oldest generation is overwritten by the next backup.
+	// 3 generations are kept by default.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Generations *int32 `json:"generations,omitempty"`
+	// S3 is the S3-compatible object storage which backups are written to, e.g. AWS S3 or MinIO
+	// +optional
+	S3 *VmBackupS3 `json:"s3,omitempty"`
+	// Filesystem is the volume which backups are written to, e.g. hostPath or NFS
+	// +optional
+	Filesystem *VmBackupFilesystem `json:"filesystem,omitempty"`
+	// Restore requests the restore of the storage from a generation of backups
+	// +optional
+	Restore *VmRestore `json:"restore,omitempty"`
+	// ExtraArgs are additional flags of vmbackup, e.g. concurrency: "4". They override generated flags.
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+	// Resources defines resources requests and limits for pods of backups and the restore.
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// SecurityContext holds pod-level security attributes of pods of backups.
+	// It must allow reading data of the storage.
+	// +optional
+	SecurityContext *SecurityContext `json:"securityContext,omitempty"`
+}
+
+// VmBackupS3 defines the S3-compatible destination of backups
+type VmBackupS3 struct {
+	// Bucket which backups are written to
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+	// Path is the prefix of backups in the bucket
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Endpoint of the S3-compatible storage, e.g. http://minio.minio.svc:9000. AWS S3 is used by default.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Region of the bucket
+	// +optional
+	Region string `json:"region,omitempty"`
+	// AccessKey is the key of the Secret with the access key ID
+	// +optional
+	AccessKey *v1.SecretKeySelector `json:"accessKey,omitempty"`
+	// SecretKey is the key of the Secret with the secret access key
+	// +optional
+	SecretKey *v1.SecretKeySelector `json:"secretKey,omitempty"`
+}
+
+// VmBackupFilesystem defines the filesystem destination of backups
+type VmBackupFilesystem struct {
+	// Volume which backups are written to. A hostPath volume keeps backups only on the node of the storage.
+	Volume VmBackupVolume `json:"volume"`
+	// Path is the directory of backups in the volume
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// VmBackupVolume defines the volume of the filesystem destination of backups. Exactly one of the sources must be set.
+type VmBackupVolume struct {
+	// HostPath is the directory on the node of the storage
+	// +optional
+	HostPath *v1.HostPathVolumeSource `json:"hostPath,omitempty"`
+	// PersistentVolumeClaim is the claim in the namespace of the storage
+	// +optional
+	PersistentVolumeClaim *v1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+	// NFS is the NFS share
+	// +optional
+	NFS *v1.NFSVolumeSource `json:"nfs,omitempty"`
+}
+
+// VmRestore defines the restore of the storage from backups
+type VmRestore struct {
+	// ID of the restore request. vmrestore runs once for each ID before the storage starts,
+	// so the ID must be changed to restore the storage again.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	ID string `json:"id"`
+	// Generation of backups which the storage is restored from, see generations in the status of backups
+	// +kubebuilder:validation:Minimum=0
+	Generation int32 `json:"generation"`
+}
+
+// VmRetentionPolicy defines retention periods and downsampling of the storage of VictoriaMetrics.
+// It is translated into -retentionFilter and -downsampling.period flags of vmsingle or vmstorage.
+type VmRetentionPolicy struct {
+	// Filters set retention periods of series matched by series filters
+	// +optional
+	Filters []VmRetentionFilter `json:"filters,omitempty"`
+	// Tenants set retention periods of tenants of VmCluster. Ignored if VmCluster is not installed.
+	// +optional
+	Tenants []VmTenantRetention `json:"tenants,omitempty"`
+	// Downsampling leaves one sample per interval for samples older than the offset.
+	// Levels are applied to all series or to series matched by their filters.
+	// +optional
+	Downsampling []VmDownsampling `json:"downsampling,omitempty"`
+	// Estimate is the expected load of the storage which is used to estimate the disk usage
+	// +optional
+	Estimate *VmStorageEstimate `json:"estimate,omitempty"`
+}
+
+// VmRetentionFilter defines the retention period of series matched by the filter
+type VmRetentionFilter struct {
+	// Filter is a series filter, e.g. {env="dev"} or {__name__=~"node_.*"}
+	// More info: https://docs.victoriametrics.com/keyconcepts/#filtering
+	// +kubebuilder:validation:MinLength=1
+	Filter string `json:"filter"`
+	// RetentionPeriod of matched series. It must not be greater than the global retentionPeriod.
+	// +kubebuilder:validation:MinLength=1
+	RetentionPeriod string `json:"retentionPeriod"`
+}
+
+// VmTenantRetention defines the retention period of a tenant of VmCluster
+type VmTenantRetention struct {
+	// Tenant ID in the format accountID or accountID:projectID
+	// +kubebuilder:validation:Pattern=`^[0-9]+(:[0-9]+)?$`
+	Tenant string `json:"tenant"`
+	// RetentionPeriod of series of the tenant. It must not be greater than the global retentionPeriod.
+	// +kubebuilder:validation:MinLength=1
+	RetentionPeriod string `json:"retentionPeriod"`
+}
+
+// VmDownsampling defines a level of downsampling
+type VmDownsampling struct {
+	// Filter is a series filter of downsampled series, all series by default.
+	// Filters require VictoriaMetrics v1.100.0 or newer.
+	// +optional
+	Filter string `json:"filter,omitempty"`
+	// Offset is the age of samples which are downsampled, e.g. 30d
+	// +kubebuilder:validation:MinLength=1
+	Offset string `json:"offset"`
+	// Interval between samples which are left after downsampling, e.g. 5m
+	// +kubebuilder:validation:MinLength=1
+	Interval string `json:"interval"`
+}
+
+// VmStorageEstimate defines the expected load of the storage of VictoriaMetrics
+type VmStorageEstimate struct {
+	// IngestionRate is the expected number of samples ingested per second
+	// +kubebuilder:validation:Minimum=1
+	IngestionRate int64 `json:"ingestionRate"`
+	// BytesPerSample is the expected size of a sample on the disk, 1 byte by default
+	// +optional
+	BytesPerSample *resource.Quantity `json:"bytesPerSample,omitempty"`
+}
+
 // VMClusterSpec defines the desired state of VMCluster
 // +k8s:openapi-gen=true
 type VmCluster struct {
@@ -1200,11 +1600,18 @@ type VmCluster struct {
 	// metrics from data/ removed eventually as soon as partition leaves retention period
 	// reverse index data at indexdb rotates once at the half of configured retention period
 	// https://docs.victoriametrics.com/Single-server-VictoriaMetrics.html#retention
-	RetentionPeriod string `json:"retentionPeriod"`
+	// +optional
+	RetentionPeriod string `json:"retentionPeriod,omitempty"`
 	// ReplicationFactor defines how many copies of data make among
 	// distinct storage nodes
 	// +optional
 	ReplicationFactor *int32 `json:"replicationFactor,omitempty"`
+	// Tenant which vmagent writes metrics to and vmalert and Grafana read metrics from
+	// in the format accountID or accountID:projectID. Tenant 0 is used by default.
+	// See https://docs.victoriametrics.com/cluster-victoriametrics/#multitenancy
+	// +kubebuilder:validation:Pattern=`^[0-9]+(:[0-9]+)?$`
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
 
 	// ServiceAccountName is the name of the ServiceAccount to use to run the
 	// VMSelect, VMStorage and VMInsert Pods.
@@ -1221,6 +1628,11 @@ type VmCluster struct {
 	// it can be overwritten with component specific image.tag value.
 	ClusterVersion string `json:"clusterVersion,omitempty"`
 
+	// VmSelect is the spec of vmselect of VMCluster, see https://docs.victoriametrics.com/operator/api/#vmselect.
+	// It is validated by the CRD of VMCluster, so its schema is not included into this CRD.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
 	// +optional
 	VmSelect *vmetricsv1b1.VMSelect `json:"vmselect,omitempty"`
 	//Image for VMSelect
@@ -1230,6 +1642,11 @@ type VmCluster struct {
 	VmSelectTLSConfig *VmTLSConfig `json:"vmSelectTlsConfig,omitempty"`
 	// Ingress enables ingress configuration for VMSelect.
 	VmSelectIngress *Ingress `json:"vmSelectIngress,omitempty"`
+	// VmInsert is the spec of vminsert of VMCluster, see https://docs.victoriametrics.com/operator/api/#vminsert.
+	// It is validated by the CRD of VMCluster, so its schema is not included into this CRD.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
 	// +optional
 	VmInsert *vmetricsv1b1.VMInsert `json:"vminsert,omitempty"`
 	//Image for VMInsert
@@ -1237,6 +1654,11 @@ type VmCluster struct {
 	// TLS Configuration
 	// +optional
 	VmInsertTLSConfig *VmTLSConfig `json:"vmInsertTlsConfig,omitempty"`
+	// VmStorage is the spec of vmstorage of VMCluster, see https://docs.victoriametrics.com/operator/api/#vmstorage.
+	// It is validated by the CRD of VMCluster, so its schema is not included into this CRD.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
 	// +optional
 	VmStorage *vmetricsv1b1.VMStorage `json:"vmstorage,omitempty"`
 	//Image for VMStorage
@@ -1512,21 +1934,297 @@ type Ingress struct {
 	TLSSecretName string `json:"tlsSecretName,omitempty"`
 }
 
-// PlatformMonitoringCondition contains description of status of PlatformMonitoring
-// +k8s:openapi-gen=true
-type PlatformMonitoringCondition struct {
-	Type               string `json:"type"`
-	Status             string `json:"status"`
-	Reason             string `json:"reason"`
-	Message            string `json:"message"`
-	LastTransitionTime string `json:"lastTransitionTime"`
+// Condition types of PlatformMonitoring
+const (
+	// ConditionAvailable means that all installed components are reconciled and their pods are ready
+	ConditionAvailable = "Available"
+	// ConditionProgressing means that the reconciliation of the components is in progress
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded means that the reconciliation of at least one component failed or was skipped
+	ConditionDegraded = "Degraded"
+	// ConditionRulesValid means that expressions of all Prometheus rules are valid.
+	// If it is false, the message contains groups, rules and parse errors of invalid expressions.
+	ConditionRulesValid = "RulesValid"
+)
+
+// PlanAnnotation enables the plan mode of PlatformMonitoring. While the annotation is set,
+// the operator doesn't change objects of components, but computes changes which the reconciliation
+// of the current spec would make and reports them in the status and in the ConfigMap.
+// Removing the annotation applies the plan.
+const PlanAnnotation = "monitoring.qubership.org/plan"
+
+// PlanAction is an action which the reconciliation would make with an object
+// +kubebuilder:validation:Enum=Create;Update;Delete
+type PlanAction string
+
+// Actions of planned changes
+const (
+	PlanActionCreate PlanAction = "Create"
+	PlanActionUpdate PlanAction = "Update"
+	PlanActionDelete PlanAction = "Delete"
+)
+
+// ComponentPhase is a phase of a component of PlatformMonitoring
+// +kubebuilder:validation:Enum=Ready;Progressing;Failed;Skipped;Disabled
+type ComponentPhase string
+
+// Phases of a component of PlatformMonitoring
+const (
+	// ComponentReady means that the component is reconciled and all its pods are ready
+	ComponentReady ComponentPhase = "Ready"
+	// ComponentProgressing means that the component is reconciled, but not all its pods are ready yet
+	ComponentProgressing ComponentPhase = "Progressing"
+	// ComponentFailed means that the reconciliation of the component failed
+	ComponentFailed ComponentPhase = "Failed"
+	// ComponentSkipped means that the component was not reconciled because one of its dependencies failed
+	ComponentSkipped ComponentPhase = "Skipped"
+	// ComponentDisabled means that the component is not installed
+	ComponentDisabled ComponentPhase = "Disabled"
+)
+
+// ComponentStatus defines the observed state of a single component of PlatformMonitoring
+type ComponentStatus struct {
+	// Phase of the component
+	Phase ComponentPhase `json:"phase"`
+	// Version of the component, taken from the tag of its image
+	// +optional
+	Version string `json:"version,omitempty"`
+	// ReadyReplicas is the number of ready pods of the component workloads
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// DesiredReplicas is the number of desired pods of the component workloads
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	// LastError contains the message of the last reconciliation error of the component
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ManagedResource refers to a cluster-scoped or cross-namespace object created by the operator.
+// Such objects can't be owned by PlatformMonitoring, so the operator deletes them itself
+// when PlatformMonitoring is deleted.
+type ManagedResource struct {
+	// Component which created the object
+	Component string `json:"component"`
+	// APIVersion of the object
+	APIVersion string `json:"apiVersion"`
+	// Kind of the object
+	Kind string `json:"kind"`
+	// Namespace of the object, empty for cluster-scoped objects
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object
+	Name string `json:"name"`
+}
+
+// PlannedChange describes a change of an object which the reconciliation would make
+type PlannedChange struct {
+	ManagedResource `json:",inline"`
+	// Action which the reconciliation would make with the object
+	Action PlanAction `json:"action"`
+	// Fields contains paths of changed fields for updated objects
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
+// PlanStatus describes changes which the reconciliation of the current spec would make
+type PlanStatus struct {
+	// ObservedGeneration is the generation of PlatformMonitoring for which the plan was computed
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastPlanTime is the time when the plan was computed
+	// +optional
+	LastPlanTime metav1.Time `json:"lastPlanTime,omitempty"`
+	// ConfigMap is the name of the ConfigMap with field-level differences of planned changes
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+	// Changes contains objects which would be created, updated or deleted
+	// +optional
+	Changes []PlannedChange `json:"changes,omitempty"`
+	// FailedComponents contains components which failed to compute changes, so the plan is incomplete
+	// +optional
+	FailedComponents []string `json:"failedComponents,omitempty"`
+}
+
+// StorageBackend is a storage of VictoriaMetrics
+// +kubebuilder:validation:Enum=VMSingle;VMCluster
+type StorageBackend string
+
+// Storage backends of VictoriaMetrics
+const (
+	StorageBackendVMSingle  StorageBackend = "VMSingle"
+	StorageBackendVMCluster StorageBackend = "VMCluster"
+)
+
+// StorageMigrationPhase is a phase of the switch between storage backends of VictoriaMetrics
+// +kubebuilder:validation:Enum=InProgress;Completed
+type StorageMigrationPhase string
+
+// Phases of the switch between storage backends of VictoriaMetrics
+const (
+	// StorageMigrationInProgress means that the new backend is not ready yet
+	StorageMigrationInProgress StorageMigrationPhase = "InProgress"
+	// StorageMigrationCompleted means that all workloads of the new backend are ready
+	StorageMigrationCompleted StorageMigrationPhase = "Completed"
+)
+
+// StorageMigration describes the switch between storage backends of VictoriaMetrics
+type StorageMigration struct {
+	// From is the previous storage backend
+	From StorageBackend `json:"from"`
+	// To is the new storage backend
+	To StorageBackend `json:"to"`
+	// Phase of the migration
+	Phase StorageMigrationPhase `json:"phase"`
+	// StartedAt is the time when the change of the backend was observed
+	StartedAt metav1.Time `json:"startedAt"`
+	// CompletedAt is the time when the new backend became ready
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+	// Message describes the state of the new backend
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DataMigrationPhase is a phase of the data migration to the storage of VictoriaMetrics
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type DataMigrationPhase string
+
+// Phases of the data migration to the storage of VictoriaMetrics
+const (
+	// DataMigrationPending means that the Job is not created yet, e.g. the destination is not ready
+	DataMigrationPending DataMigrationPhase = "Pending"
+	// DataMigrationRunning means that the Job is running
+	DataMigrationRunning DataMigrationPhase = "Running"
+	// DataMigrationSucceeded means that the Job completed successfully
+	DataMigrationSucceeded DataMigrationPhase = "Succeeded"
+	// DataMigrationFailed means that the Job failed after all retries
+	DataMigrationFailed DataMigrationPhase = "Failed"
+)
+
+// DataMigrationStatus describes the migration of existing data to the storage of VictoriaMetrics
+type DataMigrationStatus struct {
+	// Source is the storage which data is migrated from
+	Source VmMigrationSource `json:"source"`
+	// Destination is the storage backend which data is migrated to
+	Destination StorageBackend `json:"destination"`
+	// Phase of the migration
+	Phase DataMigrationPhase `json:"phase"`
+	// Job is the name of the vmctl Job
+	Job string `json:"job"`
+	// StartedAt is the time when the Job was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// CompletedAt is the time when the Job succeeded or failed
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+	// Message describes the state of the Job
+	// +optional
+	Message string `json:"message,omitempty"`
+	// SourceDeleted is true if the source was deleted after the migration succeeded
+	// +optional
+	SourceDeleted bool `json:"sourceDeleted,omitempty"`
+}
+
+// BackupPhase is a phase of the last backup of a storage node
+// +kubebuilder:validation:Enum=Running;Succeeded;Failed
+type BackupPhase string
+
+// Phases of the last backup of a storage node
+const (
+	// BackupRunning means that the Job of the backup is running
+	BackupRunning BackupPhase = "Running"
+	// BackupSucceeded means that the Job of the backup completed successfully
+	BackupSucceeded BackupPhase = "Succeeded"
+	// BackupFailed means that the Job of the backup failed after all retries
+	BackupFailed BackupPhase = "Failed"
+)
+
+// BackupStatus describes backups of a storage node of VictoriaMetrics
+type BackupStatus struct {
+	// Target is the backed up storage node, vmsingle or vmstorage-<index>
+	Target string `json:"target"`
+	// CronJob is the name of the vmbackup CronJob
+	CronJob string `json:"cronJob"`
+	// Phase of the last backup. Empty if no backup was started.
+	// +optional
+	Phase BackupPhase `json:"phase,omitempty"`
+	// Generation of the last successful backup
+	// +optional
+	Generation *int32 `json:"generation,omitempty"`
+	// LastScheduleTime is the time when the last backup was started
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessTime is the time when the last successful backup completed
+	// +optional
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+	// Message describes the state of the last backup
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RetentionPolicyStatus describes the retention policy applied to the storage of VictoriaMetrics
+type RetentionPolicyStatus struct {
+	// RetentionFilter is the value of the -retentionFilter flag of the storage
+	// +optional
+	RetentionFilter string `json:"retentionFilter,omitempty"`
+	// DownsamplingPeriod is the value of the -downsampling.period flag of the storage
+	// +optional
+	DownsamplingPeriod string `json:"downsamplingPeriod,omitempty"`
+	// EstimatedDiskUsage is the estimated size of data of all storage nodes including replicated data.
+	// Retention filters are not taken into account, so it is the upper bound.
+	// +optional
+	EstimatedDiskUsage *resource.Quantity `json:"estimatedDiskUsage,omitempty"`
+	// EstimatedDiskUsagePerNode is the estimated size of data of one storage node
+	// +optional
+	EstimatedDiskUsagePerNode *resource.Quantity `json:"estimatedDiskUsagePerNode,omitempty"`
+	// Message describes the estimate, e.g. if it exceeds the requested storage
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// StorageStatus defines the observed state of the storage of VictoriaMetrics
+type StorageStatus struct {
+	// Backend is the storage backend which receives metrics
+	Backend StorageBackend `json:"backend"`
+	// Migration contains the state of the last switch between storage backends
+	// +optional
+	Migration *StorageMigration `json:"migration,omitempty"`
+	// DataMigration contains the state of the migration of existing data with vmctl
+	// +optional
+	DataMigration *DataMigrationStatus `json:"dataMigration,omitempty"`
+	// Backups contains the state of backups of each storage node
+	// +optional
+	Backups []BackupStatus `json:"backups,omitempty"`
+	// RetentionPolicy contains the applied retention policy and the estimated disk usage
+	// +optional
+	RetentionPolicy *RetentionPolicyStatus `json:"retentionPolicy,omitempty"`
 }
 
 // PlatformMonitoringStatus defines the observed state of PlatformMonitoring
 type PlatformMonitoringStatus struct {
-	Conditions []PlatformMonitoringCondition `json:"conditions"`
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+	// ObservedGeneration is the most recent generation of PlatformMonitoring observed by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions contains Available, Progressing, Degraded and RulesValid conditions of PlatformMonitoring
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Components contains the observed state of each component by its name
+	// +optional
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+	// ManagedResources contains cluster-scoped and cross-namespace objects created by the operator
+	// in the order of creation. They are deleted in reverse order when PlatformMonitoring is deleted.
+	// +optional
+	ManagedResources []ManagedResource `json:"managedResources,omitempty"`
+	// Plan contains changes which the reconciliation of the current spec would make.
+	// It is set only while PlatformMonitoring has the PlanAnnotation.
+	// +optional
+	Plan *PlanStatus `json:"plan,omitempty"`
+	// Storage contains the storage backend of VictoriaMetrics and the state of the switch between backends
+	// +optional
+	Storage *StorageStatus `json:"storage,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -1565,6 +2263,33 @@ type Monitor struct {
 type GrafanaDashboards struct {
 	Install *bool    `json:"install,omitempty"`
 	List    []string `json:"list,omitempty"`
+	// Sources contains external sources of dashboards which are installed in addition to dashboards from the List.
+	// Dashboards from sources are rendered as Go templates with {% and %} delimiters like embedded dashboards,
+	// so they can refer to UIDs of other dashboards with DashboardsUIDs.
+	// +optional
+	Sources []DashboardSource `json:"sources,omitempty"`
+}
+
+// DashboardSource is an external source of Grafana dashboards. Only one of ConfigMaps, Path and URL must be set.
+// Files with the .json extension contain dashboard models, files with the .yaml or .yml extension
+// contain GrafanaDashboard manifests. Other files are ignored.
+type DashboardSource struct {
+	// Name of the source, it's added to labels of created GrafanaDashboards
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// ConfigMaps selects ConfigMaps with dashboards in the namespace of PlatformMonitoring by labels.
+	// Each key of a ConfigMap is a file with a dashboard.
+	// +optional
+	ConfigMaps *metav1.LabelSelector `json:"configMaps,omitempty"`
+	// Path is a directory or a tarball (.tar.gz or .tgz) with dashboards in the container of the operator,
+	// e.g. a volume with an OCI artifact or a tarball mounted to the operator.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// URL of a dashboard file or a tarball (.tar.gz or .tgz) with dashboards. Only HTTP servers inside
+	// the cluster are allowed: hosts without dots, Services (*.svc, *.svc.<cluster domain>) or private IP addresses.
+	// +optional
+	URL string `json:"url,omitempty"`
 }
 
 // PrometheusRule handles parameters to override PrometheusRule: alerts of recording rules
@@ -1575,6 +2300,45 @@ type PrometheusRule struct {
 	For      string `json:"for,omitempty"`
 	Expr     string `json:"expr,omitempty"`
 	Severity string `json:"severity,omitempty"`
+	// KeepFiringFor defines how long the alert continues firing after the condition has cleared
+	// +optional
+	KeepFiringFor string `json:"keepFiringFor,omitempty"`
+	// Labels are added to labels of the rule, existing labels are replaced
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to annotations of the alert, existing annotations are replaced, e.g. runbook_url
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Disabled removes the rule from the group
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Debug enables logging of evaluations of the rule by vmalert.
+	// It is ignored if rules are evaluated by Prometheus.
+	// +optional
+	Debug *bool `json:"debug,omitempty"`
+}
+
+// PrometheusRuleGroup overrides parameters of the group of rules and appends custom rules to it
+type PrometheusRuleGroup struct {
+	// Name of the group. If there is no group with this name in rules of the operator, the custom group is added.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Interval of evaluation of rules of the group
+	// +optional
+	Interval *promv1.Duration `json:"interval,omitempty"`
+	// Limit of the number of alerts an alerting rule and series a recording rule of the group can produce.
+	// 0 means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit *int `json:"limit,omitempty"`
+	// Concurrency defines how many rules of the group vmalert evaluates at once.
+	// It is ignored if rules are evaluated by Prometheus.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Concurrency int `json:"concurrency,omitempty"`
+	// Rules are appended to rules of the group
+	// +optional
+	Rules []promv1.Rule `json:"rules,omitempty"`
 }
 
 // PrometheusRules help to add and override Prometheus rules
@@ -1582,6 +2346,10 @@ type PrometheusRules struct {
 	Install    *bool            `json:"install,omitempty"`
 	RuleGroups []string         `json:"ruleGroups,omitempty"`
 	Override   []PrometheusRule `json:"override,omitempty"`
+	// Groups override parameters of groups from ruleGroups and append custom rules to them.
+	// Groups which are not in rules of the operator are added as custom groups.
+	// +optional
+	Groups []PrometheusRuleGroup `json:"groups,omitempty"`
 }
 
 // Promxy handles parameters to set up Platform Monitoring with Prometheus proxy.
@@ -1616,6 +2384,19 @@ func (g Grafana) IsInstall() bool {
 	return true
 }
 
+// FolderOf returns the title of the folder of the dashboard or an empty string
+// if the dashboard is in the General folder
+func (g Grafana) FolderOf(dashboard string) string {
+	for _, folder := range g.Folders {
+		for _, name := range folder.Dashboards {
+			if name == dashboard {
+				return folder.Title
+			}
+		}
+	}
+	return ""
+}
+
 // IsInstall check if Prometheus should be installed
 // Returns false if parameter `install` is false or not set
 func (p Prometheus) IsInstall() bool {
@@ -1779,6 +2560,26 @@ func (vu VmUser) IsInstall() bool {
 	return true
 }
 
+// DefaultBackupGenerations is the number of kept generations of backups if it is not set
+const DefaultBackupGenerations int32 = 3
+
+// GetGenerations returns the number of kept generations of backups
+func (b VmBackup) GetGenerations() int32 {
+	if b.Generations != nil {
+		return *b.Generations
+	}
+	return DefaultBackupGenerations
+}
+
+// ToVolumeSource returns the source of the volume of backups for pods
+func (v VmBackupVolume) ToVolumeSource() v1.VolumeSource {
+	return v1.VolumeSource{
+		HostPath:              v.HostPath.DeepCopy(),
+		PersistentVolumeClaim: v.PersistentVolumeClaim.DeepCopy(),
+		NFS:                   v.NFS.DeepCopy(),
+	}
+}
+
 // IsInstall check if VmCluster should be installed
 // Returns true if parameter `install` is true or not set
 func (vc VmCluster) IsInstall() bool {
@@ -1834,11 +2635,28 @@ func (pr *PrometheusRule) OverridePrometheusRule(rule *promv1.Rule) {
 		rule.Expr = intstr.FromString(pr.Expr)
 	}
 	if pr.For != "" {
-		*rule.For = promv1.Duration(pr.For)
+		d := promv1.Duration(pr.For)
+		rule.For = &d
+	}
+	if pr.KeepFiringFor != "" {
+		d := promv1.NonEmptyDuration(pr.KeepFiringFor)
+		rule.KeepFiringFor = &d
+	}
+	if rule.Labels == nil && (pr.Severity != "" || len(pr.Labels) > 0) {
+		rule.Labels = make(map[string]string, len(pr.Labels)+1)
+	}
+	for k, v := range pr.Labels {
+		rule.Labels[k] = v
 	}
 	if pr.Severity != "" {
 		rule.Labels["severity"] = pr.Severity
 	}
+	if rule.Annotations == nil && len(pr.Annotations) > 0 {
+		rule.Annotations = make(map[string]string, len(pr.Annotations))
+	}
+	for k, v := range pr.Annotations {
+		rule.Annotations[k] = v
+	}
 }
 
 // FillEmptyWithDefaults fill empty fields with default values
@@ -1885,11 +2703,6 @@ func (pm *PlatformMonitoring) FillEmptyWithDefaults() {
 		// If prometheus and VM stack are installed together, consider VM stack as default
 		pm.Spec.Prometheus.Install = ptr.To(false)
 	}
-
-	// if pm.Spec.Victoriametrics != nil && pm.Spec.Victoriametrics.VmCluster.IsInstall() {
-	// 	// Disable VmSingle installation if VMCluster has to be installed
-	// 	pm.Spec.Victoriametrics.VmSingle.Install = ptr.To(false)
-	// }
 }
 
 type PlatformMonitoringTemplatingParameters struct {
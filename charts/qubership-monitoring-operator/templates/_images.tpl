@@ -166,6 +166,34 @@ Image can be found from:
   {{- end -}}
 {{- end -}}
 
+{{/*
+Find a vmbackup image in various places.
+Image can be found from:
+* .Values.victoriametrics.backup.image from values file
+* or default value
+*/}}
+{{- define "vm.backup.image" -}}
+  {{- if .Values.victoriametrics.backup.image -}}
+    {{- printf "%s" .Values.victoriametrics.backup.image -}}
+  {{- else -}}
+    {{- print "docker.io/victoriametrics/vmbackup:v1.130.0" -}}
+  {{- end -}}
+{{- end -}}
+
+{{/*
+Find a vmrestore image in various places.
+Image can be found from:
+* .Values.victoriametrics.backup.restoreImage from values file
+* or default value
+*/}}
+{{- define "vm.restore.image" -}}
+  {{- if .Values.victoriametrics.backup.restoreImage -}}
+    {{- printf "%s" .Values.victoriametrics.backup.restoreImage -}}
+  {{- else -}}
+    {{- print "docker.io/victoriametrics/vmrestore:v1.130.0" -}}
+  {{- end -}}
+{{- end -}}
+
 {{/*
 Find a configmap-reload image in various places.
 Image can be found from:
@@ -292,6 +320,20 @@ Image can be found from:
   {{- end -}}
 {{- end -}}
 
+{{/*
+Find a vmctl image in various places.
+Image can be found from:
+* .Values.victoriametrics.migration.image from values file
+* or default value
+*/}}
+{{- define "vm.ctl.image" -}}
+  {{- if .Values.victoriametrics.migration.image -}}
+    {{- printf "%s" .Values.victoriametrics.migration.image -}}
+  {{- else -}}
+    {{- print "docker.io/victoriametrics/vmctl:v1.130.0" -}}
+  {{- end -}}
+{{- end -}}
+
 {{/*
 Find a configmap-reload image in various places.
 Image can be found from:
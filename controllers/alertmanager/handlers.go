@@ -23,20 +23,7 @@ func (r *AlertManagerReconciler) handleServiceAccount(cr *v1alpha1.PlatformMonit
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.AlertManager.Image)
 
-	e := &corev1.ServiceAccount{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -53,20 +40,7 @@ func (r *AlertManagerReconciler) handleSecret(cr *v1alpha1.PlatformMonitoring) e
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.AlertManager.Image)
 
-	e := &corev1.Secret{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResourceLabels(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -79,21 +53,7 @@ func (r *AlertManagerReconciler) handleAlertmanager(cr *v1alpha1.PlatformMonitor
 		return err
 	}
 
-	e := &promv1.Alertmanager{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -112,22 +72,7 @@ func (r *AlertManagerReconciler) handleService(cr *v1alpha1.PlatformMonitoring)
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.AlertManager.Image)
 
-	e := &corev1.Service{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.Ports = m.Spec.Ports
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -139,22 +84,7 @@ func (r *AlertManagerReconciler) handleIngressV1beta1(cr *v1alpha1.PlatformMonit
 		r.Log.Error(err, "Failed creating Ingress manifest")
 		return err
 	}
-	e := &v1beta1.Ingress{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -166,23 +96,7 @@ func (r *AlertManagerReconciler) handleIngressV1(cr *v1alpha1.PlatformMonitoring
 		r.Log.Error(err, "Failed creating Ingress manifest")
 		return err
 	}
-	e := &networkingv1.Ingress{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 
@@ -202,24 +116,7 @@ func (r *AlertManagerReconciler) handlePodMonitor(cr *v1alpha1.PlatformMonitorin
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.AlertManager.Image)
 
-	e := &promv1.PodMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.PodMetricsEndpoints = m.Spec.PodMetricsEndpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
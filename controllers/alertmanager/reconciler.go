@@ -1,10 +1,13 @@
 package alertmanager
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -12,13 +15,14 @@ type AlertManagerReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewAlertManagerReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *AlertManagerReconciler {
+func NewAlertManagerReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *AlertManagerReconciler {
 	return &AlertManagerReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("alertmanager_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("alertmanager_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -27,7 +31,7 @@ func NewAlertManagerReconciler(c client.Client, s *runtime.Scheme, dc discovery.
 // Creates new deployment, service, service account, cluster role and cluster role binding if its don't exists.
 // Updates deployment and service in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *AlertManagerReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *AlertManagerReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if cr.Spec.AlertManager != nil && cr.Spec.AlertManager.IsInstall() {
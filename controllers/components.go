@@ -0,0 +1,418 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/alertmanager"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/etcd"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/grafana"
+	grafanaoperator "github.com/Netcracker/qubership-monitoring-operator/controllers/grafana-operator"
+	kubernetesmonitors "github.com/Netcracker/qubership-monitoring-operator/controllers/kubernetes-monitors"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/kubestatemetrics"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/nodeexporter"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus"
+	prometheusoperator "github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus-operator"
+	prometheusrules "github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus-rules"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/pushgateway"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmagent"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmalert"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmalertmanager"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmauth"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmbackup"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmcluster"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmmigration"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmoperator"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmsingle"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmuser"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Names of components reconciled by the PlatformMonitoringReconciler
+const (
+	prometheusOperatorComponent = "prometheus-operator"
+	etcdComponent               = "etcd-monitor"
+	kubernetesMonitorsComponent = "kubernetes-monitors"
+	vmOperatorComponent         = "vmoperator"
+	vmSingleComponent           = "vmsingle"
+	vmClusterComponent          = "vmcluster"
+	vmMigrationComponent        = "vmmigration"
+	vmBackupComponent           = "vmbackup"
+	vmUserComponent             = "vmuser"
+	vmAgentComponent            = "vmagent"
+	vmAuthComponent             = "vmauth"
+	prometheusComponent         = "prometheus"
+	vmAlertManagerComponent     = "vmalertmanager"
+	alertManagerComponent       = "alertmanager"
+	vmAlertComponent            = "vmalert"
+	kubeStateMetricsComponent   = "kube-state-metrics"
+	nodeExporterComponent       = "node-exporter"
+	grafanaOperatorComponent    = "grafana-operator"
+	grafanaComponent            = "grafana"
+	prometheusRulesComponent    = "prometheus-rules"
+	pushgatewayComponent        = "pushgateway"
+)
+
+// componentInfo describes how to get the observed state of a component
+type componentInfo struct {
+	// installed returns true if the component must be installed for the custom resource
+	installed func(cr *qubershiporgv1.PlatformMonitoring) bool
+	// image returns the image of the component
+	image func(cr *qubershiporgv1.PlatformMonitoring) string
+	// workloads contains kinds and names of the Deployments, StatefulSets and DaemonSets of the component
+	workloads []workloadRef
+}
+
+// workloadRef refers to a Deployment, StatefulSet or DaemonSet in the namespace of the custom resource
+type workloadRef struct {
+	kind string
+	name string
+}
+
+// componentInfos maps component names to their descriptions.
+// Components which are absent in this map report only the result of the reconciliation.
+var componentInfos = map[string]componentInfo{
+	prometheusOperatorComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Prometheus != nil && cr.Spec.Prometheus.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Prometheus.Operator.Image },
+		workloads: []workloadRef{{"Deployment", utils.PrometheusOperatorComponentName}},
+	},
+	etcdComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return kubernetesmonitors.IsMonitorInstall(cr, utils.EtcdServiceMonitorName)
+		},
+	},
+	kubernetesMonitorsComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return len(cr.Spec.KubernetesMonitors) > 0 || cr.Spec.PublicCloudName != ""
+		},
+	},
+	vmOperatorComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmOperator.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Victoriametrics.VmOperator.Image },
+		workloads: []workloadRef{{"Deployment", utils.VmOperatorComponentName}},
+	},
+	vmSingleComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmSingle.IsInstall() &&
+				!cr.Spec.Victoriametrics.VmCluster.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Victoriametrics.VmSingle.Image },
+		workloads: []workloadRef{{"Deployment", utils.VmSingleServiceName}},
+	},
+	vmClusterComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmCluster.IsInstall() &&
+				!cr.Spec.Victoriametrics.VmSingle.IsInstall()
+		},
+		image: func(cr *qubershiporgv1.PlatformMonitoring) string {
+			return cr.Spec.Victoriametrics.VmCluster.VmStorageImage
+		},
+		workloads: []workloadRef{
+			{"StatefulSet", "vmstorage-" + utils.VmComponentName},
+			{"StatefulSet", "vmselect-" + utils.VmComponentName},
+			{"Deployment", "vminsert-" + utils.VmComponentName},
+		},
+	},
+	vmMigrationComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return victoriametrics.GetMigrationDestination(cr) != ""
+		},
+		image: func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Victoriametrics.Migration.Image },
+	},
+	vmBackupComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return victoriametrics.GetBackup(cr) != nil
+		},
+		image: func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Victoriametrics.Backup.Image },
+	},
+	vmUserComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmUser.IsInstall() &&
+				cr.Spec.Victoriametrics.VmAuth.IsInstall()
+		},
+	},
+	vmAgentComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmAgent.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Victoriametrics.VmAgent.Image },
+		workloads: []workloadRef{{"Deployment", utils.VmAgentServiceName}},
+	},
+	vmAuthComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmAuth.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Victoriametrics.VmAuth.Image },
+		workloads: []workloadRef{{"Deployment", utils.VmAuthServiceName}},
+	},
+	prometheusComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Prometheus != nil && cr.Spec.Prometheus.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Prometheus.Image },
+		workloads: []workloadRef{{"StatefulSet", "prometheus-k8s"}},
+	},
+	vmAlertManagerComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmAlertManager.IsInstall()
+		},
+		image: func(cr *qubershiporgv1.PlatformMonitoring) string {
+			return cr.Spec.Victoriametrics.VmAlertManager.Image
+		},
+		workloads: []workloadRef{{"StatefulSet", utils.VmAlertManagerServiceName}},
+	},
+	alertManagerComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.AlertManager != nil && cr.Spec.AlertManager.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.AlertManager.Image },
+		workloads: []workloadRef{{"StatefulSet", "alertmanager-k8s"}},
+	},
+	vmAlertComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmAlert.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Victoriametrics.VmAlert.Image },
+		workloads: []workloadRef{{"Deployment", utils.VmAlertServiceName}},
+	},
+	kubeStateMetricsComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.KubeStateMetrics != nil && cr.Spec.KubeStateMetrics.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.KubeStateMetrics.Image },
+		workloads: []workloadRef{{"Deployment", utils.KubestatemetricsComponentName}},
+	},
+	nodeExporterComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.NodeExporter != nil && cr.Spec.NodeExporter.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.NodeExporter.Image },
+		workloads: []workloadRef{{"DaemonSet", utils.NodeExporterComponentName}},
+	},
+	grafanaOperatorComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Grafana != nil && cr.Spec.Grafana.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Grafana.Operator.Image },
+		workloads: []workloadRef{{"Deployment", utils.GrafanaOperatorComponentName}},
+	},
+	grafanaComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Grafana != nil && cr.Spec.Grafana.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Grafana.Image },
+		workloads: []workloadRef{{"Deployment", utils.GrafanaDeploymentName}},
+	},
+	prometheusRulesComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.PrometheusRules != nil && cr.Spec.PrometheusRules.IsInstall()
+		},
+	},
+	pushgatewayComponent: {
+		installed: func(cr *qubershiporgv1.PlatformMonitoring) bool {
+			return cr.Spec.Pushgateway != nil && cr.Spec.Pushgateway.IsInstall()
+		},
+		image:     func(cr *qubershiporgv1.PlatformMonitoring) string { return cr.Spec.Pushgateway.Image },
+		workloads: []workloadRef{{"Deployment", utils.PushgatewayComponentName}},
+	},
+}
+
+// componentTimeout returns the default timeout for reconciliation of a single component
+func componentTimeout() time.Duration {
+	seconds, err := strconv.ParseInt(utils.GetEnvWithDefaultValue("COMPONENT_RECONCILIATION_TIMEOUT"), 10, 64)
+	if err != nil || seconds <= 0 {
+		seconds, _ = strconv.ParseInt(utils.GetDefaultEnvValue("COMPONENT_RECONCILIATION_TIMEOUT"), 10, 64)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// componentClient returns the client of the component which records its objects in the tracker.
+// Changes of the component are attributed to it if they are planned.
+// Requests of the client are cancelled when the reconciliation of the component times out.
+func (r *PlatformMonitoringReconciler) componentClient(ctx context.Context, tracker *utils.ResourceTracker, component string) client.Client {
+	c := r.Client
+	if p, ok := c.(*planClient); ok {
+		c = p.forComponent(component)
+	}
+	return tracker.Client(utils.WithContext(ctx, c), component)
+}
+
+// components returns all components of the monitoring stack with their dependencies.
+// Components which don't depend on each other are reconciled concurrently.
+// Cluster-scoped and cross-namespace objects changed by components are recorded in the tracker.
+func (r *PlatformMonitoringReconciler) components(tracker *utils.ResourceTracker) []utils.Component {
+	return []utils.Component{
+		{
+			// Prometheus Operator should be reconciled first because other components create its custom resources:
+			// * Prometheus
+			// * ServiceMonitor
+			// * PodMonitor
+			// * Alertmanager
+			// * PrometheusRule
+			Name: prometheusOperatorComponent,
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return prometheusoperator.NewPrometheusOperatorReconciler(r.componentClient(ctx, tracker, prometheusOperatorComponent), r.Scheme, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      etcdComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return etcd.NewEtcdMonitorReconciler(r.componentClient(ctx, tracker, etcdComponent), r.Scheme, r.DiscoveryClient, r.Config, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      kubernetesMonitorsComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return kubernetesmonitors.NewKubernetesMonitorsReconciler(r.componentClient(ctx, tracker, kubernetesMonitorsComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// Victoriametrics Operator serves all VM* custom resources
+			Name:      vmOperatorComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmoperator.NewVmOperatorReconciler(r.componentClient(ctx, tracker, vmOperatorComponent), r.Scheme, r.Config, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      vmSingleComponent,
+			DependsOn: []string{vmOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmsingle.NewVmSingleReconciler(r.componentClient(ctx, tracker, vmSingleComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      vmClusterComponent,
+			DependsOn: []string{vmOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmcluster.NewVmClusterReconciler(r.componentClient(ctx, tracker, vmClusterComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// VMUser refers to vmsingle or vmcluster as targets
+			Name:      vmUserComponent,
+			DependsOn: []string{vmSingleComponent, vmClusterComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmuser.NewVmUserReconciler(r.componentClient(ctx, tracker, vmUserComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// VMAgent writes metrics to vmsingle or vmcluster
+			Name:      vmAgentComponent,
+			DependsOn: []string{vmSingleComponent, vmClusterComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmagent.NewVmAgentReconciler(r.componentClient(ctx, tracker, vmAgentComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      vmAuthComponent,
+			DependsOn: []string{vmUserComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmauth.NewVmAuthReconciler(r.componentClient(ctx, tracker, vmAuthComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      prometheusComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return prometheus.NewPrometheusReconciler(r.componentClient(ctx, tracker, prometheusComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// The migration reads from vmsingle or Prometheus and writes to vmcluster or vmsingle
+			Name:      vmMigrationComponent,
+			DependsOn: []string{vmSingleComponent, vmClusterComponent, prometheusComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmmigration.NewVmMigrationReconciler(r.componentClient(ctx, tracker, vmMigrationComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// Backups are made from volumes of vmsingle or vmstorage
+			Name:      vmBackupComponent,
+			DependsOn: []string{vmSingleComponent, vmClusterComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmbackup.NewVmBackupReconciler(r.componentClient(ctx, tracker, vmBackupComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      vmAlertManagerComponent,
+			DependsOn: []string{vmOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmalertmanager.NewVmAlertManagerReconciler(r.componentClient(ctx, tracker, vmAlertManagerComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      alertManagerComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return alertmanager.NewAlertManagerReconciler(r.componentClient(ctx, tracker, alertManagerComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// VMAlert reads from vmsingle or vmcluster and sends alerts to vmalertmanager
+			Name:      vmAlertComponent,
+			DependsOn: []string{vmSingleComponent, vmClusterComponent, vmAlertManagerComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return vmalert.NewVmAlertReconciler(r.componentClient(ctx, tracker, vmAlertComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      kubeStateMetricsComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return kubestatemetrics.NewKubeStateMetricsReconciler(r.componentClient(ctx, tracker, kubeStateMetricsComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      nodeExporterComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return nodeexporter.NewNodeExporterReconciler(r.componentClient(ctx, tracker, nodeExporterComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// Grafana Operator serves custom resources:
+			// * Grafana
+			// * GrafanaDatasource
+			// * GrafanaDashboard
+			Name:      grafanaOperatorComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return grafanaoperator.NewGrafanaOperatorReconciler(r.componentClient(ctx, tracker, grafanaOperatorComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      grafanaComponent,
+			DependsOn: []string{grafanaOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return grafana.NewGrafanaReconciler(r.componentClient(ctx, tracker, grafanaComponent), r.Scheme, r.DiscoveryClient, r.Config, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			// Rules are created as PrometheusRules or as VMRules if they are evaluated by vmalert
+			Name:      prometheusRulesComponent,
+			DependsOn: []string{prometheusOperatorComponent, vmOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return prometheusrules.NewPrometheusRulesReconciler(r.componentClient(ctx, tracker, prometheusRulesComponent), r.Scheme, r.Recorder).Run(ctx, cr)
+			},
+		},
+		{
+			Name:      pushgatewayComponent,
+			DependsOn: []string{prometheusOperatorComponent},
+			Run: func(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+				return pushgateway.NewPushgatewayReconciler(r.componentClient(ctx, tracker, pushgatewayComponent), r.Scheme, r.DiscoveryClient, r.Recorder).Run(ctx, cr)
+			},
+		},
+	}
+}
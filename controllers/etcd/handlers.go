@@ -1,15 +1,11 @@
 package etcd
 
 import (
-	"context"
-
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (r *EtcdMonitorReconciler) handleServiceAccount(cr *v1alpha1.PlatformMonitoring) error {
@@ -18,20 +14,7 @@ func (r *EtcdMonitorReconciler) handleServiceAccount(cr *v1alpha1.PlatformMonito
 		r.Log.Error(err, "Failed creating ServiceAccount manifest")
 		return err
 	}
-	e := &corev1.ServiceAccount{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -43,24 +26,7 @@ func (r *EtcdMonitorReconciler) handleServiceMonitor(cr *v1alpha1.PlatformMonito
 		r.Log.Error(err, "Failed creating ServiceMonitor manifest")
 		return err
 	}
-	e := &promv1.ServiceMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.Endpoints = m.Spec.Endpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -72,24 +38,7 @@ func (r *EtcdMonitorReconciler) handleService(cr *v1alpha1.PlatformMonitoring, i
 		r.Log.Error(err, "Failed creating Service manifest")
 		return err
 	}
-	var e *corev1.Service
-	if e, err = r.KubeClient.CoreV1().Services(etcdServiceNamespace).Get(context.TODO(), m.GetName(), metav1.GetOptions{}); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.TypeMeta = m.TypeMeta
-	e.SetLabels(m.GetLabels())
-	e.Spec.Ports = m.Spec.Ports
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -108,20 +57,7 @@ func (r *EtcdMonitorReconciler) handleSecret(cr *v1alpha1.PlatformMonitoring) er
 		m.Labels["app.kubernetes.io/version"] = label
 	}
 
-	e := &corev1.Secret{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResourceLabels(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -21,6 +21,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/remotecommand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -31,7 +32,7 @@ type EtcdMonitorReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewEtcdMonitorReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, r *rest.Config) *EtcdMonitorReconciler {
+func NewEtcdMonitorReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, r *rest.Config, rec record.EventRecorder) *EtcdMonitorReconciler {
 	clientSet, err := kubernetes.NewForConfig(r)
 
 	if err != nil {
@@ -40,10 +41,11 @@ func NewEtcdMonitorReconciler(c client.Client, s *runtime.Scheme, dc discovery.D
 
 	return &EtcdMonitorReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("etcd_monitor_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("etcd_monitor_reconciler"),
+			Recorder: rec,
 		},
 		KubeClient: clientSet,
 		config:     r,
@@ -71,14 +73,6 @@ func (r *EtcdMonitorReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMo
 		etcdServiceNamespace = utils.EtcdServiceComponentNamespaceOpenshiftV4
 	}
 
-	// If monitor should not be installed in public cloud, the rest of this code will not work too
-	affected, installed := kubernetesmonitors.IsMonitorPresentInPublicCloud(cr, utils.EtcdServiceMonitorName)
-	if affected && !installed {
-		r.uninstall(cr, isOpenshift, etcdServiceNamespace, isOpenshiftV4)
-		r.Log.Info("Skip Secret resource handling and certificates updating for public cloud")
-		return nil
-	}
-
 	if len(cr.Spec.KubernetesMonitors) == 0 || !kubernetesmonitors.IsMonitorInstall(cr, utils.EtcdServiceMonitorName) {
 		r.Log.Info("Uninstalling component if exists")
 		r.uninstall(cr, isOpenshift, etcdServiceNamespace, isOpenshiftV4)
@@ -137,7 +131,7 @@ func (r *EtcdMonitorReconciler) uninstall(cr *v1alpha1.PlatformMonitoring, isOpe
 // In any Kubernetes clusters or OpenShift version under 4.5 (OpenShift with version of Kubernetes under 1.18)
 // certificates can be gotten from etcd pods in kube-system namespace
 func (r *EtcdMonitorReconciler) getCertsFromEtcdPods(ctx context.Context, isOpenshift bool, minorServerVersion int) (string, string, string, error) {
-	pods, err := r.KubeClient.CoreV1().Pods(utils.EtcdServiceComponentNamespace).List(context.TODO(), metav1.ListOptions{
+	pods, err := r.KubeClient.CoreV1().Pods(utils.EtcdServiceComponentNamespace).List(ctx, metav1.ListOptions{
 		LabelSelector: utils.EtcdPodLabelSelector,
 	})
 	if err != nil {
@@ -269,8 +263,8 @@ func (r *EtcdMonitorReconciler) getCertsFromEtcdPods(ctx context.Context, isOpen
 	return caFile, certFile, keyFile, nil
 }
 
-func (r *EtcdMonitorReconciler) getCertsFromConfigmapAndSecret() (string, string, string, error) {
-	configMap, err := r.KubeClient.CoreV1().ConfigMaps(utils.EtcdCertificatesSourceNamespaceOpenshiftV4).Get(context.TODO(), utils.EtcdCertificatesSourceConfigmapOpenshiftV4, metav1.GetOptions{})
+func (r *EtcdMonitorReconciler) getCertsFromConfigmapAndSecret(ctx context.Context) (string, string, string, error) {
+	configMap, err := r.KubeClient.CoreV1().ConfigMaps(utils.EtcdCertificatesSourceNamespaceOpenshiftV4).Get(ctx, utils.EtcdCertificatesSourceConfigmapOpenshiftV4, metav1.GetOptions{})
 	if err != nil {
 		if !apierrors.IsForbidden(err) || utils.PrivilegedRights {
 			r.Log.Error(err, fmt.Sprintf("Failed to get configmap %s (namespace: %s) to get etcd certificates", utils.EtcdCertificatesSourceConfigmapOpenshiftV4, utils.EtcdCertificatesSourceNamespaceOpenshiftV4))
@@ -279,7 +273,7 @@ func (r *EtcdMonitorReconciler) getCertsFromConfigmapAndSecret() (string, string
 	}
 	caFile := configMap.Data["ca-bundle.crt"]
 
-	secret, err := r.KubeClient.CoreV1().Secrets(utils.EtcdCertificatesSourceNamespaceOpenshiftV4).Get(context.TODO(), utils.EtcdCertificatesSourceSecretOpenshiftV4, metav1.GetOptions{})
+	secret, err := r.KubeClient.CoreV1().Secrets(utils.EtcdCertificatesSourceNamespaceOpenshiftV4).Get(ctx, utils.EtcdCertificatesSourceSecretOpenshiftV4, metav1.GetOptions{})
 	if err != nil {
 		if !apierrors.IsForbidden(err) || utils.PrivilegedRights {
 			r.Log.Error(err, fmt.Sprintf("Failed to get secret %s (namespace: %s) to get etcd certificates", utils.EtcdCertificatesSourceSecretOpenshiftV4, utils.EtcdCertificatesSourceNamespaceOpenshiftV4))
@@ -301,7 +295,7 @@ func (r *EtcdMonitorReconciler) updateCertificates(ctx context.Context, cr *v1al
 
 	// In OpenShift v4.x we get certificates from configmap and secret instead of pods
 	if isOpenshiftV4 {
-		caFile, certFile, keyFile, err = r.getCertsFromConfigmapAndSecret()
+		caFile, certFile, keyFile, err = r.getCertsFromConfigmapAndSecret(ctx)
 	} else {
 		caFile, certFile, keyFile, err = r.getCertsFromEtcdPods(ctx, isOpenshift, minorServerVersion)
 	}
@@ -313,7 +307,7 @@ func (r *EtcdMonitorReconciler) updateCertificates(ctx context.Context, cr *v1al
 		return err
 	}
 
-	secret, err := r.KubeClient.CoreV1().Secrets(cr.GetNamespace()).Get(context.TODO(), utils.KubeEtcdClientCertsSecretName, metav1.GetOptions{})
+	secret, err := r.KubeClient.CoreV1().Secrets(cr.GetNamespace()).Get(ctx, utils.KubeEtcdClientCertsSecretName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -325,7 +319,11 @@ func (r *EtcdMonitorReconciler) updateCertificates(ctx context.Context, cr *v1al
 	certData["etcd-client.key"] = []byte(keyFile)
 
 	secret.Data = certData
-	_, err = r.KubeClient.CoreV1().Secrets(cr.GetNamespace()).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	opts := metav1.UpdateOptions{}
+	if r.IsDryRun() {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	_, err = r.KubeClient.CoreV1().Secrets(cr.GetNamespace()).Update(ctx, secret, opts)
 
 	if err != nil {
 		return err
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// cleanupFinalizer prevents deletion of PlatformMonitoring until the operator deletes
+// cluster-scoped and cross-namespace objects which are not removed by the garbage collector
+const cleanupFinalizer = "monitoring.qubership.org/cleanup"
+
+// Reasons of the conditions of PlatformMonitoring during deletion
+const (
+	reasonDeleting       = "Deleting"
+	reasonDeletionFailed = "DeletionFailed"
+)
+
+// ensureFinalizer adds the cleanup finalizer to the custom resource instance if it is absent
+func (r *PlatformMonitoringReconciler) ensureFinalizer(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+	if controllerutil.ContainsFinalizer(cr, cleanupFinalizer) {
+		return nil
+	}
+	patch := client.MergeFromWithOptions(cr.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	controllerutil.AddFinalizer(cr, cleanupFinalizer)
+	return r.Client.Patch(ctx, cr, patch)
+}
+
+// finalize deletes managed resources of the custom resource instance in reverse order of their creation
+// and removes the cleanup finalizer when all of them are deleted.
+// Deletion progress is reported in the Progressing condition.
+func (r *PlatformMonitoringReconciler) finalize(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(cr, cleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	r.Log.Info("Deleting managed resources")
+
+	total := len(cr.Status.ManagedResources)
+	setCondition(cr, qubershiporgv1.ConditionProgressing, metav1.ConditionTrue, reasonDeleting,
+		fmt.Sprintf("Deleting %d managed resources", total))
+	if err := r.Client.Status().Update(ctx, cr); err != nil {
+		r.Log.Error(err, "Update status failed")
+	}
+
+	resources := cr.Status.ManagedResources
+	shared, deleteErr := r.sharedResources(ctx, cr)
+	for i := len(resources) - 1; i >= 0 && deleteErr == nil; i-- {
+		if _, ok := shared[sharedKey(resources[i])]; ok {
+			// The object (e.g. the etcd Service in kube-system) is also managed by another instance
+			r.Log.Info("Keep resource managed by another PlatformMonitoring", utils.ResourceKey, resources[i].Kind,
+				"name", managedResourceName(resources[i]))
+			resources = resources[:i]
+			continue
+		}
+		if deleteErr = r.deleteManagedResource(ctx, resources[i]); deleteErr != nil {
+			break
+		}
+		r.event(cr, corev1.EventTypeNormal, "Deleted", fmt.Sprintf("%s: %s %s deleted",
+			resources[i].Component, resources[i].Kind, managedResourceName(resources[i])))
+		resources = resources[:i]
+	}
+	cr.Status.ManagedResources = resources
+	deleted := total - len(resources)
+
+	if deleteErr != nil {
+		setCondition(cr, qubershiporgv1.ConditionProgressing, metav1.ConditionTrue, reasonDeleting,
+			fmt.Sprintf("Deleted %d of %d managed resources", deleted, total))
+		setCondition(cr, qubershiporgv1.ConditionDegraded, metav1.ConditionTrue, reasonDeletionFailed, deleteErr.Error())
+		r.event(cr, corev1.EventTypeWarning, "DeleteFailed", deleteErr.Error())
+		if err := r.Client.Status().Update(ctx, cr); err != nil {
+			r.Log.Error(err, "Update status failed")
+		}
+		return ctrl.Result{}, deleteErr
+	}
+
+	setCondition(cr, qubershiporgv1.ConditionProgressing, metav1.ConditionFalse, reasonDeleting,
+		fmt.Sprintf("Deleted %d of %d managed resources", deleted, total))
+	if err := r.Client.Status().Update(ctx, cr); err != nil {
+		r.Log.Error(err, "Update status failed")
+	}
+
+	patch := client.MergeFromWithOptions(cr.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(cr, cleanupFinalizer)
+	if err := r.Client.Patch(ctx, cr, patch); err != nil {
+		return ctrl.Result{}, err
+	}
+	deleteMetrics(cr)
+	r.Log.Info("Managed resources deleted", "count", deleted)
+	return ctrl.Result{}, nil
+}
+
+// deleteManagedResource deletes the object by reference.
+// Objects which are already absent or whose API is not served anymore are considered deleted.
+func (r *PlatformMonitoringReconciler) deleteManagedResource(ctx context.Context, res qubershiporgv1.ManagedResource) error {
+	gv, err := schema.ParseGroupVersion(res.APIVersion)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gv.WithKind(res.Kind))
+	obj.SetNamespace(res.Namespace)
+	obj.SetName(res.Name)
+	if err = r.Client.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete %s %s: %w", res.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+	r.Log.Info("Successful deleting", utils.ComponentKey, res.Component, utils.ResourceKey, res.Kind, "name", res.Name)
+	return nil
+}
+
+// sharedResources returns objects recorded in the status of other PlatformMonitoring instances
+func (r *PlatformMonitoringReconciler) sharedResources(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) (map[qubershiporgv1.ManagedResource]struct{}, error) {
+	list := &qubershiporgv1.PlatformMonitoringList{}
+	if err := r.Client.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list PlatformMonitoring instances: %w", err)
+	}
+	shared := map[qubershiporgv1.ManagedResource]struct{}{}
+	for _, item := range list.Items {
+		if item.GetNamespace() == cr.GetNamespace() && item.GetName() == cr.GetName() {
+			continue
+		}
+		for _, res := range item.Status.ManagedResources {
+			shared[sharedKey(res)] = struct{}{}
+		}
+	}
+	return shared, nil
+}
+
+// sharedKey returns the reference to the object without the component which manages it
+func sharedKey(res qubershiporgv1.ManagedResource) qubershiporgv1.ManagedResource {
+	res.Component = ""
+	return res
+}
+
+// managedResourceName returns the name of the referenced object with its namespace
+func managedResourceName(res qubershiporgv1.ManagedResource) string {
+	if res.Namespace == "" {
+		return res.Name
+	}
+	return res.Namespace + "/" + res.Name
+}
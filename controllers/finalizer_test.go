@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFinalize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := qubershiporgv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{rbacv1.SchemeGroupVersion, corev1.SchemeGroupVersion})
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+
+	now := metav1.Now()
+	cr := &qubershiporgv1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "platformmonitoring",
+			Namespace:         "monitoring",
+			Finalizers:        []string{cleanupFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Status: qubershiporgv1.PlatformMonitoringStatus{
+			ManagedResources: []qubershiporgv1.ManagedResource{
+				{Component: prometheusOperatorComponent, APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "prometheus-operator"},
+				{Component: etcdComponent, APIVersion: "v1", Kind: "Service", Namespace: "kube-system", Name: "etcd"},
+				{Component: grafanaComponent, APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "already-deleted"},
+			},
+		},
+	}
+	// The other instance manages the same cross-namespace object, so it must be kept
+	other := &qubershiporgv1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "team-a"},
+		Status: qubershiporgv1.PlatformMonitoringStatus{
+			ManagedResources: []qubershiporgv1.ManagedResource{
+				{Component: etcdComponent, APIVersion: "v1", Kind: "Service", Namespace: "kube-system", Name: "etcd-shared"},
+			},
+		},
+	}
+	cr.Status.ManagedResources = append(cr.Status.ManagedResources, qubershiporgv1.ManagedResource{
+		Component: etcdComponent, APIVersion: "v1", Kind: "Service", Namespace: "kube-system", Name: "etcd-shared",
+	})
+	clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "prometheus-operator"}}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "etcd", Namespace: "kube-system"}}
+	sharedService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "etcd-shared", Namespace: "kube-system"}}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(mapper).
+		WithObjects(cr, other, clusterRole, service, sharedService).
+		WithStatusSubresource(cr).
+		Build()
+	r := &PlatformMonitoringReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}
+
+	instance := &qubershiporgv1.PlatformMonitoring{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(cr), instance); err != nil {
+		t.Fatal(err)
+	}
+	_, err := r.finalize(context.Background(), instance)
+	assert.NoError(t, err)
+
+	assert.True(t, errors.IsNotFound(c.Get(context.Background(), client.ObjectKeyFromObject(clusterRole), &rbacv1.ClusterRole{})))
+	assert.True(t, errors.IsNotFound(c.Get(context.Background(), client.ObjectKeyFromObject(service), &corev1.Service{})))
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(sharedService), &corev1.Service{}))
+	assert.Empty(t, instance.Status.ManagedResources)
+	progressing := meta.FindStatusCondition(instance.Status.Conditions, qubershiporgv1.ConditionProgressing)
+	if assert.NotNil(t, progressing) {
+		assert.Equal(t, "Deleted 4 of 4 managed resources", progressing.Message)
+	}
+	// Fake client deletes the object when the last finalizer is removed
+	assert.True(t, errors.IsNotFound(c.Get(context.Background(), client.ObjectKeyFromObject(cr), &qubershiporgv1.PlatformMonitoring{})))
+}
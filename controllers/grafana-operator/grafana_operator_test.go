@@ -111,4 +111,18 @@ func TestGrafanaOperatorManifests(t *testing.T) {
 			assert.NotNil(t, m, "GrafanaDashboard manifest should not be empty")
 		}
 	})
+	t.Run("Test GrafanaDashboard manifest in folder", func(t *testing.T) {
+		folderCR := cr.DeepCopy()
+		folderCR.Spec.Grafana.Folders = []v1alpha1.GrafanaFolder{{Title: "Platform", Dashboards: []string{"govm-processes", "etcd-dashboard"}}}
+		m, err := grafanaDashboard(folderCR, "govm-processes.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "Platform", m.Spec.CustomFolderName)
+		m, err = grafanaDashboard(folderCR, "alerts-overview.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, m.Spec.CustomFolderName)
+	})
 }
@@ -239,23 +239,56 @@ func grafanaOperatorDeployment(cr *v1alpha1.PlatformMonitoring) (*appsv1.Deploym
 }
 
 func grafanaDashboard(cr *v1alpha1.PlatformMonitoring, fileName string) (*grafv1.GrafanaDashboard, error) {
-	dashboard := grafv1.GrafanaDashboard{}
 	fullPath := utils.BasePath + utils.DashboardsFolder + fileName
+	dashboard, err := renderGrafanaDashboard(cr, fullPath, utils.MustAssetReaderToString(assets, fullPath), utils.DashboardsUIDsMap)
+	if err != nil {
+		return nil, err
+	}
+	setGrafanaDashboardFolder(cr, dashboard, strings.TrimSuffix(fileName, ".yaml"))
+	return dashboard, nil
+}
+
+// setGrafanaDashboardFolder moves the dashboard to the folder which contains its name from the list
+// of dashboards or the name of the GrafanaDashboard
+func setGrafanaDashboardFolder(cr *v1alpha1.PlatformMonitoring, dashboard *grafv1.GrafanaDashboard, name string) {
+	if cr.Spec.Grafana == nil {
+		return
+	}
+	folder := cr.Spec.Grafana.FolderOf(name)
+	if folder == "" {
+		folder = cr.Spec.Grafana.FolderOf(dashboard.GetName())
+	}
+	dashboard.Spec.CustomFolderName = folder
+}
+
+// renderGrafanaDashboard renders the template of the GrafanaDashboard manifest with parameters of the custom resource
+// and UIDs of dashboards
+func renderGrafanaDashboard(cr *v1alpha1.PlatformMonitoring, filePath, content string, uids map[string]string) (*grafv1.GrafanaDashboard, error) {
+	dashboard := grafv1.GrafanaDashboard{}
 	crParams := cr.ToParams()
 	// Add a map contains human-readable UIDs for Grafana dashboards to the current Custom Resource
-	crParams.DashboardsUIDs = utils.DashboardsUIDsMap
-	fileContent, err := utils.ParseTemplate(utils.MustAssetReaderToString(assets, fullPath), fullPath, utils.DashboardTemplateLeftDelim, utils.DashboardTemplateRightDelim, crParams)
+	crParams.DashboardsUIDs = uids
+	fileContent, err := utils.ParseTemplate(content, filePath, utils.DashboardTemplateLeftDelim, utils.DashboardTemplateRightDelim, crParams)
 	if err != nil {
 		return nil, err
 	}
 	if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(fileContent), 100).Decode(&dashboard); err != nil {
 		return nil, err
 	}
+	setGrafanaDashboardParameters(cr, &dashboard)
+	return &dashboard, nil
+}
+
+// setGrafanaDashboardParameters sets the namespace and labels of the GrafanaDashboard
+func setGrafanaDashboardParameters(cr *v1alpha1.PlatformMonitoring, dashboard *grafv1.GrafanaDashboard) {
 	//Set parameters
 	dashboard.SetGroupVersionKind(schema.GroupVersionKind{Group: "integreatly.org", Version: "v1alpha1", Kind: "Grafana"})
 	dashboard.SetNamespace(cr.GetNamespace())
 
 	// Set labels
+	if dashboard.Labels == nil {
+		dashboard.Labels = map[string]string{}
+	}
 	dashboard.Labels["name"] = utils.TruncLabel(dashboard.GetName())
 	dashboard.Labels["app.kubernetes.io/name"] = utils.TruncLabel(dashboard.GetName())
 	dashboard.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(dashboard.GetName(), dashboard.GetNamespace())
@@ -264,8 +297,6 @@ func grafanaDashboard(cr *v1alpha1.PlatformMonitoring, fileName string) (*grafv1
 	if cr.Spec.Grafana != nil {
 		dashboard.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Grafana.Operator.Image)
 	}
-
-	return &dashboard, nil
 }
 
 func grafanaOperatorPodMonitor(cr *v1alpha1.PlatformMonitoring) (*promv1.PodMonitor, error) {
@@ -1,11 +1,14 @@
 package grafana_operator
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	kubernetes_monitors "github.com/Netcracker/qubership-monitoring-operator/controllers/kubernetes-monitors"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -13,13 +16,14 @@ type GrafanaOperatorReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewGrafanaOperatorReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *GrafanaOperatorReconciler {
+func NewGrafanaOperatorReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *GrafanaOperatorReconciler {
 	return &GrafanaOperatorReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("grafanaoperator_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("grafanaoperator_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -28,23 +32,12 @@ func NewGrafanaOperatorReconciler(c client.Client, s *runtime.Scheme, dc discove
 // Creates new cluster role, cluster role binding and grafana custom resource if its don't exists.
 // Updates deployment and service in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *GrafanaOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *GrafanaOperatorReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
-	restrictedDashboards := make(map[string]bool)
-	if cr.Spec.PublicCloudName != "" {
-		if pcMap, ok := utils.PublicCloudDashboardsEnabled[cr.Spec.PublicCloudName]; ok {
-			for dashboard, included := range pcMap {
-				if included {
-					cr.Spec.GrafanaDashboards.List = append(cr.Spec.GrafanaDashboards.List, dashboard)
-				} else {
-					restrictedDashboards[dashboard] = true
-				}
-			}
-		}
-	}
 	// Create dashboards after we have created CRD-s
-	if cr.Spec.GrafanaDashboards != nil && cr.Spec.GrafanaDashboards.IsInstall() && len(cr.Spec.GrafanaDashboards.List) > 0 {
+	if cr.Spec.GrafanaDashboards != nil && cr.Spec.GrafanaDashboards.IsInstall() &&
+		(len(cr.Spec.GrafanaDashboards.List) > 0 || len(cr.Spec.GrafanaDashboards.Sources) > 0) {
 
 		// Nginx dashboards should be installed only if NginxIngressPodMonitor is installed
 		isNginxIngressPodMonitorInstalled := isMonitorInstall(cr, utils.NginxIngressPodMonitorName)
@@ -68,11 +61,6 @@ func (r *GrafanaOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 				cr.Spec.GrafanaDashboards.List = append(cr.Spec.GrafanaDashboards.List, "victoriametrics-vmsingle")
 			}
 		}
-		// Pod distribution by zones dashboard
-		if cr.Spec.PublicCloudName != "" && cr.Spec.PublicCloudName == "aws" {
-			cr.Spec.GrafanaDashboards.List = append(cr.Spec.GrafanaDashboards.List, "kubernetes-pods-distribution-by-zone")
-		}
-
 		isOpenshiftV4, err := r.IsOpenShiftV4()
 		if err != nil {
 			r.Log.Error(err, "Failed to recognize OpenShift V4")
@@ -98,13 +86,6 @@ func (r *GrafanaOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 
 		for _, mResource := range utils.GrafanaKubernetesDashboardsResources {
 			if _, ok := dashboardsToInstall[mResource]; ok {
-				// Dashboards that should not be in the public cloud will be skipped
-				if restrictedDashboards[dashboardsToInstall[mResource]] {
-					if err = r.deleteGrafanaDashboard(mResource, cr); err != nil {
-						r.Log.Error(err, "Can not delete GrafanaDashboard")
-					}
-					continue
-				}
 				// Create node-exporter dashboard only if we install it
 				if mResource == utils.GrafanaNodeExporterDashboardResource && (cr.Spec.NodeExporter == nil || !cr.Spec.NodeExporter.IsInstall()) {
 					r.Log.Info("Delete dashboard " + utils.GrafanaNodeExporterDashboardResource + " if exists and NodeExporter is not installed")
@@ -214,9 +195,14 @@ func (r *GrafanaOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 				}
 			}
 		}
+
+		// Create dashboards from external sources
+		if err = r.handleExternalDashboards(ctx, cr); err != nil {
+			return err
+		}
 	} else {
 		r.Log.Info("Remove all grafana dashboards")
-		r.uninstallGrafanaDashboards(cr)
+		r.uninstallGrafanaDashboards(ctx, cr)
 	}
 	if cr.Spec.Grafana != nil && cr.Spec.Grafana.IsInstall() {
 		if !cr.Spec.Grafana.Operator.Paused {
@@ -270,12 +256,15 @@ func (r *GrafanaOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 	return nil
 }
 
-func (r *GrafanaOperatorReconciler) uninstallGrafanaDashboards(cr *v1alpha1.PlatformMonitoring) {
+func (r *GrafanaOperatorReconciler) uninstallGrafanaDashboards(ctx context.Context, cr *v1alpha1.PlatformMonitoring) {
 	for _, mResource := range utils.GrafanaKubernetesDashboardsResources {
 		if err := r.deleteGrafanaDashboard(mResource, cr); err != nil {
 			r.Log.Error(err, "Can not delete GrafanaDashboard")
 		}
 	}
+	if err := r.deleteExternalDashboards(ctx, cr); err != nil {
+		r.Log.Error(err, "Can not delete GrafanaDashboards from external sources")
+	}
 }
 
 // uninstall deletes all resources related to the component
@@ -290,15 +279,5 @@ func (r *GrafanaOperatorReconciler) uninstall(cr *v1alpha1.PlatformMonitoring) {
 
 // isMonitorInstall returns "true" if the monitor installed
 func isMonitorInstall(cr *v1alpha1.PlatformMonitoring, monitorName string) bool {
-	// If the monitor affected by installation in the public cloud, cr.Spec.KubernetesMonitors doesn't matter
-	affected, installed := kubernetes_monitors.IsMonitorPresentInPublicCloud(cr, monitorName)
-	if affected {
-		return installed
-	} else if len(cr.Spec.KubernetesMonitors) > 0 {
-		monitor, ok := cr.Spec.KubernetesMonitors[monitorName]
-		if ok && monitor.IsInstall() {
-			return true
-		}
-	}
-	return false
+	return kubernetes_monitors.IsMonitorInstall(cr, monitorName)
 }
@@ -0,0 +1,413 @@
+package grafana_operator
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	grafv1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DashboardSourceLabel contains the name of the external source of the GrafanaDashboard
+	DashboardSourceLabel = "monitoring.qubership.org/dashboard-source"
+
+	// maxDashboardSourceSize limits the total size of files read from an external source,
+	// for tarballs the size of extracted files is limited
+	maxDashboardSourceSize = 64 << 20
+	// dashboardSourceTimeout limits the time to download dashboards from the URL
+	dashboardSourceTimeout = 30 * time.Second
+	// maxDashboardSourceRedirects limits the number of redirects to follow when dashboards are downloaded
+	maxDashboardSourceRedirects = 10
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// dashboardSourceClient downloads dashboards from URLs. Each redirect is checked as the URL of the source,
+// so the server inside the cluster can't redirect the operator to the internet.
+var dashboardSourceClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxDashboardSourceRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxDashboardSourceRedirects)
+		}
+		if err := utils.ValidateClusterLocalURL(req.URL.String()); err != nil {
+			return fmt.Errorf("redirect to %s is not allowed: %w", req.URL.Redacted(), err)
+		}
+		return nil
+	},
+}
+
+// sizeLimit tracks the size of files read from the external source
+type sizeLimit struct {
+	remaining int64
+}
+
+func newSizeLimit() *sizeLimit {
+	return &sizeLimit{remaining: maxDashboardSourceSize}
+}
+
+// read returns the content of the reader or an error if the total size of read files exceeds the limit
+func (l *sizeLimit) read(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, l.remaining+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > l.remaining {
+		return nil, fmt.Errorf("dashboard source exceeds the size limit of %d bytes", maxDashboardSourceSize)
+	}
+	l.remaining -= int64(len(content))
+	return content, nil
+}
+
+// dashboardFile is a file with a dashboard read from the external source
+type dashboardFile struct {
+	name    string
+	content []byte
+}
+
+// readDashboardSource returns files with dashboards from the external source sorted by names
+func (r *GrafanaOperatorReconciler) readDashboardSource(ctx context.Context, cr *v1alpha1.PlatformMonitoring, source v1alpha1.DashboardSource) ([]dashboardFile, error) {
+	var files []dashboardFile
+	var err error
+	switch {
+	case source.ConfigMaps != nil:
+		files, err = r.readConfigMaps(ctx, cr, source.ConfigMaps)
+	case source.Path != "":
+		files, err = readPath(source.Path, newSizeLimit())
+	case source.URL != "":
+		files, err = readURL(ctx, source.URL, newSizeLimit())
+	default:
+		return nil, fmt.Errorf("one of configMaps, path or url must be set")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var dashboards []dashboardFile
+	for _, file := range files {
+		if isDashboardFile(file.name) {
+			dashboards = append(dashboards, file)
+		}
+	}
+	sort.SliceStable(dashboards, func(i, j int) bool { return dashboards[i].name < dashboards[j].name })
+	return dashboards, nil
+}
+
+// readConfigMaps returns data of ConfigMaps selected by labels in the namespace of the custom resource
+func (r *GrafanaOperatorReconciler) readConfigMaps(ctx context.Context, cr *v1alpha1.PlatformMonitoring, selector *metav1.LabelSelector) ([]dashboardFile, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	list := &corev1.ConfigMapList{}
+	if err = r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()), client.MatchingLabelsSelector{Selector: s}); err != nil {
+		return nil, err
+	}
+	var files []dashboardFile
+	for _, cm := range list.Items {
+		for key, value := range cm.Data {
+			files = append(files, dashboardFile{name: key, content: []byte(value)})
+		}
+		for key, value := range cm.BinaryData {
+			files = append(files, dashboardFile{name: key, content: value})
+		}
+	}
+	return files, nil
+}
+
+// readPath returns files from the directory or the tarball
+func readPath(path string, limit *sizeLimit) ([]dashboardFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if isTarball(path) {
+			return readTarball(io.LimitReader(f, maxDashboardSourceSize), limit)
+		}
+		content, err := limit.read(f)
+		if err != nil {
+			return nil, err
+		}
+		return []dashboardFile{{name: filepath.Base(path), content: content}}, nil
+	}
+	var files []dashboardFile
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Skip hidden directories, e.g. ..data of mounted ConfigMaps, files are available by symlinks
+		if d.IsDir() {
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") || !isDashboardFile(d.Name()) {
+			return nil
+		}
+		content, err := readFile(p, limit)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, dashboardFile{name: filepath.ToSlash(rel), content: content})
+		return nil
+	})
+	return files, err
+}
+
+func readFile(path string, limit *sizeLimit) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return limit.read(f)
+}
+
+// readURL downloads the file or the tarball from the HTTP server inside the cluster
+func readURL(ctx context.Context, url string, limit *sizeLimit) ([]dashboardFile, error) {
+	if err := utils.ValidateClusterLocalURL(url); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, dashboardSourceTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dashboardSourceClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s of %s", resp.Status, url)
+	}
+	name := filepath.Base(req.URL.Path)
+	if isTarball(name) {
+		return readTarball(io.LimitReader(resp.Body, maxDashboardSourceSize), limit)
+	}
+	content, err := limit.read(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return []dashboardFile{{name: name, content: content}}, nil
+}
+
+// readTarball returns regular files from the gzipped tarball
+func readTarball(r io.Reader, limit *sizeLimit) ([]dashboardFile, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	var files []dashboardFile
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || !isDashboardFile(header.Name) {
+			continue
+		}
+		content, err := limit.read(tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dashboardFile{name: strings.TrimPrefix(header.Name, "./"), content: content})
+	}
+}
+
+func isTarball(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+func isDashboardFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// dashboardName returns the name of the GrafanaDashboard for the file of the source
+func dashboardName(source, file string) string {
+	base := strings.ToLower(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+	name := strings.Trim(invalidNameChars.ReplaceAllString(source+"-"+base, "-"), "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// externalGrafanaDashboard renders the GrafanaDashboard from the file of the external source.
+// Dashboard models from .json files are wrapped into GrafanaDashboard manifests.
+func externalGrafanaDashboard(cr *v1alpha1.PlatformMonitoring, source string, file dashboardFile, uids map[string]string) (*grafv1.GrafanaDashboard, error) {
+	filePath := source + "/" + file.name
+	if filepath.Ext(file.name) != ".json" {
+		dashboard, err := renderGrafanaDashboard(cr, filePath, string(file.content), uids)
+		if err != nil {
+			return nil, err
+		}
+		setDashboardSourceLabel(dashboard, source)
+		setGrafanaDashboardFolder(cr, dashboard, dashboard.GetName())
+		return dashboard, nil
+	}
+	crParams := cr.ToParams()
+	crParams.DashboardsUIDs = uids
+	content, err := utils.ParseTemplate(string(file.content), filePath, utils.DashboardTemplateLeftDelim, utils.DashboardTemplateRightDelim, crParams)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid([]byte(content)) {
+		return nil, fmt.Errorf("the dashboard %s is not a valid JSON", filePath)
+	}
+	dashboard := &grafv1.GrafanaDashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: dashboardName(source, file.name)},
+		Spec:       grafv1.GrafanaDashboardSpec{Json: content},
+	}
+	setGrafanaDashboardParameters(cr, dashboard)
+	dashboard.Labels["app.kubernetes.io/component"] = "monitoring"
+	setDashboardSourceLabel(dashboard, source)
+	setGrafanaDashboardFolder(cr, dashboard, dashboard.GetName())
+	return dashboard, nil
+}
+
+func setDashboardSourceLabel(dashboard *grafv1.GrafanaDashboard, source string) {
+	dashboard.Labels[DashboardSourceLabel] = source
+}
+
+// dashboardsUIDs returns UIDs of embedded dashboards and dashboards from external sources.
+// UIDs of external dashboards are names of GrafanaDashboards created for them.
+func dashboardsUIDs(files map[string][]dashboardFile) map[string]string {
+	uids := make(map[string]string, len(utils.DashboardsUIDsMap))
+	for k, v := range utils.DashboardsUIDsMap {
+		uids[k] = v
+	}
+	for source, sourceFiles := range files {
+		for _, file := range sourceFiles {
+			name := dashboardName(source, file.name)
+			if _, ok := uids[name]; !ok {
+				uids[name] = name
+			}
+		}
+	}
+	return uids
+}
+
+// handleExternalDashboards creates GrafanaDashboards from external sources and removes GrafanaDashboards
+// of files which are no longer in sources. GrafanaDashboards of sources which failed to read are kept.
+func (r *GrafanaOperatorReconciler) handleExternalDashboards(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	var errs []error
+	failedSources := make(map[string]bool)
+	files := make(map[string][]dashboardFile)
+	for _, source := range cr.Spec.GrafanaDashboards.Sources {
+		sourceFiles, err := r.readDashboardSource(ctx, cr, source)
+		if err != nil {
+			r.Log.Error(err, "Failed reading dashboards from the source", "source", source.Name)
+			errs = append(errs, fmt.Errorf("dashboard source %s: %w", source.Name, err))
+			failedSources[source.Name] = true
+			continue
+		}
+		files[source.Name] = sourceFiles
+	}
+
+	uids := dashboardsUIDs(files)
+	installed := make(map[string]string)
+	for _, source := range cr.Spec.GrafanaDashboards.Sources {
+		for _, file := range files[source.Name] {
+			m, err := externalGrafanaDashboard(cr, source.Name, file, uids)
+			if err != nil {
+				r.Log.Error(err, "Failed creating GrafanaDashboard manifest", "source", source.Name, "file", file.name)
+				errs = append(errs, fmt.Errorf("dashboard source %s: %w", source.Name, err))
+				failedSources[source.Name] = true
+				continue
+			}
+			if other, ok := installed[m.GetName()]; ok {
+				errs = append(errs, fmt.Errorf("dashboard source %s: GrafanaDashboard %s is already created from the source %s",
+					source.Name, m.GetName(), other))
+				failedSources[source.Name] = true
+				continue
+			}
+			// GrafanaDashboards created from embedded assets or by users must not be overwritten
+			existing := &grafv1.GrafanaDashboard{ObjectMeta: metav1.ObjectMeta{Name: m.GetName(), Namespace: m.GetNamespace()}}
+			if err = r.GetResource(existing); err == nil && existing.Labels[DashboardSourceLabel] == "" {
+				errs = append(errs, fmt.Errorf("dashboard source %s: GrafanaDashboard %s is not created from sources",
+					source.Name, m.GetName()))
+				failedSources[source.Name] = true
+				continue
+			} else if err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			}
+			installed[m.GetName()] = source.Name
+			if err = r.ApplyResource(cr, m); err != nil {
+				errs = append(errs, fmt.Errorf("dashboard source %s: %w", source.Name, err))
+			}
+		}
+	}
+
+	// Remove GrafanaDashboards of removed files and sources
+	list := &grafv1.GrafanaDashboardList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()), client.HasLabels{DashboardSourceLabel}); err != nil {
+		errs = append(errs, err)
+		return errors.Join(errs...)
+	}
+	for i := range list.Items {
+		dashboard := &list.Items[i]
+		if _, ok := installed[dashboard.GetName()]; ok || failedSources[dashboard.Labels[DashboardSourceLabel]] {
+			continue
+		}
+		r.Log.Info("Delete GrafanaDashboard which is no longer in sources", "name", dashboard.GetName())
+		if err := r.DeleteResource(dashboard); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deleteExternalDashboards removes all GrafanaDashboards created from external sources
+func (r *GrafanaOperatorReconciler) deleteExternalDashboards(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	list := &grafv1.GrafanaDashboardList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()), client.HasLabels{DashboardSourceLabel}); err != nil {
+		return err
+	}
+	var errs []error
+	for i := range list.Items {
+		if err := r.DeleteResource(&list.Items[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
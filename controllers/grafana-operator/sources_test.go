@@ -0,0 +1,186 @@
+package grafana_operator
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	grafv1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const externalDashboardJSON = `{"title": "Team A", "links": [{"url": "/d/{% index .DashboardsUIDs "etcd-dashboard" %}"}]}`
+
+func newTarball(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadPath(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "..data"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "..data", "hidden.json"), []byte("{}"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "team-a.json"), []byte("{}"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Dashboards"), 0644))
+
+	files, err := readPath(dir, newSizeLimit())
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "team-a.json", files[0].name)
+	}
+
+	tarball := filepath.Join(dir, "dashboards.tar.gz")
+	assert.NoError(t, os.WriteFile(tarball, newTarball(t, map[string]string{
+		"./team-a.json":     "{}",
+		"nested/team-b.yml": "kind: GrafanaDashboard",
+		"LICENSE":           "Apache",
+	}), 0644))
+	files, err = readPath(tarball, newSizeLimit())
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.name)
+	}
+	assert.ElementsMatch(t, []string{"team-a.json", "nested/team-b.yml"}, names)
+
+	// The size of extracted files is limited in total
+	_, err = readPath(tarball, &sizeLimit{remaining: 10})
+	assert.ErrorContains(t, err, "exceeds the size limit")
+	_, err = readPath(dir, &sizeLimit{remaining: 1})
+	assert.ErrorContains(t, err, "exceeds the size limit")
+}
+
+func TestReadURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/team-a.json":
+			_, _ = w.Write([]byte(`{"title": "Team A"}`))
+		case "/moved.json":
+			http.Redirect(w, r, "/team-a.json", http.StatusFound)
+		case "/external.json":
+			http.Redirect(w, r, "http://dashboards.example.com/team-a.json", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	files, err := readURL(context.Background(), server.URL+"/moved.json", newSizeLimit())
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, `{"title": "Team A"}`, string(files[0].content))
+	}
+
+	_, err = readURL(context.Background(), server.URL+"/external.json", newSizeLimit())
+	assert.ErrorContains(t, err, "redirect to http://dashboards.example.com")
+
+	_, err = readURL(context.Background(), server.URL+"/team-a.json", &sizeLimit{remaining: 5})
+	assert.ErrorContains(t, err, "exceeds the size limit")
+}
+
+func TestDashboardName(t *testing.T) {
+	assert.Equal(t, "team-a-node-overview", dashboardName("team-a", "nested/Node_Overview.json"))
+	assert.Len(t, dashboardName("team-a", "a-very-long-name-of-the-dashboard-which-does-not-fit-into-the-label.json"), 63)
+}
+
+func TestExternalGrafanaDashboard(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"}}
+	uids := dashboardsUIDs(map[string][]dashboardFile{"team-a": {{name: "overview.json"}}})
+	assert.Equal(t, "team-a-overview", uids["team-a-overview"])
+
+	m, err := externalGrafanaDashboard(cr, "team-a", dashboardFile{name: "overview.json", content: []byte(externalDashboardJSON)}, uids)
+	assert.NoError(t, err)
+	assert.Equal(t, "team-a-overview", m.GetName())
+	assert.Equal(t, "monitoring", m.GetNamespace())
+	assert.Equal(t, "team-a", m.Labels[DashboardSourceLabel])
+	assert.Contains(t, m.Spec.Json, `"/d/etcd"`)
+
+	_, err = externalGrafanaDashboard(cr, "team-a", dashboardFile{name: "broken.json", content: []byte(`{"title": `)}, uids)
+	assert.Error(t, err)
+
+	manifest := `apiVersion: integreatly.org/v1alpha1
+kind: GrafanaDashboard
+metadata:
+  name: team-a-custom
+spec:
+  json: >
+    {"uid": "{% index .DashboardsUIDs "team-a-overview" %}"}
+`
+	m, err = externalGrafanaDashboard(cr, "team-a", dashboardFile{name: "custom.yaml", content: []byte(manifest)}, uids)
+	assert.NoError(t, err)
+	assert.Equal(t, "team-a-custom", m.GetName())
+	assert.Equal(t, "team-a", m.Labels[DashboardSourceLabel])
+	assert.Contains(t, m.Spec.Json, `"team-a-overview"`)
+}
+
+func TestHandleExternalDashboards(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, grafv1.AddToScheme(scheme))
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a-dashboards", Namespace: "monitoring", Labels: map[string]string{"dashboards": "team-a"}},
+		Data:       map[string]string{"overview.json": externalDashboardJSON, "notes.txt": "ignored"},
+	}
+	other := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b-dashboards", Namespace: "monitoring", Labels: map[string]string{"dashboards": "team-b"}},
+		Data:       map[string]string{"overview.json": "{}"},
+	}
+	stale := &grafv1.GrafanaDashboard{ObjectMeta: metav1.ObjectMeta{Name: "team-a-removed", Namespace: "monitoring",
+		Labels: map[string]string{DashboardSourceLabel: "team-a"}}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm, other, stale).Build()
+	r := &GrafanaOperatorReconciler{ComponentReconciler: &utils.ComponentReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}}
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring", UID: "uid"},
+		Spec: v1alpha1.PlatformMonitoringSpec{GrafanaDashboards: &v1alpha1.GrafanaDashboards{Sources: []v1alpha1.DashboardSource{
+			{Name: "team-a", ConfigMaps: &metav1.LabelSelector{MatchLabels: map[string]string{"dashboards": "team-a"}}},
+		}}},
+	}
+
+	files, err := r.readDashboardSource(context.Background(), cr, cr.Spec.GrafanaDashboards.Sources[0])
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "overview.json", files[0].name)
+	}
+
+	// The fake client doesn't support server-side apply, so only removal of stale dashboards is checked
+	_ = r.handleExternalDashboards(context.Background(), cr)
+	list := &grafv1.GrafanaDashboardList{}
+	assert.NoError(t, c.List(context.Background(), list, client.HasLabels{DashboardSourceLabel}))
+	assert.Empty(t, list.Items)
+
+	assert.NoError(t, c.Create(context.Background(), &grafv1.GrafanaDashboard{ObjectMeta: metav1.ObjectMeta{
+		Name: "team-a-overview", Namespace: "monitoring", Labels: map[string]string{DashboardSourceLabel: "team-a"}}}))
+	assert.NoError(t, r.deleteExternalDashboards(context.Background(), cr))
+	assert.NoError(t, c.List(context.Background(), list, client.HasLabels{DashboardSourceLabel}))
+	assert.Empty(t, list.Items)
+}
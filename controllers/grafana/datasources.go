@@ -0,0 +1,367 @@
+package grafana
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	grafv1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// datasourceTypes contains types of Grafana plugins for types of datasources in the catalogue
+var datasourceTypes = map[v1alpha1.GrafanaDatasourceType]string{
+	v1alpha1.GrafanaDatasourcePrometheus:    "prometheus",
+	v1alpha1.GrafanaDatasourceLoki:          "loki",
+	v1alpha1.GrafanaDatasourceTempo:         "tempo",
+	v1alpha1.GrafanaDatasourceElasticsearch: "elasticsearch",
+	v1alpha1.GrafanaDatasourceOpenSearch:    "grafana-opensearch-datasource",
+	v1alpha1.GrafanaDatasourceAlertmanager:  "alertmanager",
+}
+
+// credentialsEnvPrefix is the prefix of environment variables of Grafana with credentials of datasources
+const credentialsEnvPrefix = "DATASOURCE_"
+
+// DatasourceCredentialsHashAnnotation contains the hash of credentials of datasources.
+// Grafana reads environment variables only on start, so it is restarted when the hash is changed.
+const DatasourceCredentialsHashAnnotation = "monitoring.qubership.org/datasource-credentials-hash"
+
+var envNameInvalidChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// secretResolver returns the value which is set to the datasource for the key of the Secret in the namespace
+type secretResolver func(namespace string, selector *corev1.SecretKeySelector) (string, error)
+
+// datasourceCredentials collects values of Secrets used by datasources by names of environment variables.
+// Values are stored in grafana-extra-vars-secret, which is mounted to Grafana as environment variables,
+// and datasources refer to them with $__env{}, so GrafanaDataSource doesn't contain credentials.
+type datasourceCredentials map[string]string
+
+// reference stores the value of the key of the Secret and returns the reference to the environment variable
+func (c datasourceCredentials) reference(namespace string, selector *corev1.SecretKeySelector, value string) string {
+	name := credentialsEnvName(namespace, selector)
+	c[name] = value
+	return "$__env{" + name + "}"
+}
+
+// hash returns the hash of all credentials or the empty string if there are no credentials
+func (c datasourceCredentials) hash() string {
+	if len(c) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, c[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// credentialsEnvName returns the name of the environment variable for the key of the Secret in the namespace.
+// Invalid characters are replaced with "_", so different keys can get the same name, e.g. a-b/c:key and a/b-c:key.
+// The short hash of the namespace, the Secret and the key is appended to keep names unique.
+func credentialsEnvName(namespace string, selector *corev1.SecretKeySelector) string {
+	name := strings.ToUpper(strings.Join([]string{namespace, selector.Name, selector.Key}, "_"))
+	sum := sha256.Sum256([]byte(namespace + "/" + selector.Name + ":" + selector.Key))
+	return credentialsEnvPrefix + envNameInvalidChars.ReplaceAllString(name, "_") + "_" +
+		strings.ToUpper(hex.EncodeToString(sum[:4]))
+}
+
+// clickHouseSecretKey returns the key of the Secret with credentials of ClickHouse
+func clickHouseSecretKey(key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: utils.ClickHouseSecret}, Key: key}
+}
+
+// datasourceTarget is the name and the URL of the datasource created from the entry of the catalogue
+type datasourceTarget struct {
+	name string
+	uid  string
+	url  string
+}
+
+// catalogueDataSources returns datasources from grafana.datasources.
+// Services are discovered by labels and credentials are resolved from Secrets in the namespace of the custom resource.
+func (r *GrafanaReconciler) catalogueDataSources(ctx context.Context, cr *v1alpha1.PlatformMonitoring, resolve secretResolver) ([]grafv1.GrafanaDataSourceFields, error) {
+	if cr.Spec.Grafana == nil || len(cr.Spec.Grafana.Datasources) == 0 {
+		return nil, nil
+	}
+	interval := datasourceInterval(cr)
+	var datasources []grafv1.GrafanaDataSourceFields
+	for _, ds := range cr.Spec.Grafana.Datasources {
+		targets := []datasourceTarget{{name: ds.Name, uid: ds.UID, url: ds.URL}}
+		if ds.Discovery != nil {
+			services, err := r.discoverDatasourceServices(ctx, cr, ds.Discovery)
+			if err != nil {
+				return nil, fmt.Errorf("datasource %s: %w", ds.Name, err)
+			}
+			if len(services) == 0 {
+				r.Log.Info("Services of the datasource are not found", "datasource", ds.Name)
+			}
+			targets = discoveredTargets(ds, services)
+		}
+		for _, target := range targets {
+			fields, err := catalogueDataSource(ds, target, interval, func(selector *corev1.SecretKeySelector) (string, error) {
+				return resolve(cr.GetNamespace(), selector)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("datasource %s: %w", ds.Name, err)
+			}
+			datasources = append(datasources, fields)
+		}
+	}
+	return datasources, nil
+}
+
+// secretReference returns the resolver which stores values of keys of Secrets in credentials
+// and returns references to environment variables of Grafana.
+// Secrets are watched, so Grafana gets new credentials when they are changed.
+func (r *GrafanaReconciler) secretReference(ctx context.Context, credentials datasourceCredentials) secretResolver {
+	return func(namespace string, selector *corev1.SecretKeySelector) (string, error) {
+		secret, err := r.KubeClient.CoreV1().Secrets(namespace).Get(ctx, selector.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		r.WatchResource(secret)
+		value := secret.Data[selector.Key]
+		if len(value) == 0 {
+			return "", fmt.Errorf("key %s is not found or empty in the Secret %s/%s", selector.Key, namespace, selector.Name)
+		}
+		return credentials.reference(namespace, selector, string(value)), nil
+	}
+}
+
+// discoverDatasourceServices returns Services selected by labels in the namespace of the custom resource
+// or in namespaces selected by labels
+func (r *GrafanaReconciler) discoverDatasourceServices(ctx context.Context, cr *v1alpha1.PlatformMonitoring, discovery *v1alpha1.DatasourceDiscovery) ([]corev1.Service, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&discovery.Selector)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []string{cr.GetNamespace()}
+	if discovery.NamespaceSelector != nil {
+		if !utils.PrivilegedRights {
+			return nil, fmt.Errorf("namespaceSelector requires privileged rights")
+		}
+		nsSelector, err := metav1.LabelSelectorAsSelector(discovery.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		list, err := r.KubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: nsSelector.String()})
+		if err != nil {
+			return nil, err
+		}
+		namespaces = namespaces[:0]
+		for _, ns := range list.Items {
+			namespaces = append(namespaces, ns.GetName())
+		}
+	}
+	var services []corev1.Service
+	for _, ns := range namespaces {
+		list, err := r.KubeClient.CoreV1().Services(ns).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, list.Items...)
+	}
+	sortServices(services)
+	return services, nil
+}
+
+// discoveredTargets returns names and URLs of datasources for discovered Services.
+// If several Services are found, names are suffixed with namespaces and names of Services.
+func discoveredTargets(ds v1alpha1.GrafanaDatasource, services []corev1.Service) []datasourceTarget {
+	scheme := ds.Discovery.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	var targets []datasourceTarget
+	var found []corev1.Service
+	for _, service := range services {
+		var port int32
+		for i, p := range service.Spec.Ports {
+			if (ds.Discovery.Port == "" && i == 0) || p.Name == ds.Discovery.Port {
+				port = p.Port
+			}
+		}
+		if port == 0 {
+			continue
+		}
+		found = append(found, service)
+		targets = append(targets, datasourceTarget{
+			name: ds.Name,
+			uid:  ds.UID,
+			url:  fmt.Sprintf("%s://%s.%s.svc:%d%s", scheme, service.GetName(), service.GetNamespace(), port, ds.Discovery.Path),
+		})
+	}
+	if len(targets) > 1 {
+		for i, service := range found {
+			targets[i].name = fmt.Sprintf("%s %s/%s", ds.Name, service.GetNamespace(), service.GetName())
+			targets[i].uid = ""
+		}
+	}
+	return targets
+}
+
+// tenantDataSources returns datasources of tenants of VmCluster which have the grafana section.
+// Datasources read metrics of the tenant from vmselect and are created in the organisation set for the tenant,
+// so users of the organisation see only metrics of the tenant.
+func tenantDataSources(cr *v1alpha1.PlatformMonitoring, interval string) []grafv1.GrafanaDataSourceFields {
+	if cr.Spec.Victoriametrics == nil || !cr.Spec.Victoriametrics.VmOperator.IsInstall() {
+		return nil
+	}
+	var datasources []grafv1.GrafanaDataSourceFields
+	for _, tenant := range victoriametrics.GetVmclusterTenants(cr) {
+		if tenant.Grafana == nil {
+			continue
+		}
+		datasources = append(datasources, grafv1.GrafanaDataSourceFields{
+			Name:   "VictoriaMetrics " + tenant.Name,
+			Type:   "prometheus",
+			Access: "proxy",
+			OrgId:  tenant.Grafana.OrgID,
+			Url:    victoriametrics.GetVmselectURL(cr, tenant.ID),
+			JsonData: grafv1.GrafanaDataSourceJsonData{
+				TimeInterval:  interval,
+				TlsSkipVerify: true,
+				HTTPMethod:    "POST",
+			},
+			Editable: true,
+			Version:  1,
+		})
+	}
+	return datasources
+}
+
+// catalogueDataSource returns fields of the datasource for the entry of the catalogue.
+// Type-specific defaults are set first, then they are overridden by jsonData of the entry.
+func catalogueDataSource(ds v1alpha1.GrafanaDatasource, target datasourceTarget, interval string, resolve func(*corev1.SecretKeySelector) (string, error)) (grafv1.GrafanaDataSourceFields, error) {
+	fields := grafv1.GrafanaDataSourceFields{
+		Name:      target.name,
+		Uid:       target.uid,
+		Type:      datasourceTypes[ds.Type],
+		Access:    "proxy",
+		Url:       target.url,
+		IsDefault: ds.IsDefault,
+		Editable:  true,
+		Version:   1,
+	}
+	extra := map[string]interface{}{}
+	switch ds.Type {
+	case v1alpha1.GrafanaDatasourcePrometheus:
+		fields.JsonData.TimeInterval = interval
+		fields.JsonData.HTTPMethod = "POST"
+	case v1alpha1.GrafanaDatasourceElasticsearch:
+		fields.Database = ds.Database
+		fields.JsonData.TimeField = "@timestamp"
+	case v1alpha1.GrafanaDatasourceOpenSearch:
+		fields.JsonData.TimeField = "@timestamp"
+		extra["database"] = ds.Database
+		extra["flavor"] = "opensearch"
+	case v1alpha1.GrafanaDatasourceAlertmanager:
+		fields.JsonData.Implementation = "prometheus"
+	}
+
+	if ds.BasicAuth != nil {
+		user, err := resolve(&ds.BasicAuth.Username)
+		if err != nil {
+			return fields, err
+		}
+		password, err := resolve(&ds.BasicAuth.Password)
+		if err != nil {
+			return fields, err
+		}
+		fields.BasicAuth = true
+		fields.BasicAuthUser = user
+		fields.SecureJsonData.BasicAuthPassword = password
+	}
+	if ds.BearerToken != nil {
+		token, err := resolve(ds.BearerToken)
+		if err != nil {
+			return fields, err
+		}
+		fields.JsonData.HTTPHeaderName1 = "Authorization"
+		fields.SecureJsonData.HTTPHeaderValue1 = "Bearer " + token
+	}
+	if tls := ds.TLSConfig; tls != nil {
+		if tls.InsecureSkipVerify != nil {
+			fields.JsonData.TlsSkipVerify = *tls.InsecureSkipVerify
+		}
+		if tls.CASecret != nil {
+			ca, err := resolve(tls.CASecret)
+			if err != nil {
+				return fields, err
+			}
+			fields.JsonData.TlsAuthWithCACert = true
+			fields.SecureJsonData.TlsCaCert = ca
+		}
+		if tls.CertSecret != nil && tls.KeySecret != nil {
+			cert, err := resolve(tls.CertSecret)
+			if err != nil {
+				return fields, err
+			}
+			key, err := resolve(tls.KeySecret)
+			if err != nil {
+				return fields, err
+			}
+			fields.JsonData.TlsAuth = true
+			fields.SecureJsonData.TlsClientCert = cert
+			fields.SecureJsonData.TlsClientKey = key
+		}
+	}
+
+	if len(extra) == 0 && ds.JSONData == nil {
+		return fields, nil
+	}
+	// Settings which are not in typed jsonData are set with customJsonData, which replaces jsonData
+	jsonData, err := mergeJSONData(fields.JsonData, extra, ds.JSONData)
+	if err != nil {
+		return fields, err
+	}
+	fields.JsonData = grafv1.GrafanaDataSourceJsonData{}
+	fields.CustomJsonData = jsonData
+	return fields, nil
+}
+
+// mergeJSONData merges typed jsonData, additional settings and settings from the custom resource
+func mergeJSONData(typed grafv1.GrafanaDataSourceJsonData, extra map[string]interface{}, custom *apiextensionsv1.JSON) (json.RawMessage, error) {
+	data, err := json.Marshal(typed)
+	if err != nil {
+		return nil, err
+	}
+	merged := map[string]interface{}{}
+	if err = json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	// Some settings of typed jsonData are not omitted if they are empty
+	for k, v := range merged {
+		if nested, ok := v.(map[string]interface{}); (ok && len(nested) == 0) || v == float64(0) {
+			delete(merged, k)
+		}
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	if custom != nil && len(custom.Raw) > 0 {
+		overrides := map[string]interface{}{}
+		if err = json.Unmarshal(custom.Raw, &overrides); err != nil {
+			return nil, fmt.Errorf("jsonData must be an object: %w", err)
+		}
+		for k, v := range overrides {
+			merged[k] = v
+		}
+	}
+	return json.Marshal(merged)
+}
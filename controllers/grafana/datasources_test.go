@@ -0,0 +1,214 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestCatalogueDataSource(t *testing.T) {
+	resolve := func(selector *corev1.SecretKeySelector) (string, error) {
+		return selector.Name + "/" + selector.Key, nil
+	}
+	target := datasourceTarget{name: "Logs", uid: "logs", url: "http://logs:3100"}
+
+	fields, err := catalogueDataSource(v1alpha1.GrafanaDatasource{Type: v1alpha1.GrafanaDatasourcePrometheus}, target, "15s", resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, "prometheus", fields.Type)
+	assert.Equal(t, "15s", fields.JsonData.TimeInterval)
+	assert.Equal(t, "POST", fields.JsonData.HTTPMethod)
+
+	fields, err = catalogueDataSource(v1alpha1.GrafanaDatasource{
+		Type:        v1alpha1.GrafanaDatasourceLoki,
+		BasicAuth:   &promv1.BasicAuth{Username: secretKey("loki", "user"), Password: secretKey("loki", "password")},
+		BearerToken: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "loki"}, Key: "token"},
+		TLSConfig:   &v1alpha1.TLSConfig{CASecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "loki-tls"}, Key: "ca.crt"}},
+	}, target, "15s", resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, "loki", fields.Type)
+	assert.Equal(t, "Logs", fields.Name)
+	assert.Equal(t, "logs", fields.Uid)
+	assert.Equal(t, "proxy", fields.Access)
+	assert.True(t, fields.BasicAuth)
+	assert.Equal(t, "loki/user", fields.BasicAuthUser)
+	assert.Equal(t, "loki/password", fields.SecureJsonData.BasicAuthPassword)
+	assert.Equal(t, "Authorization", fields.JsonData.HTTPHeaderName1)
+	assert.Equal(t, "Bearer loki/token", fields.SecureJsonData.HTTPHeaderValue1)
+	assert.True(t, fields.JsonData.TlsAuthWithCACert)
+	assert.Equal(t, "loki-tls/ca.crt", fields.SecureJsonData.TlsCaCert)
+
+	fields, err = catalogueDataSource(v1alpha1.GrafanaDatasource{Type: v1alpha1.GrafanaDatasourceElasticsearch, Database: "logs-*"}, target, "15s", resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, "logs-*", fields.Database)
+	assert.Equal(t, "@timestamp", fields.JsonData.TimeField)
+
+	fields, err = catalogueDataSource(v1alpha1.GrafanaDatasource{Type: v1alpha1.GrafanaDatasourceAlertmanager}, target, "15s", resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, "prometheus", fields.JsonData.Implementation)
+
+	// Settings of OpenSearch and jsonData of the entry are set with customJsonData
+	fields, err = catalogueDataSource(v1alpha1.GrafanaDatasource{
+		Type:     v1alpha1.GrafanaDatasourceOpenSearch,
+		Database: "logs-*",
+		JSONData: &apiextensionsv1.JSON{Raw: []byte(`{"timeField": "time", "maxConcurrentShardRequests": 5}`)},
+	}, target, "15s", resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, "grafana-opensearch-datasource", fields.Type)
+	assert.Empty(t, fields.JsonData.TimeField)
+	jsonData := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(fields.CustomJsonData, &jsonData))
+	assert.Equal(t, map[string]interface{}{
+		"database":                   "logs-*",
+		"flavor":                     "opensearch",
+		"timeField":                  "time",
+		"maxConcurrentShardRequests": float64(5),
+	}, jsonData)
+
+	_, err = catalogueDataSource(v1alpha1.GrafanaDatasource{
+		Type:     v1alpha1.GrafanaDatasourceTempo,
+		JSONData: &apiextensionsv1.JSON{Raw: []byte(`["tracesToLogs"]`)},
+	}, target, "15s", resolve)
+	assert.Error(t, err)
+}
+
+func TestCatalogueDataSources(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tempo-credentials", Namespace: "monitoring"},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+	service := func(namespace, name string, ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app.kubernetes.io/name": "tempo"}},
+			Spec:       corev1.ServiceSpec{Ports: ports},
+		}
+	}
+	kubeClient := kubefake.NewSimpleClientset(
+		secret,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tracing-a", Labels: map[string]string{"tracing": "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tracing-b", Labels: map[string]string{"tracing": "true"}}},
+		service("monitoring", "tempo", corev1.ServicePort{Name: "grpc", Port: 9095}, corev1.ServicePort{Name: "http", Port: 3200}),
+		service("tracing-a", "tempo-query", corev1.ServicePort{Name: "http", Port: 3200}),
+		service("tracing-b", "tempo-query", corev1.ServicePort{Name: "http", Port: 3100}),
+		service("tracing-b", "tempo-headless"),
+	)
+	r := &GrafanaReconciler{
+		ComponentReconciler: &utils.ComponentReconciler{Log: utils.Logger("test")},
+		KubeClient:          kubeClient,
+	}
+	discovery := &v1alpha1.DatasourceDiscovery{
+		Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": "tempo"}},
+		Port:     "http",
+	}
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{Grafana: &v1alpha1.Grafana{Datasources: []v1alpha1.GrafanaDatasource{{
+			Name:        "Tempo",
+			Type:        v1alpha1.GrafanaDatasourceTempo,
+			UID:         "tempo",
+			Discovery:   discovery,
+			BearerToken: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "tempo-credentials"}, Key: "token"},
+		}}}},
+	}
+
+	// Credentials are referred by environment variables
+	credentials := datasourceCredentials{}
+	resolve := r.secretReference(context.Background(), credentials)
+	datasources, err := r.catalogueDataSources(context.Background(), cr, resolve)
+	assert.NoError(t, err)
+	if assert.Len(t, datasources, 1) {
+		assert.Equal(t, "Tempo", datasources[0].Name)
+		assert.Equal(t, "tempo", datasources[0].Uid)
+		assert.Equal(t, "http://tempo.monitoring.svc:3200", datasources[0].Url)
+		assert.Equal(t, "Bearer $__env{DATASOURCE_MONITORING_TEMPO_CREDENTIALS_TOKEN_4BEEB64B}", datasources[0].SecureJsonData.HTTPHeaderValue1)
+	}
+	assert.Equal(t, datasourceCredentials{"DATASOURCE_MONITORING_TEMPO_CREDENTIALS_TOKEN_4BEEB64B": "secret-token"}, credentials)
+
+	// Services in several namespaces are added as separate datasources without UIDs
+	discovery.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tracing": "true"}}
+	privileged := utils.PrivilegedRights
+	defer func() { utils.PrivilegedRights = privileged }()
+	utils.PrivilegedRights = true
+	datasources, err = r.catalogueDataSources(context.Background(), cr, resolve)
+	assert.NoError(t, err)
+	if assert.Len(t, datasources, 2) {
+		assert.Equal(t, "Tempo tracing-a/tempo-query", datasources[0].Name)
+		assert.Empty(t, datasources[0].Uid)
+		assert.Equal(t, "http://tempo-query.tracing-a.svc:3200", datasources[0].Url)
+		assert.Equal(t, "Tempo tracing-b/tempo-query", datasources[1].Name)
+		assert.Equal(t, "http://tempo-query.tracing-b.svc:3100", datasources[1].Url)
+	}
+
+	utils.PrivilegedRights = false
+	_, err = r.catalogueDataSources(context.Background(), cr, resolve)
+	assert.Error(t, err)
+
+	// Missing key of the Secret fails the datasource
+	discovery.NamespaceSelector = nil
+	cr.Spec.Grafana.Datasources[0].BearerToken.Key = "password"
+	_, err = r.catalogueDataSources(context.Background(), cr, resolve)
+	assert.Error(t, err)
+}
+
+func secretKey(name, key string) corev1.SecretKeySelector {
+	return corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+}
+
+func TestDatasourceCredentials(t *testing.T) {
+	credentials := datasourceCredentials{}
+	assert.Empty(t, credentials.hash())
+
+	ref := credentials.reference("clickhouse-1", clickHouseSecretKey("password"), "secret")
+	assert.Equal(t, "$__env{DATASOURCE_CLICKHOUSE_1_CLICKHOUSE_OPERATOR_CREDENTIALS_PASSWORD_95DD3929}", ref)
+	hash := credentials.hash()
+	assert.NotEmpty(t, hash)
+
+	// The hash is changed when the value of the Secret is rotated
+	credentials.reference("clickhouse-1", clickHouseSecretKey("password"), "rotated")
+	assert.NotEqual(t, hash, credentials.hash())
+	assert.Len(t, credentials, 1)
+
+	// Keys which differ only in invalid characters get different names
+	first := credentials.reference("a-b", &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "c"}, Key: "key"}, "first")
+	second := credentials.reference("a", &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "b-c"}, Key: "key"}, "second")
+	assert.NotEqual(t, first, second)
+	assert.Len(t, credentials, 3)
+}
+
+func TestTenantDataSources(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmOperator: v1alpha1.VmOperator{Image: "victoriametrics/operator:v0.48.3"},
+				VmCluster: v1alpha1.VmCluster{
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+				},
+				Tenants: []v1alpha1.VmTenant{
+					{Name: "team-a", ID: "1", Grafana: &v1alpha1.VmTenantGrafana{OrgID: 2}},
+					{Name: "team-b", ID: "2"},
+				},
+			},
+		},
+	}
+	datasources := tenantDataSources(cr, "30s")
+	assert.Len(t, datasources, 1)
+	assert.Equal(t, "VictoriaMetrics team-a", datasources[0].Name)
+	assert.Equal(t, "http://vmselect-k8s.monitoring.svc:8481/select/1/prometheus", datasources[0].Url)
+	assert.Equal(t, 2, datasources[0].OrgId)
+	assert.Equal(t, "30s", datasources[0].JsonData.TimeInterval)
+
+	cr.Spec.Victoriametrics.VmCluster.Install = ptr.To(false)
+	assert.Empty(t, tenantDataSources(cr, "30s"))
+}
@@ -0,0 +1,309 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	grafanaAdminCredentialsSecret = "grafana-admin-credentials"
+	grafanaAPITimeout             = 30 * time.Second
+)
+
+// grafanaURL returns the URL of the Grafana HTTP API in the namespace
+var grafanaURL = func(namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", utils.GrafanaServiceName, namespace, utils.GrafanaServicePort)
+}
+
+// permissionLevels contains values of permissions in the Grafana HTTP API
+var permissionLevels = map[v1alpha1.GrafanaPermission]int{
+	v1alpha1.GrafanaPermissionView:  1,
+	v1alpha1.GrafanaPermissionEdit:  2,
+	v1alpha1.GrafanaPermissionAdmin: 4,
+}
+
+// errTeamSyncUnavailable is returned if Grafana doesn't support synchronization of teams with OAuth groups
+var errTeamSyncUnavailable = errors.New("team synchronization with OAuth groups is not available, it requires Grafana Enterprise")
+
+// grafanaAPI is a client of the Grafana HTTP API authenticated as the Grafana admin
+type grafanaAPI struct {
+	baseURL  string
+	user     string
+	password string
+	client   *http.Client
+}
+
+type grafanaFolder struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+type grafanaTeam struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// folderPermission is an item of permissions of the folder in the Grafana HTTP API
+type folderPermission struct {
+	TeamID     int64  `json:"teamId,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Permission int    `json:"permission"`
+}
+
+// apiError is an unexpected response of the Grafana HTTP API
+type apiError struct {
+	method string
+	path   string
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.method, e.path, e.status, e.body)
+}
+
+func (a *grafanaAPI) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(a.user, a.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &apiError{method: method, path: path, status: resp.StatusCode, body: string(data)}
+	}
+	if result != nil {
+		return json.Unmarshal(data, result)
+	}
+	return nil
+}
+
+// ensureFolder returns the UID of the folder with the title and creates the folder if it doesn't exist.
+// grafana-operator finds folders of dashboards by titles, so it uses the same folder.
+func (a *grafanaAPI) ensureFolder(ctx context.Context, title string) (string, error) {
+	var folders []grafanaFolder
+	if err := a.do(ctx, http.MethodGet, "/api/folders?limit=1000", nil, &folders); err != nil {
+		return "", err
+	}
+	for _, folder := range folders {
+		if folder.Title == title {
+			return folder.UID, nil
+		}
+	}
+	created := grafanaFolder{}
+	if err := a.do(ctx, http.MethodPost, "/api/folders", map[string]string{"title": title}, &created); err != nil {
+		return "", err
+	}
+	return created.UID, nil
+}
+
+// ensureTeam returns the ID of the team with the name and creates the team if it doesn't exist
+func (a *grafanaAPI) ensureTeam(ctx context.Context, name string) (int64, error) {
+	var search struct {
+		Teams []grafanaTeam `json:"teams"`
+	}
+	if err := a.do(ctx, http.MethodGet, "/api/teams/search?name="+url.QueryEscape(name), nil, &search); err != nil {
+		return 0, err
+	}
+	for _, team := range search.Teams {
+		if team.Name == name {
+			return team.ID, nil
+		}
+	}
+	var created struct {
+		TeamID int64 `json:"teamId"`
+	}
+	if err := a.do(ctx, http.MethodPost, "/api/teams", map[string]string{"name": name}, &created); err != nil {
+		return 0, err
+	}
+	return created.TeamID, nil
+}
+
+// ensureTeamGroup synchronizes members of the team with the OAuth group
+func (a *grafanaAPI) ensureTeamGroup(ctx context.Context, teamID int64, group string) error {
+	var groups []struct {
+		GroupID string `json:"groupId"`
+	}
+	path := fmt.Sprintf("/api/teams/%d/groups", teamID)
+	if err := a.do(ctx, http.MethodGet, path, nil, &groups); err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && (apiErr.status == http.StatusNotFound || apiErr.status == http.StatusForbidden) {
+			return errTeamSyncUnavailable
+		}
+		return err
+	}
+	for _, g := range groups {
+		if g.GroupID == group {
+			return nil
+		}
+	}
+	return a.do(ctx, http.MethodPost, path, map[string]string{"groupId": group}, nil)
+}
+
+// setFolderPermissions replaces permissions of the folder if they differ from the desired ones
+func (a *grafanaAPI) setFolderPermissions(ctx context.Context, uid string, permissions []folderPermission) error {
+	path := "/api/folders/" + url.PathEscape(uid) + "/permissions"
+	var current []struct {
+		folderPermission
+		UserID    int64 `json:"userId"`
+		Inherited bool  `json:"inherited"`
+	}
+	if err := a.do(ctx, http.MethodGet, path, nil, &current); err != nil {
+		return err
+	}
+	var existing []folderPermission
+	// Permissions of users are not declared, so the folder is updated to remove them
+	hasUsers := false
+	for _, p := range current {
+		if p.Inherited {
+			continue
+		}
+		if p.UserID != 0 {
+			hasUsers = true
+			continue
+		}
+		existing = append(existing, p.folderPermission)
+	}
+	sortFolderPermissions(existing)
+	sortFolderPermissions(permissions)
+	if !hasUsers && equalFolderPermissions(existing, permissions) {
+		return nil
+	}
+	return a.do(ctx, http.MethodPost, path, map[string]interface{}{"items": permissions}, nil)
+}
+
+func sortFolderPermissions(permissions []folderPermission) {
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].TeamID != permissions[j].TeamID {
+			return permissions[i].TeamID < permissions[j].TeamID
+		}
+		if permissions[i].Role != permissions[j].Role {
+			return permissions[i].Role < permissions[j].Role
+		}
+		return permissions[i].Permission < permissions[j].Permission
+	})
+}
+
+func equalFolderPermissions(a, b []folderPermission) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newGrafanaAPI returns the client of the Grafana HTTP API with credentials of the Grafana admin
+func (r *GrafanaReconciler) newGrafanaAPI(cr *v1alpha1.PlatformMonitoring) (*grafanaAPI, error) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: grafanaAdminCredentialsSecret, Namespace: cr.GetNamespace()}}
+	if err := r.GetResource(secret); err != nil {
+		return nil, err
+	}
+	return &grafanaAPI{
+		baseURL:  grafanaURL(cr.GetNamespace()),
+		user:     string(secret.Data["GF_SECURITY_ADMIN_USER"]),
+		password: string(secret.Data["GF_SECURITY_ADMIN_PASSWORD"]),
+		client:   &http.Client{Timeout: grafanaAPITimeout},
+	}, nil
+}
+
+// handleGrafanaFolders sets permissions of folders through the Grafana HTTP API.
+// Folders are created if they don't exist yet, grafana-operator moves dashboards to them.
+func (r *GrafanaReconciler) handleGrafanaFolders(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	if r.IsDryRun() {
+		r.Log.Info("Skip permissions of Grafana folders in the dry run")
+		return nil
+	}
+	api, err := r.newGrafanaAPI(cr)
+	if err != nil {
+		return err
+	}
+	return r.applyGrafanaFolders(ctx, cr, api)
+}
+
+func (r *GrafanaReconciler) applyGrafanaFolders(ctx context.Context, cr *v1alpha1.PlatformMonitoring, api *grafanaAPI) error {
+	var errs []error
+	teams := make(map[string]int64)
+	for _, folder := range cr.Spec.Grafana.Folders {
+		if len(folder.Permissions) == 0 {
+			continue
+		}
+		uid, err := api.ensureFolder(ctx, folder.Title)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("folder %s: %w", folder.Title, err))
+			continue
+		}
+		var permissions []folderPermission
+		failed := false
+		for _, p := range folder.Permissions {
+			item := folderPermission{Role: p.Role, Permission: permissionLevels[p.Permission]}
+			if team := p.Team + p.Group; team != "" {
+				id, ok := teams[team]
+				if !ok {
+					if id, err = api.ensureTeam(ctx, team); err != nil {
+						errs = append(errs, fmt.Errorf("folder %s: team %s: %w", folder.Title, team, err))
+						failed = true
+						break
+					}
+					teams[team] = id
+					if p.Group != "" {
+						if err = api.ensureTeamGroup(ctx, id, p.Group); errors.Is(err, errTeamSyncUnavailable) {
+							r.Log.Info("Members of the team are not synchronized with the OAuth group", "team", team, "reason", err.Error())
+							r.Event(cr, corev1.EventTypeWarning, "TeamSyncUnavailable",
+								fmt.Sprintf("Add members of the OAuth group %s to the Grafana team %s manually: %s", p.Group, team, err))
+						} else if err != nil {
+							errs = append(errs, fmt.Errorf("folder %s: group %s: %w", folder.Title, p.Group, err))
+						}
+					}
+				}
+				item.TeamID = id
+			}
+			permissions = append(permissions, item)
+		}
+		if failed {
+			continue
+		}
+		if err = api.setFolderPermissions(ctx, uid, permissions); err != nil {
+			errs = append(errs, fmt.Errorf("folder %s: %w", folder.Title, err))
+			continue
+		}
+		r.Log.Info("Permissions of the Grafana folder are reconciled", "folder", folder.Title)
+	}
+	return errors.Join(errs...)
+}
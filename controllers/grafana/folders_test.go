@@ -0,0 +1,139 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeGrafana implements endpoints of the Grafana HTTP API used to reconcile folders
+type fakeGrafana struct {
+	mu          sync.Mutex
+	folders     []grafanaFolder
+	teams       []grafanaTeam
+	permissions map[string][]folderPermission
+	updates     int
+	teamSync    bool
+	groups      map[int64][]string
+}
+
+func (g *fakeGrafana) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if user, password, ok := req.BasicAuth(); !ok || user != "admin" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	write := func(v interface{}) { _ = json.NewEncoder(w).Encode(v) }
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/api/folders":
+		write(g.folders)
+	case req.Method == http.MethodPost && req.URL.Path == "/api/folders":
+		var body grafanaFolder
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		body.UID = "uid-" + body.Title
+		g.folders = append(g.folders, body)
+		write(body)
+	case req.Method == http.MethodGet && req.URL.Path == "/api/teams/search":
+		var found []grafanaTeam
+		for _, team := range g.teams {
+			if team.Name == req.URL.Query().Get("name") {
+				found = append(found, team)
+			}
+		}
+		write(map[string]interface{}{"teams": found})
+	case req.Method == http.MethodPost && req.URL.Path == "/api/teams":
+		var body grafanaTeam
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		body.ID = int64(len(g.teams) + 1)
+		g.teams = append(g.teams, body)
+		write(map[string]int64{"teamId": body.ID})
+	case req.URL.Path == "/api/teams/2/groups" || req.URL.Path == "/api/teams/1/groups":
+		if !g.teamSync {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		id := int64(1)
+		if req.URL.Path == "/api/teams/2/groups" {
+			id = 2
+		}
+		if req.Method == http.MethodPost {
+			var body struct {
+				GroupID string `json:"groupId"`
+			}
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			g.groups[id] = append(g.groups[id], body.GroupID)
+			return
+		}
+		var groups []map[string]string
+		for _, group := range g.groups[id] {
+			groups = append(groups, map[string]string{"groupId": group})
+		}
+		write(groups)
+	case req.Method == http.MethodGet && req.URL.Path == "/api/folders/uid-Platform/permissions":
+		write(g.permissions["uid-Platform"])
+	case req.Method == http.MethodPost && req.URL.Path == "/api/folders/uid-Platform/permissions":
+		var body struct {
+			Items []folderPermission `json:"items"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		g.permissions["uid-Platform"] = body.Items
+		g.updates++
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestApplyGrafanaFolders(t *testing.T) {
+	g := &fakeGrafana{
+		permissions: map[string][]folderPermission{
+			"uid-Platform": {{Role: "Editor", Permission: 2}, {Role: "Viewer", Permission: 1}},
+		},
+		groups: map[int64][]string{},
+	}
+	server := httptest.NewServer(g)
+	defer server.Close()
+	api := &grafanaAPI{baseURL: server.URL, user: "admin", password: "secret", client: server.Client()}
+	r := &GrafanaReconciler{ComponentReconciler: &utils.ComponentReconciler{Log: utils.Logger("test")}}
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{Grafana: &v1alpha1.Grafana{Folders: []v1alpha1.GrafanaFolder{
+			{
+				Title: "Platform",
+				Permissions: []v1alpha1.GrafanaFolderPermission{
+					{Role: "Viewer", Permission: v1alpha1.GrafanaPermissionView},
+					{Team: "platform", Permission: v1alpha1.GrafanaPermissionEdit},
+					{Group: "platform-admins", Permission: v1alpha1.GrafanaPermissionAdmin},
+				},
+			},
+			{Title: "Applications", Dashboards: []string{"jvm-processes"}},
+		}}},
+	}
+	ctx := context.Background()
+
+	// Members of teams are not synchronized with groups in Grafana OSS, but permissions are set
+	assert.NoError(t, r.applyGrafanaFolders(ctx, cr, api))
+	assert.Equal(t, []grafanaFolder{{UID: "uid-Platform", Title: "Platform"}}, g.folders)
+	assert.Equal(t, []grafanaTeam{{ID: 1, Name: "platform"}, {ID: 2, Name: "platform-admins"}}, g.teams)
+	assert.ElementsMatch(t, []folderPermission{
+		{Role: "Viewer", Permission: 1},
+		{TeamID: 1, Permission: 2},
+		{TeamID: 2, Permission: 4},
+	}, g.permissions["uid-Platform"])
+	assert.Equal(t, 1, g.updates)
+
+	// Permissions are not updated if they are not changed
+	g.teamSync = true
+	assert.NoError(t, r.applyGrafanaFolders(ctx, cr, api))
+	assert.Equal(t, 1, g.updates)
+	assert.Len(t, g.teams, 2)
+	assert.Equal(t, []string{"platform-admins"}, g.groups[2])
+}
@@ -26,15 +26,22 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 )
 
-func (r *GrafanaReconciler) handleGrafana(cr *v1alpha1.PlatformMonitoring) error {
+func (r *GrafanaReconciler) handleGrafana(ctx context.Context, cr *v1alpha1.PlatformMonitoring, credentials datasourceCredentials) error {
 	m, err := grafana(cr)
 	if err != nil {
 		r.Log.Error(err, "Failed creating Grafana manifest")
 		return err
 	}
+	// Grafana reads credentials of datasources from environment variables only on start
+	if hash := credentials.hash(); hash != "" {
+		if m.Spec.Deployment.Annotations == nil {
+			m.Spec.Deployment.Annotations = map[string]string{}
+		}
+		m.Spec.Deployment.Annotations[DatasourceCredentialsHashAnnotation] = hash
+	}
 
 	if m.Spec.Config.AuthGenericOauth != nil {
-		secret, err := r.KubeClient.CoreV1().Secrets(m.Namespace).Get(context.TODO(), utils.GrafanaExtraVarsSecret, metav1.GetOptions{})
+		secret, err := r.KubeClient.CoreV1().Secrets(m.Namespace).Get(ctx, utils.GrafanaExtraVarsSecret, metav1.GetOptions{})
 		if err != nil {
 			r.Log.Error(err, "auth.generic_oauth is configured but clientId and clientSecret is not stored in secret")
 			return err
@@ -46,68 +53,70 @@ func (r *GrafanaReconciler) handleGrafana(cr *v1alpha1.PlatformMonitoring) error
 		m.Spec.Config.AuthGenericOauth.ClientId = secret.StringData["GF_AUTH_GENERIC_OAUTH_CLIENT_ID"]
 		m.Spec.Config.AuthGenericOauth.ClientSecret = secret.StringData["GF_AUTH_GENERIC_OAUTH_CLIENT_SECRET"]
 	}
-	e := &grafv1.Grafana{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	// WA for https://github.com/grafana-operator/grafana-operator/issues/652
-	r.Log.Info("Waiting grafana-deployment")
-	time.Sleep(30 * time.Second)
+	if !r.IsDryRun() {
+		r.Log.Info("Waiting grafana-deployment")
+		select {
+		case <-time.After(30 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 	return nil
 }
 
-func (r *GrafanaReconciler) handleGrafanaDataSource(cr *v1alpha1.PlatformMonitoring) error {
-	jaegerServices, err := r.getJaegerServices(cr)
+// grafanaDataSourceManifest returns the GrafanaDataSource manifest and credentials of its datasources
+func (r *GrafanaReconciler) grafanaDataSourceManifest(ctx context.Context, cr *v1alpha1.PlatformMonitoring) (*grafv1.GrafanaDataSource, datasourceCredentials, error) {
+	jaegerServices, err := r.getJaegerServices(ctx, cr)
 	if err != nil {
 		r.Log.Error(err, "Failed getting Jaeger services")
 	}
-	clickHouseServices, err := r.getClickhouseServices(cr)
+	clickHouseServices, err := r.getClickhouseServices(ctx, cr)
 	if err != nil {
 		r.Log.Error(err, "Failed getting ClickHouse services")
 	}
-	m, err := grafanaDataSource(cr, r.KubeClient, jaegerServices, clickHouseServices)
+	credentials := datasourceCredentials{}
+	resolve := r.secretReference(ctx, credentials)
+	datasources, err := r.catalogueDataSources(ctx, cr, resolve)
+	if err != nil {
+		r.Log.Error(err, "Failed creating datasources from the catalogue")
+		return nil, nil, err
+	}
+	m, err := grafanaDataSource(cr, jaegerServices, clickHouseServices, datasources, resolve)
 	if err != nil {
 		r.Log.Error(err, "Failed creating GrafanaDataSource manifest")
-		return err
+		return nil, nil, err
 	}
 
 	// Set labels
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Grafana.Image)
+	return m, credentials, nil
+}
 
-	e := &grafv1.GrafanaDataSource{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
+func (r *GrafanaReconciler) handleGrafanaDataSource(cr *v1alpha1.PlatformMonitoring, m *grafv1.GrafanaDataSource) error {
+	if err := r.ApplyResource(cr, m); err != nil {
 		return err
 	}
+	return nil
+}
 
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
-		return err
+// handleDatasourceCredentials stores credentials of datasources in grafana-extra-vars-secret.
+// Only keys with credentials are applied, so other keys of the Secret are kept
+// and keys which are not used anymore are removed by server-side apply.
+func (r *GrafanaReconciler) handleDatasourceCredentials(cr *v1alpha1.PlatformMonitoring, credentials datasourceCredentials) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.GrafanaExtraVarsSecret, Namespace: cr.GetNamespace()},
+		Data:       make(map[string][]byte, len(credentials)),
 	}
-	return nil
+	for name, value := range credentials {
+		secret.Data[name] = []byte(value)
+	}
+	// The Secret is created by the chart with other variables, so it is not owned by the custom resource
+	return r.ApplyResource(cr, secret, false)
 }
 
 func (r *GrafanaReconciler) handleIngressV1beta1(cr *v1alpha1.PlatformMonitoring) error {
@@ -116,24 +125,7 @@ func (r *GrafanaReconciler) handleIngressV1beta1(cr *v1alpha1.PlatformMonitoring
 		r.Log.Error(err, "Failed creating Ingress manifest")
 		return err
 	}
-	e := &v1beta1.Ingress{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -145,24 +137,7 @@ func (r *GrafanaReconciler) handleIngressV1(cr *v1alpha1.PlatformMonitoring) err
 		r.Log.Error(err, "Failed creating Ingress manifest")
 		return err
 	}
-	e := &networkingv1.Ingress{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -181,25 +156,7 @@ func (r *GrafanaReconciler) handlePodMonitor(cr *v1alpha1.PlatformMonitoring) er
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Grafana.Image)
 
-	e := &promv1.PodMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.PodMetricsEndpoints = m.Spec.PodMetricsEndpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -222,9 +179,11 @@ func (r *GrafanaReconciler) handleGrafanaCredentialsSecret(cr *v1alpha1.Platform
 	if err = r.GetResource(e); err == nil {
 		if err = r.GetResource(tmpSecret); err == nil {
 			if !reflect.DeepEqual(e.Data, tmpSecret.Data) {
+				// The secret is created by grafana-operator, so it is updated
+				// without taking the ownership of its fields by server-side apply
 				e.Data = tmpSecret.Data
 				err = r.UpdateResource(e)
-				if err == nil {
+				if err == nil && !r.IsDryRun() {
 					isSecretUpdated = true
 				}
 			}
@@ -236,7 +195,7 @@ func (r *GrafanaReconciler) handleGrafanaCredentialsSecret(cr *v1alpha1.Platform
 	return
 }
 
-func (r *GrafanaReconciler) resetGrafanaCredentials(cr *v1alpha1.PlatformMonitoring) (err error) {
+func (r *GrafanaReconciler) resetGrafanaCredentials(ctx context.Context, cr *v1alpha1.PlatformMonitoring) (err error) {
 	// Waiting Grafana Pods readiness
 	r.Log.Info("Waiting for Grafana pods statuses", "kind", "Deployment", "name", utils.GrafanaDeploymentName)
 	if err := r.WaitForPodsReadiness(
@@ -262,7 +221,7 @@ func (r *GrafanaReconciler) resetGrafanaCredentials(cr *v1alpha1.PlatformMonitor
 		if err != nil {
 			return fmt.Errorf("cannot create clientset: %w", err)
 		}
-		pods, err := clientset.CoreV1().Pods(cr.GetNamespace()).List(context.TODO(), metav1.ListOptions{
+		pods, err := clientset.CoreV1().Pods(cr.GetNamespace()).List(ctx, metav1.ListOptions{
 			LabelSelector: "app=grafana",
 		})
 		if err != nil || len(pods.Items) == 0 {
@@ -307,7 +266,7 @@ func (r *GrafanaReconciler) resetGrafanaCredentials(cr *v1alpha1.PlatformMonitor
 		// Execute Grafana CLI request
 		r.Log.Info("Executing Grafana CLI command")
 		var stdout, stderr bytes.Buffer
-		err = exec.StreamWithContext(context.TODO(), remotecommand.StreamOptions{
+		err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
 			Stdout: &stdout,
 			Stderr: &stderr,
 		})
@@ -345,15 +304,7 @@ func (r *GrafanaReconciler) deleteGrafana(cr *v1alpha1.PlatformMonitoring) error
 }
 
 func (r *GrafanaReconciler) deleteGrafanaDataSource(cr *v1alpha1.PlatformMonitoring) error {
-	jaegerServices, err := r.getJaegerServices(cr)
-	if err != nil {
-		r.Log.Error(err, "Failed getting Jaeger services")
-	}
-	clickHouseServices, err := r.getClickhouseServices(cr)
-	if err != nil {
-		r.Log.Error(err, "Failed getting ClickHouse services")
-	}
-	m, err := grafanaDataSource(cr, r.KubeClient, jaegerServices, clickHouseServices)
+	m, err := grafanaDataSource(cr, nil, nil, nil, nil)
 	if err != nil {
 		r.Log.Error(err, "Failed creating GrafanaDataSource manifest")
 		return err
@@ -371,6 +322,18 @@ func (r *GrafanaReconciler) deleteGrafanaDataSource(cr *v1alpha1.PlatformMonitor
 	return nil
 }
 
+// deleteDatasourceCredentials removes credentials of datasources from grafana-extra-vars-secret
+func (r *GrafanaReconciler) deleteDatasourceCredentials(cr *v1alpha1.PlatformMonitoring) error {
+	e := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: utils.GrafanaExtraVarsSecret, Namespace: cr.GetNamespace()}}
+	if err := r.GetResource(e); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return r.handleDatasourceCredentials(cr, nil)
+}
+
 func (r *GrafanaReconciler) deleteIngressV1beta1(cr *v1alpha1.PlatformMonitoring) error {
 	m, err := grafanaIngressV1beta1(cr)
 	if err != nil {
@@ -429,11 +392,11 @@ func (r *GrafanaReconciler) deletePodMonitor(cr *v1alpha1.PlatformMonitoring) er
 }
 
 // Looking for Jaeger Services in all namespaces except current using a label selector and return list of them or nil
-func (r *GrafanaReconciler) getJaegerServices(cr *v1alpha1.PlatformMonitoring) ([]corev1.Service, error) {
+func (r *GrafanaReconciler) getJaegerServices(ctx context.Context, cr *v1alpha1.PlatformMonitoring) ([]corev1.Service, error) {
 	if !utils.PrivilegedRights || cr.Spec.Integration == nil || cr.Spec.Integration.Jaeger == nil || !cr.Spec.Integration.Jaeger.CreateGrafanaDataSource {
 		return nil, nil
 	}
-	allNamespaces, err := r.KubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	allNamespaces, err := r.KubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		r.Log.Error(err, "Failed getting namespaces")
 		return nil, err
@@ -448,7 +411,7 @@ func (r *GrafanaReconciler) getJaegerServices(cr *v1alpha1.PlatformMonitoring) (
 		if namespace.GetNamespace() == cr.GetNamespace() {
 			continue
 		}
-		serviceList, err := r.KubeClient.CoreV1().Services(namespace.GetNamespace()).List(context.TODO(), listOptions)
+		serviceList, err := r.KubeClient.CoreV1().Services(namespace.GetNamespace()).List(ctx, listOptions)
 		if err != nil {
 			if errors.IsNotFound(err) {
 				continue
@@ -474,12 +437,12 @@ func (r *GrafanaReconciler) getJaegerServices(cr *v1alpha1.PlatformMonitoring) (
 }
 
 // Looking for Clickhouse Services in all namespaces except current using a label selector and return list of them or nil
-func (r *GrafanaReconciler) getClickhouseServices(cr *v1alpha1.PlatformMonitoring) ([]corev1.Service, error) {
+func (r *GrafanaReconciler) getClickhouseServices(ctx context.Context, cr *v1alpha1.PlatformMonitoring) ([]corev1.Service, error) {
 	if !utils.PrivilegedRights || cr.Spec.Integration == nil || cr.Spec.Integration.ClickHouse == nil || !cr.Spec.Integration.ClickHouse.CreateGrafanaDataSource {
 		r.Log.Info(fmt.Sprintf("neto, utils.PrivilegedRights: %+v, cr.Spec.Integration: %+v", utils.PrivilegedRights, cr.Spec.Integration))
 		return nil, nil
 	}
-	allNamespaces, err := r.KubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	allNamespaces, err := r.KubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		r.Log.Error(err, "Failed getting namespaces")
 		return nil, err
@@ -489,7 +452,7 @@ func (r *GrafanaReconciler) getClickhouseServices(cr *v1alpha1.PlatformMonitorin
 		if namespace.GetName() == cr.GetNamespace() {
 			continue
 		}
-		serviceList, err := r.KubeClient.CoreV1().Services(namespace.GetName()).List(context.TODO(), metav1.ListOptions{})
+		serviceList, err := r.KubeClient.CoreV1().Services(namespace.GetName()).List(ctx, metav1.ListOptions{})
 		if err != nil {
 			r.Log.Info(fmt.Sprintf("Error getting services in namespace:%s Error: %v", namespace.GetNamespace(), err))
 			continue
@@ -1,7 +1,6 @@
 package grafana
 
 import (
-	"context"
 	"embed"
 	"errors"
 	"fmt"
@@ -11,17 +10,16 @@ import (
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
 	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	grafv1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/api/networking/v1beta1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/kubernetes"
 )
 
 //go:embed  assets/*.yaml
@@ -256,13 +254,22 @@ func grafana(cr *v1alpha1.PlatformMonitoring) (*grafv1.Grafana, error) {
 	return &graf, nil
 }
 
-func grafanaDataSource(cr *v1alpha1.PlatformMonitoring, KubeClient kubernetes.Interface, jaegerServices []corev1.Service, clickHouseServices []corev1.Service) (*grafv1.GrafanaDataSource, error) {
+// datasourceInterval returns the scrape interval which is used as the minimal interval of Prometheus datasources
+func datasourceInterval(cr *v1alpha1.PlatformMonitoring) string {
+	if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmOperator.IsInstall() &&
+		cr.Spec.Victoriametrics.VmAgent.IsInstall() && len(strings.TrimSpace(cr.Spec.Victoriametrics.VmAgent.ScrapeInterval)) > 0 {
+		return cr.Spec.Victoriametrics.VmAgent.ScrapeInterval
+	}
+	return "30s"
+}
+
+func grafanaDataSource(cr *v1alpha1.PlatformMonitoring, jaegerServices []corev1.Service, clickHouseServices []corev1.Service, datasources []grafv1.GrafanaDataSourceFields, resolve secretResolver) (*grafv1.GrafanaDataSource, error) {
 	dataSource := grafv1.GrafanaDataSource{}
 	if err := yaml.NewYAMLOrJSONDecoder(utils.MustAssetReader(assets, utils.GrafanaDataSourceAsset), 100).Decode(&dataSource); err != nil {
 		return nil, err
 	}
 	// Set Interval for Grafana datasource
-	var grafanaDatasourceInterval string = "30s"
+	grafanaDatasourceInterval := datasourceInterval(cr)
 	if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmOperator.IsInstall() {
 		if cr.Spec.Victoriametrics.VmSingle.IsInstall() {
 			vmSingle := vmetricsv1b1.VMSingle{}
@@ -275,18 +282,8 @@ func grafanaDataSource(cr *v1alpha1.PlatformMonitoring, KubeClient kubernetes.In
 			dataSource.Spec.Datasources[0].Url = vmSingle.AsURL()
 		}
 		if cr.Spec.Victoriametrics.VmCluster.IsInstall() {
-			vmCluster := &vmetricsv1b1.VMCluster{}
-			vmCluster.SetName(utils.VmComponentName)
-			vmCluster.SetNamespace(cr.GetNamespace())
-			vmCluster.Spec.VMSelect = cr.Spec.Victoriametrics.VmCluster.VmSelect
-			if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.TLSEnabled {
-				vmCluster.Spec.VMSelect.ExtraArgs = make(map[string]string)
-				maps.Copy(vmCluster.Spec.VMSelect.ExtraArgs, map[string]string{"tls": "true"})
-			}
-			dataSource.Spec.Datasources[0].Url = vmCluster.VMSelectURL() + "/select/0/prometheus"
-		}
-		if cr.Spec.Victoriametrics.VmAgent.IsInstall() && len(strings.TrimSpace(cr.Spec.Victoriametrics.VmAgent.ScrapeInterval)) > 0 {
-			grafanaDatasourceInterval = cr.Spec.Victoriametrics.VmAgent.ScrapeInterval
+			tenant := victoriametrics.GetVmclusterTenant(cr.Spec.Victoriametrics.VmCluster)
+			dataSource.Spec.Datasources[0].Url = victoriametrics.GetVmselectURL(cr, tenant)
 		}
 	}
 	// Set parameters
@@ -356,12 +353,14 @@ func grafanaDataSource(cr *v1alpha1.PlatformMonitoring, KubeClient kubernetes.In
 				Version:   1,
 				Url:       fmt.Sprintf("http://%s.%s.svc.cluster.local:8123", clickHouseService.GetName(), clickHouseService.GetNamespace()),
 			}
-			secret, err := KubeClient.CoreV1().Secrets(clickHouseService.GetNamespace()).Get(context.TODO(), utils.ClickHouseSecret, metav1.GetOptions{})
-			if err == nil && len(secret.Data["username"]) != 0 && len(secret.Data["password"]) != 0 {
+			// Credentials are set as references to environment variables of Grafana
+			user, userErr := resolve(clickHouseService.GetNamespace(), clickHouseSecretKey("username"))
+			password, passwordErr := resolve(clickHouseService.GetNamespace(), clickHouseSecretKey("password"))
+			if userErr == nil && passwordErr == nil {
 				clickHouseDataSource.BasicAuth = true
-				clickHouseDataSource.BasicAuthUser = string(secret.Data["username"])
+				clickHouseDataSource.BasicAuthUser = user
 				clickHouseDataSource.SecureJsonData = grafv1.GrafanaDataSourceSecureJsonData{
-					BasicAuthPassword: string(secret.Data["password"]),
+					BasicAuthPassword: password,
 				}
 			}
 			dataSource.Spec.Datasources = append(dataSource.Spec.Datasources, clickHouseDataSource)
@@ -374,6 +373,17 @@ func grafanaDataSource(cr *v1alpha1.PlatformMonitoring, KubeClient kubernetes.In
 
 	dataSource.Spec.Datasources[0].JsonData.TimeInterval = grafanaDatasourceInterval
 
+	// Set datasources of tenants of VmCluster
+	dataSource.Spec.Datasources = append(dataSource.Spec.Datasources, tenantDataSources(cr, grafanaDatasourceInterval)...)
+
+	// Set datasources from the catalogue of grafana.datasources
+	for _, ds := range datasources {
+		if ds.IsDefault {
+			dataSource.Spec.Datasources[0].IsDefault = false
+		}
+	}
+	dataSource.Spec.Datasources = append(dataSource.Spec.Datasources, datasources...)
+
 	return &dataSource, nil
 }
 
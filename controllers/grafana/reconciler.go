@@ -1,12 +1,15 @@
 package grafana
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -18,14 +21,15 @@ type GrafanaReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewGrafanaReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, r *rest.Config) *GrafanaReconciler {
+func NewGrafanaReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, r *rest.Config, rec record.EventRecorder) *GrafanaReconciler {
 	cl, _ := kubernetes.NewForConfig(r)
 	return &GrafanaReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("grafana_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("grafana_reconciler"),
+			Recorder: rec,
 		},
 		KubeClient: cl,
 		config:     r,
@@ -36,7 +40,7 @@ func NewGrafanaReconciler(c client.Client, s *runtime.Scheme, dc discovery.Disco
 // Creates new custom resources: Grafana and GrafanaDataSource if its don't exists.
 // Updates custom resources in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *GrafanaReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *GrafanaReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if cr.Spec.Grafana != nil && cr.Spec.Grafana.IsInstall() {
@@ -44,11 +48,19 @@ func (r *GrafanaReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 			if err := r.handleGrafanaCredentialsSecret(cr); err != nil {
 				return err
 			}
+			// Datasources are built before Grafana, because Grafana is restarted when their credentials are changed
+			dataSource, credentials, err := r.grafanaDataSourceManifest(ctx, cr)
+			if err != nil {
+				return err
+			}
+			if err = r.handleDatasourceCredentials(cr, credentials); err != nil {
+				return err
+			}
 			// Reconcile resources with creation and update
-			if err := r.handleGrafana(cr); err != nil {
+			if err = r.handleGrafana(ctx, cr, credentials); err != nil {
 				return err
 			}
-			if err := r.handleGrafanaDataSource(cr); err != nil {
+			if err = r.handleGrafanaDataSource(cr, dataSource); err != nil {
 				return err
 			}
 
@@ -90,11 +102,18 @@ func (r *GrafanaReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 			}
 			// Reset Grafana Credentials
 			if isSecretUpdated {
-				if err := r.resetGrafanaCredentials(cr); err != nil {
+				if err := r.resetGrafanaCredentials(ctx, cr); err != nil {
 					r.Log.Error(err, "Can not reset Grafana Credentials")
 					return err
 				}
 			}
+			// Reconcile permissions of folders through the Grafana HTTP API
+			if len(cr.Spec.Grafana.Folders) > 0 {
+				if err := r.handleGrafanaFolders(ctx, cr); err != nil {
+					r.Log.Error(err, "Can not reconcile Grafana folders")
+					return err
+				}
+			}
 			r.Log.Info("Component reconciled")
 		} else {
 			r.Log.Info("Reconciling paused")
@@ -116,6 +135,9 @@ func (r *GrafanaReconciler) uninstall(cr *v1alpha1.PlatformMonitoring) {
 	if err := r.deleteGrafanaDataSource(cr); err != nil {
 		r.Log.Error(err, "Can not delete GrafanaDataSource")
 	}
+	if err := r.deleteDatasourceCredentials(cr); err != nil {
+		r.Log.Error(err, "Can not delete credentials of datasources")
+	}
 	if err := r.deletePodMonitor(cr); err != nil {
 		r.Log.Error(err, "Can not delete PodMonitor")
 	}
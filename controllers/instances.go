@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reasonDuplicateInstance is the reason of conditions of PlatformMonitoring which is rejected
+// because another instance already manages its namespace
+const reasonDuplicateInstance = "DuplicateInstance"
+
+// activeInstance returns the name of PlatformMonitoring which manages the namespace of the custom resource instance.
+// Names of objects created by components are unique only within the namespace, so only one instance
+// per namespace is supported. The oldest instance manages the namespace, other instances are rejected.
+func (r *PlatformMonitoringReconciler) activeInstance(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) (string, error) {
+	list := &qubershiporgv1.PlatformMonitoringList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace())); err != nil {
+		return "", err
+	}
+	items := list.Items
+	if len(items) == 0 {
+		return cr.GetName(), nil
+	}
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := items[i].GetCreationTimestamp(), items[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+	return items[0].GetName(), nil
+}
+
+// rejectDuplicateInstance reports in the status that the custom resource instance is not reconciled
+// because another instance already manages the namespace
+func (r *PlatformMonitoringReconciler) rejectDuplicateInstance(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring, active string) error {
+	message := fmt.Sprintf("PlatformMonitoring %s already manages namespace %s, only one instance per namespace is supported",
+		active, cr.GetNamespace())
+	changed := setCondition(cr, qubershiporgv1.ConditionDegraded, metav1.ConditionTrue, reasonDuplicateInstance, message)
+	changed = setCondition(cr, qubershiporgv1.ConditionAvailable, metav1.ConditionFalse, reasonDuplicateInstance, message) || changed
+	changed = setCondition(cr, qubershiporgv1.ConditionProgressing, metav1.ConditionFalse, reasonDuplicateInstance, message) || changed
+	if !changed && cr.Status.ObservedGeneration == cr.Generation {
+		return nil
+	}
+	cr.Status.ObservedGeneration = cr.Generation
+	r.event(cr, corev1.EventTypeWarning, reasonDuplicateInstance, message)
+	return r.Client.Status().Update(ctx, cr)
+}
+
+// mapOtherInstances returns requests for other PlatformMonitoring instances in the namespace of the deleted one,
+// so a rejected instance starts to manage the namespace
+func (r *PlatformMonitoringReconciler) mapOtherInstances(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &qubershiporgv1.PlatformMonitoringList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list PlatformMonitoring instances", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		if item.GetName() != obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: item.GetNamespace(), Name: item.GetName()},
+			})
+		}
+	}
+	return requests
+}
+
+// instanceDeletedPredicate passes only deletion of PlatformMonitoring instances
+func instanceDeletedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDuplicateInstance(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := qubershiporgv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	created := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	first := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{
+		Name: "platformmonitoring", Namespace: "monitoring", CreationTimestamp: created,
+	}}
+	second := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{
+		Name: "another", Namespace: "monitoring", CreationTimestamp: metav1.NewTime(created.Add(time.Minute)), Generation: 2,
+	}}
+	other := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{
+		Name: "platformmonitoring", Namespace: "team-a", CreationTimestamp: metav1.NewTime(created.Add(time.Minute)),
+	}}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(first, second, other).
+		WithStatusSubresource(first, second, other).
+		Build()
+	r := &PlatformMonitoringReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}
+	ctx := context.Background()
+
+	t.Run("Test active instance", func(t *testing.T) {
+		active, err := r.activeInstance(ctx, second)
+		assert.NoError(t, err)
+		assert.Equal(t, "platformmonitoring", active)
+		active, err = r.activeInstance(ctx, other)
+		assert.NoError(t, err)
+		assert.Equal(t, "platformmonitoring", active)
+	})
+	t.Run("Test duplicate instance is rejected", func(t *testing.T) {
+		instance := &qubershiporgv1.PlatformMonitoring{}
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(second), instance))
+		assert.NoError(t, r.rejectDuplicateInstance(ctx, instance, "platformmonitoring"))
+
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(second), instance))
+		degraded := meta.FindStatusCondition(instance.Status.Conditions, qubershiporgv1.ConditionDegraded)
+		if assert.NotNil(t, degraded) {
+			assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+			assert.Equal(t, reasonDuplicateInstance, degraded.Reason)
+			assert.Contains(t, degraded.Message, "PlatformMonitoring platformmonitoring already manages namespace monitoring")
+		}
+		assert.True(t, meta.IsStatusConditionFalse(instance.Status.Conditions, qubershiporgv1.ConditionAvailable))
+		assert.Equal(t, int64(2), instance.Status.ObservedGeneration)
+	})
+	t.Run("Test other instances are reconciled after deletion", func(t *testing.T) {
+		assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "monitoring", Name: "another"}}},
+			r.mapOtherInstances(ctx, first))
+	})
+}
@@ -12,24 +12,7 @@ func (r *KubernetesMonitorsReconciler) handleApiServerServiceMonitor(cr *v1alpha
 		r.Log.Error(err, "Failed creating ApiServerServiceMonitor manifest")
 		return err
 	}
-	e := &promv1.ServiceMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.Endpoints = m.Spec.Endpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -41,24 +24,7 @@ func (r *KubernetesMonitorsReconciler) handleControllerManagerServiceMonitor(cr
 		r.Log.Error(err, "Failed creating ControllerManagerServiceMonitor manifest")
 		return err
 	}
-	e := &promv1.ServiceMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.Endpoints = m.Spec.Endpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -70,25 +36,7 @@ func (r *KubernetesMonitorsReconciler) handleSchedulerServiceMonitor(cr *v1alpha
 		r.Log.Error(err, "Failed creating SchedulerServiceMonitor manifest")
 		return err
 	}
-	e := &promv1.ServiceMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.Endpoints = m.Spec.Endpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -100,25 +48,7 @@ func (r *KubernetesMonitorsReconciler) handleKubeletServiceMonitor(cr *v1alpha1.
 		r.Log.Error(err, "Failed creating KubeletServiceMonitor manifest")
 		return err
 	}
-	e := &promv1.ServiceMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.Endpoints = m.Spec.Endpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -130,25 +60,7 @@ func (r *KubernetesMonitorsReconciler) handleNginxIngressPodMonitor(cr *v1alpha1
 		r.Log.Error(err, "Failed creating NginxIngressPodMonitor manifest")
 		return err
 	}
-	e := &promv1.PodMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.PodMetricsEndpoints = m.Spec.PodMetricsEndpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -160,25 +72,7 @@ func (r *KubernetesMonitorsReconciler) handleCoreDnsServiceMonitor(cr *v1alpha1.
 		r.Log.Error(err, "Failed creating CoreDnsServiceMonitor manifest")
 		return err
 	}
-	e := &promv1.ServiceMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.Endpoints = m.Spec.Endpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -193,24 +87,7 @@ func (r *KubernetesMonitorsReconciler) handleOpenshiftServiceMonitor(cr *v1alpha
 		r.Log.Error(err, "Failed creating manifest for"+crServiceMonitorName)
 		return err
 	}
-	e := &promv1.ServiceMonitor{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec.JobLabel = m.Spec.JobLabel
-	e.Spec.Endpoints = m.Spec.Endpoints
-	e.Spec.NamespaceSelector = m.Spec.NamespaceSelector
-	e.Spec.Selector = m.Spec.Selector
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -1,10 +1,13 @@
 package kubernetes_monitors
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -14,20 +17,21 @@ type KubernetesMonitorsReconciler struct {
 }
 
 // NewKubernetesMonitorsReconciler  returns KubernetesMonitorsReconciler by specified parameters
-func NewKubernetesMonitorsReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *KubernetesMonitorsReconciler {
+func NewKubernetesMonitorsReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *KubernetesMonitorsReconciler {
 	return &KubernetesMonitorsReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("kubernetes_monitors_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("kubernetes_monitors_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
 
 // Run reconciles k8s service monitors
 // Creates, updates and deletes service monitors for k8s monitoring depending of configurtion
-func (r *KubernetesMonitorsReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *KubernetesMonitorsReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if len(cr.Spec.KubernetesMonitors) > 0 {
@@ -227,26 +231,9 @@ func (r *KubernetesMonitorsReconciler) uninstall(cr *v1alpha1.PlatformMonitoring
 	}
 }
 
-// IsMonitorPresentInPublicCloud gets an answer in format (does the public cloud affect the monitor?, should the monitor be installed?)
-func IsMonitorPresentInPublicCloud(cr *v1alpha1.PlatformMonitoring, monitorName string) (bool, bool) {
-	if cr.Spec.PublicCloudName != "" {
-		if pcMap, ok := utils.PublicCloudMonitorsEnabled[cr.Spec.PublicCloudName]; ok {
-			if installed, included := pcMap[monitorName]; included {
-				return true, installed
-			}
-		}
-	}
-	return false, false
-}
-
-// IsMonitorInstall returns "true" if monitor should be installed
+// IsMonitorInstall returns "true" if monitor should be installed.
+// Monitors of the platform profile are already enabled or disabled in the custom resource.
 func IsMonitorInstall(cr *v1alpha1.PlatformMonitoring, monitorName string) bool {
-	// If the monitor affected by installation in the public cloud, cr.Spec.KubernetesMonitors doesn't matter
-	affected, installed := IsMonitorPresentInPublicCloud(cr, monitorName)
-	if affected {
-		return installed
-	} else {
-		monitor, ok := cr.Spec.KubernetesMonitors[monitorName]
-		return ok && monitor.IsInstall()
-	}
+	monitor, ok := cr.Spec.KubernetesMonitors[monitorName]
+	return ok && monitor.IsInstall()
 }
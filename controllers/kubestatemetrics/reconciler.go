@@ -1,10 +1,13 @@
 package kubestatemetrics
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -12,13 +15,14 @@ type KubeStateMetricsReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewKubeStateMetricsReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *KubeStateMetricsReconciler {
+func NewKubeStateMetricsReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *KubeStateMetricsReconciler {
 	return &KubeStateMetricsReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("kubestatemetrics_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("kubestatemetrics_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -27,7 +31,7 @@ func NewKubeStateMetricsReconciler(c client.Client, s *runtime.Scheme, dc discov
 // Creates new deployment, service, service account, cluster role and cluster role binding if its don't exists.
 // Updates deployment and service in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *KubeStateMetricsReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *KubeStateMetricsReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("reconciling component")
 
 	if cr.Spec.KubeStateMetrics != nil && cr.Spec.KubeStateMetrics.IsInstall() {
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Metrics of the operator reconciliation exposed on the metrics endpoint of the manager
+var (
+	componentReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "monitoring_operator_component_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliation of a component of the monitoring stack.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 14),
+	}, []string{"namespace", "name", "component"})
+	componentReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "monitoring_operator_component_reconcile_errors_total",
+		Help: "Number of failed reconciliations of a component of the monitoring stack by reason.",
+	}, []string{"namespace", "name", "component", "reason"})
+	managedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monitoring_operator_managed_objects",
+		Help: "Number of objects managed by a component during the last successful reconciliation.",
+	}, []string{"namespace", "name", "component"})
+	lastSuccessfulReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monitoring_operator_last_successful_reconcile_timestamp_seconds",
+		Help: "Unix time of the last reconciliation of PlatformMonitoring in which all components succeeded.",
+	}, []string{"namespace", "name"})
+	backupLastSucceeded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monitoring_operator_backup_last_succeeded",
+		Help: "Whether the last finished backup of a storage node of VictoriaMetrics succeeded (1) or failed (0).",
+	}, []string{"namespace", "target"})
+	backupAge = newBackupAgeCollector()
+)
+
+func init() {
+	metrics.Registry.MustRegister(componentReconcileDuration, componentReconcileErrors, managedObjects, lastSuccessfulReconcile,
+		backupLastSucceeded, backupAge)
+}
+
+// backupAgeCollector exposes the age of the last successful backup of each storage node.
+// The age is calculated at the time of the scrape, so it grows between reconciliations.
+type backupAgeCollector struct {
+	mu          sync.Mutex
+	desc        *prometheus.Desc
+	lastSuccess map[[2]string]time.Time
+}
+
+func newBackupAgeCollector() *backupAgeCollector {
+	return &backupAgeCollector{
+		desc: prometheus.NewDesc("monitoring_operator_backup_age_seconds",
+			"Time since the last successful backup of a storage node of VictoriaMetrics.", []string{"namespace", "target"}, nil),
+		lastSuccess: map[[2]string]time.Time{},
+	}
+}
+
+func (c *backupAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *backupAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, t := range c.lastSuccess {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(t).Seconds(), key[0], key[1])
+	}
+}
+
+// set replaces times of last successful backups of storage nodes in the namespace
+func (c *backupAgeCollector) set(namespace string, backups []qubershiporgv1.BackupStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.lastSuccess {
+		if key[0] == namespace {
+			delete(c.lastSuccess, key)
+		}
+	}
+	for _, backup := range backups {
+		if backup.LastSuccessTime != nil {
+			c.lastSuccess[[2]string{namespace, backup.Target}] = backup.LastSuccessTime.Time
+		}
+	}
+}
+
+// recordMetrics updates metrics of the custom resource instance with results of the reconciliation of components
+func recordMetrics(cr *qubershiporgv1.PlatformMonitoring, results []utils.ComponentResult, tracker *utils.ResourceTracker, degraded bool) {
+	namespace, name := cr.GetNamespace(), cr.GetName()
+	for _, result := range results {
+		if result.Skipped() {
+			continue
+		}
+		componentReconcileDuration.WithLabelValues(namespace, name, result.Name).Observe(result.Duration.Seconds())
+		if result.Err != nil {
+			componentReconcileErrors.WithLabelValues(namespace, name, result.Name, errorReason(result.Err)).Inc()
+			continue
+		}
+		managedObjects.WithLabelValues(namespace, name, result.Name).Set(float64(tracker.Managed(result.Name)))
+	}
+	if !degraded {
+		lastSuccessfulReconcile.WithLabelValues(namespace, name).Set(float64(time.Now().Unix()))
+	}
+}
+
+// deleteMetrics deletes series of reconciliation metrics of the deleted custom resource instance
+func deleteMetrics(cr *qubershiporgv1.PlatformMonitoring) {
+	labels := prometheus.Labels{"namespace": cr.GetNamespace(), "name": cr.GetName()}
+	componentReconcileDuration.DeletePartialMatch(labels)
+	componentReconcileErrors.DeletePartialMatch(labels)
+	managedObjects.DeletePartialMatch(labels)
+	lastSuccessfulReconcile.DeletePartialMatch(labels)
+}
+
+// recordBackupMetrics updates metrics of backups of storage nodes with the status of the custom resource
+func recordBackupMetrics(cr *qubershiporgv1.PlatformMonitoring) {
+	var backups []qubershiporgv1.BackupStatus
+	if cr.Status.Storage != nil {
+		backups = cr.Status.Storage.Backups
+	}
+	backupLastSucceeded.DeletePartialMatch(prometheus.Labels{"namespace": cr.GetNamespace()})
+	for _, backup := range backups {
+		switch backup.Phase {
+		case qubershiporgv1.BackupSucceeded:
+			backupLastSucceeded.WithLabelValues(cr.GetNamespace(), backup.Target).Set(1)
+		case qubershiporgv1.BackupFailed:
+			backupLastSucceeded.WithLabelValues(cr.GetNamespace(), backup.Target).Set(0)
+		}
+	}
+	backupAge.set(cr.GetNamespace(), backups)
+}
+
+// errorReason returns a short reason of the component failure to use as a label value
+func errorReason(err error) string {
+	switch {
+	case errors.Is(err, utils.ErrComponentTimeout):
+		return "Timeout"
+	case errors.Is(err, utils.ErrComponentPanic):
+		return "Panic"
+	}
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	return "Error"
+}
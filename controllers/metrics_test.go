@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestErrorReason(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "clusterroles"}, "grafana", errors.New("denied"))
+	assert.Equal(t, "Forbidden", errorReason(forbidden))
+	assert.Equal(t, "Forbidden", errorReason(fmt.Errorf("failed to create ClusterRole: %w", forbidden)))
+	assert.Equal(t, "Error", errorReason(errors.New("failed creating Deployment manifest")))
+}
+
+func TestRecordMetrics(t *testing.T) {
+	componentReconcileErrors.Reset()
+	componentReconcileDuration.Reset()
+	managedObjects.Reset()
+	lastSuccessfulReconcile.Reset()
+
+	failed := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "platformmonitoring"}}
+	results := []utils.ComponentResult{
+		{Name: "grafana-operator", Err: errors.New("failed"), Duration: time.Second},
+		{Name: "grafana", SkippedBy: "grafana-operator"},
+		{Name: "pushgateway", Duration: time.Second},
+	}
+	recordMetrics(failed, results, utils.NewResourceTracker(nil, nil), true)
+	succeeded := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "platformmonitoring"}}
+	recordMetrics(succeeded, []utils.ComponentResult{{Name: "pushgateway", Duration: time.Second}}, utils.NewResourceTracker(nil, nil), false)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(componentReconcileErrors.WithLabelValues("monitoring", "platformmonitoring", "grafana-operator", "Error")))
+	assert.Equal(t, 3, testutil.CollectAndCount(componentReconcileDuration))
+	assert.Equal(t, 2, testutil.CollectAndCount(managedObjects))
+	assert.Equal(t, float64(0), testutil.ToFloat64(managedObjects.WithLabelValues("monitoring", "platformmonitoring", "pushgateway")))
+	// The successful reconciliation of one instance doesn't hide the failed one
+	assert.Equal(t, 1, testutil.CollectAndCount(lastSuccessfulReconcile))
+	assert.NotZero(t, testutil.ToFloat64(lastSuccessfulReconcile.WithLabelValues("team-a", "platformmonitoring")))
+
+	deleteMetrics(failed)
+	assert.Equal(t, 1, testutil.CollectAndCount(componentReconcileDuration))
+	assert.Equal(t, 0, testutil.CollectAndCount(componentReconcileErrors))
+	assert.Equal(t, 1, testutil.CollectAndCount(managedObjects))
+	deleteMetrics(succeeded)
+	assert.Equal(t, 0, testutil.CollectAndCount(lastSuccessfulReconcile))
+}
+
+func TestRecordBackupMetrics(t *testing.T) {
+	backupLastSucceeded.Reset()
+	cr := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"}}
+	cr.Status.Storage = &qubershiporgv1.StorageStatus{Backups: []qubershiporgv1.BackupStatus{
+		{Target: "vmstorage-0", Phase: qubershiporgv1.BackupSucceeded, LastSuccessTime: &metav1.Time{Time: time.Now().Add(-time.Hour)}},
+		{Target: "vmstorage-1", Phase: qubershiporgv1.BackupFailed},
+		{Target: "vmstorage-2", Phase: qubershiporgv1.BackupRunning},
+	}}
+	recordBackupMetrics(cr)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(backupLastSucceeded.WithLabelValues("monitoring", "vmstorage-0")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(backupLastSucceeded.WithLabelValues("monitoring", "vmstorage-1")))
+	assert.Equal(t, 1, testutil.CollectAndCount(backupAge))
+	assert.InDelta(t, time.Hour.Seconds(), testutil.ToFloat64(backupAge), 60)
+
+	cr.Status.Storage = nil
+	recordBackupMetrics(cr)
+	assert.Equal(t, 0, testutil.CollectAndCount(backupLastSucceeded))
+	assert.Equal(t, 0, testutil.CollectAndCount(backupAge))
+}
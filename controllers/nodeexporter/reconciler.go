@@ -1,12 +1,15 @@
 package nodeexporter
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	secv1 "github.com/openshift/api/security/v1"
 	pspApi "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -14,13 +17,14 @@ type NodeExporterReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewNodeExporterReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *NodeExporterReconciler {
+func NewNodeExporterReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *NodeExporterReconciler {
 	return &NodeExporterReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("nodeexporter_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("nodeexporter_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -29,7 +33,7 @@ func NewNodeExporterReconciler(c client.Client, s *runtime.Scheme, dc discovery.
 // Creates new daemonset, service, service account, cluster role and cluster role binding if its don't exists.
 // Updates deployment and service in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *NodeExporterReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *NodeExporterReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if cr.Spec.NodeExporter != nil && cr.Spec.NodeExporter.IsInstall() {
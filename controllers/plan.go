@@ -0,0 +1,523 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// planConfigMapKey is the key of the plan ConfigMap with planned changes
+	planConfigMapKey = "plan.yaml"
+	// planConfigMapLimit is the maximal size of planned changes in the ConfigMap.
+	// Manifests of created objects are omitted if changes don't fit into it.
+	planConfigMapLimit = 900 * 1024
+	// redactedValue replaces values of Secrets in planned changes
+	redactedValue = "<redacted>"
+)
+
+// planRequested returns true if the custom resource instance has the PlanAnnotation
+func planRequested(o client.Object) bool {
+	_, ok := o.GetAnnotations()[qubershiporgv1.PlanAnnotation]
+	return ok
+}
+
+// planConfigMapName returns the name of the ConfigMap with planned changes of the custom resource instance
+func planConfigMapName(cr *qubershiporgv1.PlatformMonitoring) string {
+	return cr.GetName() + "-plan"
+}
+
+// plan computes changes which the reconciliation of the custom resource instance would make
+// and reports them in the status and in the ConfigMap. Components are reconciled as usual,
+// but their changes are sent to the API server as dry-run requests, so objects are not changed.
+func (r *PlatformMonitoringReconciler) plan(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) (reconcile.Result, error) {
+	r.Log.Info("Planning started")
+	pc := newPlanClient(r.Client)
+	planner := *r
+	planner.Client = pc
+	// Events are created only for changes which are applied
+	planner.Recorder = nil
+	components := planner.components(utils.NewResourceTracker(cr, nil))
+	engine, err := utils.NewEngine(r.Log, componentTimeout(), components...)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	results := engine.Run(ctx, cr)
+
+	status := &qubershiporgv1.PlanStatus{
+		ObservedGeneration: cr.Generation,
+		LastPlanTime:       metav1.Now(),
+		ConfigMap:          planConfigMapName(cr),
+	}
+	for _, result := range results {
+		if result.Failed() {
+			r.Log.Error(result.Err, "Planning of "+result.Name+" failed", "dependency", result.SkippedBy)
+			status.FailedComponents = append(status.FailedComponents, result.Name)
+		}
+	}
+	changes := pc.changes.sorted(componentNames(components))
+	counts := map[qubershiporgv1.PlanAction]int{}
+	for _, change := range changes {
+		status.Changes = append(status.Changes, change.PlannedChange)
+		counts[change.Action]++
+	}
+	if err = r.writePlanConfigMap(ctx, cr, changes); err != nil {
+		return reconcile.Result{}, err
+	}
+	cr.Status.Plan = status
+	message := fmt.Sprintf("Plan computed: %d to create, %d to update, %d to delete",
+		counts[qubershiporgv1.PlanActionCreate], counts[qubershiporgv1.PlanActionUpdate], counts[qubershiporgv1.PlanActionDelete])
+	r.event(cr, corev1.EventTypeNormal, "Planned", message)
+	if err = r.Client.Status().Update(ctx, cr); err != nil {
+		return reconcile.Result{}, err
+	}
+	r.Log.Info(message, "configMap", status.ConfigMap)
+	if len(status.FailedComponents) > 0 {
+		r.Log.Info("Planning failed. Run planning again.")
+		return reconcile.Result{Requeue: true}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// writePlanConfigMap creates or updates the ConfigMap with field-level differences of planned changes
+func (r *PlatformMonitoringReconciler) writePlanConfigMap(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring, changes []*plannedChange) error {
+	data, err := yaml.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	if len(data) > planConfigMapLimit {
+		for _, change := range changes {
+			change.Object = nil
+		}
+		if data, err = yaml.Marshal(changes); err != nil {
+			return err
+		}
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: planConfigMapName(cr), Namespace: cr.GetNamespace()}}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.SetLabels(map[string]string{
+			"name":                         utils.TruncLabel(cm.GetName()),
+			"app.kubernetes.io/name":       utils.TruncLabel(cm.GetName()),
+			"app.kubernetes.io/managed-by": "monitoring-operator",
+			"app.kubernetes.io/part-of":    "monitoring",
+		})
+		cm.Data = map[string]string{planConfigMapKey: string(data)}
+		return controllerutil.SetControllerReference(cr, cm, r.Scheme)
+	})
+	return err
+}
+
+// clearPlan removes the plan from the status and deletes the ConfigMap with planned changes
+// after the plan is applied
+func (r *PlatformMonitoringReconciler) clearPlan(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) {
+	if cr.Status.Plan == nil {
+		return
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cr.Status.Plan.ConfigMap, Namespace: cr.GetNamespace()}}
+	if cm.GetName() == "" {
+		cm.SetName(planConfigMapName(cr))
+	}
+	if err := r.Client.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Failed to delete ConfigMap with planned changes", "name", cm.GetName())
+	}
+	cr.Status.Plan = nil
+}
+
+// plannedChange is a planned change of an object with field-level differences
+type plannedChange struct {
+	qubershiporgv1.PlannedChange `json:",inline"`
+	// Diff contains current and new values of changed fields of updated objects
+	Diff []fieldDiff `json:"diff,omitempty"`
+	// Object is the manifest of the created object
+	Object map[string]interface{} `json:"object,omitempty"`
+}
+
+// fieldDiff is a difference of a field of the object
+type fieldDiff struct {
+	Path    string      `json:"path"`
+	Current interface{} `json:"current,omitempty"`
+	New     interface{} `json:"new,omitempty"`
+}
+
+// plannedChanges collects changes of all components
+type plannedChanges struct {
+	mu    sync.Mutex
+	keys  []objectKey
+	items map[objectKey]*plannedChange
+}
+
+// add records the change of the object. The object which is created and then updated is still created.
+func (p *plannedChanges) add(key objectKey, change *plannedChange) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prev, ok := p.items[key]
+	if !ok {
+		p.keys = append(p.keys, key)
+	}
+	if ok && prev.Action == qubershiporgv1.PlanActionCreate && change.Action == qubershiporgv1.PlanActionUpdate {
+		prev.Object = change.Object
+		return
+	}
+	p.items[key] = change
+}
+
+// sorted returns changes ordered by components, changes of a component keep the order in which they were made
+func (p *plannedChanges) sorted(components []string) []*plannedChange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rank := make(map[string]int, len(components))
+	for i, name := range components {
+		rank[name] = i
+	}
+	changes := make([]*plannedChange, 0, len(p.keys))
+	for _, key := range p.keys {
+		changes = append(changes, p.items[key])
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return rank[changes[i].Component] < rank[changes[j].Component]
+	})
+	return changes
+}
+
+// planClient sends changes of objects to the API server as dry-run requests, so they are validated
+// and defaulted by the server, but not persisted. Differences between live and changed objects are recorded.
+type planClient struct {
+	client.Client
+	changes   *plannedChanges
+	component string
+}
+
+func newPlanClient(c client.Client) *planClient {
+	return &planClient{Client: c, changes: &plannedChanges{items: map[objectKey]*plannedChange{}}}
+}
+
+// forComponent returns the client which attributes changes to the component
+func (c *planClient) forComponent(component string) *planClient {
+	return &planClient{Client: c.Client, changes: c.changes, component: component}
+}
+
+// DryRun returns true, objects are not changed in the cluster
+func (c *planClient) DryRun() bool {
+	return true
+}
+
+func (c *planClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	return c.record(qubershiporgv1.PlanActionCreate, nil, obj)
+}
+
+func (c *planClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	live, err := c.live(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if err = c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	return c.record(qubershiporgv1.PlanActionUpdate, live, obj)
+}
+
+func (c *planClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	live, err := c.live(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if err = c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	if live == nil {
+		// Server-side apply creates the object if it doesn't exist
+		return c.record(qubershiporgv1.PlanActionCreate, nil, obj)
+	}
+	return c.record(qubershiporgv1.PlanActionUpdate, live, obj)
+}
+
+func (c *planClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	live, err := c.newObject(obj)
+	if err != nil {
+		return err
+	}
+	if err = c.Client.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		return err
+	}
+	if err = c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	return c.record(qubershiporgv1.PlanActionDelete, live, nil)
+}
+
+func (c *planClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	newList, err := c.Scheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return err
+	}
+	list, ok := newList.(client.ObjectList)
+	if !ok {
+		return fmt.Errorf("%T is not a client.ObjectList", newList)
+	}
+	deleteOpts := &client.DeleteAllOfOptions{}
+	deleteOpts.ApplyOptions(opts)
+	if err = c.Client.List(ctx, list, &deleteOpts.ListOptions); err != nil {
+		return err
+	}
+	if err = c.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if live, ok := item.(client.Object); ok {
+			if err = c.record(qubershiporgv1.PlanActionDelete, live, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *planClient) Status() client.SubResourceWriter {
+	return &planSubResourceWriter{SubResourceWriter: c.Client.Status()}
+}
+
+func (c *planClient) SubResource(subResource string) client.SubResourceClient {
+	return &planSubResourceClient{SubResourceClient: c.Client.SubResource(subResource)}
+}
+
+// live returns the current state of the object or nil if it doesn't exist
+func (c *planClient) live(ctx context.Context, obj client.Object) (client.Object, error) {
+	live, err := c.newObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.Client.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return live, nil
+}
+
+// newObject returns an empty object of the same type
+func (c *planClient) newObject(obj client.Object) (client.Object, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	newObj, err := c.Scheme().New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	o, ok := newObj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a client.Object", newObj)
+	}
+	return o, nil
+}
+
+// record adds the change of the object. Updates which don't change fields are skipped.
+// Either the live or the changed object can be nil.
+func (c *planClient) record(action qubershiporgv1.PlanAction, live, changed client.Object) error {
+	obj := changed
+	if obj == nil {
+		obj = live
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	namespaced, err := c.IsObjectNamespaced(obj)
+	if err != nil {
+		return err
+	}
+	ref := qubershiporgv1.ManagedResource{
+		Component:  c.component,
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       obj.GetName(),
+	}
+	if namespaced {
+		ref.Namespace = obj.GetNamespace()
+	}
+	change := &plannedChange{PlannedChange: qubershiporgv1.PlannedChange{ManagedResource: ref, Action: action}}
+	switch action {
+	case qubershiporgv1.PlanActionCreate:
+		if change.Object, err = planManifest(changed); err != nil {
+			return err
+		}
+	case qubershiporgv1.PlanActionUpdate:
+		current, err := planManifest(live)
+		if err != nil {
+			return err
+		}
+		desired, err := planManifest(changed)
+		if err != nil {
+			return err
+		}
+		change.Diff = fieldDiffs("", current, desired)
+		if len(change.Diff) == 0 {
+			return nil
+		}
+		for _, diff := range change.Diff {
+			change.Fields = append(change.Fields, diff.Path)
+		}
+	}
+	if gvk.GroupKind() == (schema.GroupKind{Kind: "Secret"}) {
+		redactSecret(change)
+	}
+	c.changes.add(objectKey{
+		GroupKind:      gvk.GroupKind(),
+		NamespacedName: types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name},
+	}, change)
+	return nil
+}
+
+// planManifest returns the manifest of the object without fields which are set by the API server,
+// the status and the hash of the applied manifest
+func planManifest(obj client.Object) (map[string]interface{}, error) {
+	content, err := objectManifest(obj)
+	if err != nil {
+		return nil, err
+	}
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, utils.AppliedHashAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return content, nil
+}
+
+// fieldDiffs returns differences between current and desired values of fields under the path.
+// Lists of different lengths are compared as a whole.
+func fieldDiffs(path string, current, desired interface{}) []fieldDiff {
+	currentMap, currentIsMap := current.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	if currentIsMap && desiredIsMap {
+		keys := make(map[string]struct{}, len(currentMap)+len(desiredMap))
+		for k := range currentMap {
+			keys[k] = struct{}{}
+		}
+		for k := range desiredMap {
+			keys[k] = struct{}{}
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+		var diffs []fieldDiff
+		for _, k := range sortedKeys {
+			diffs = append(diffs, fieldDiffs(fieldPath(path, k), currentMap[k], desiredMap[k])...)
+		}
+		return diffs
+	}
+	currentList, currentIsList := current.([]interface{})
+	desiredList, desiredIsList := desired.([]interface{})
+	if currentIsList && desiredIsList && len(currentList) == len(desiredList) {
+		var diffs []fieldDiff
+		for i := range currentList {
+			diffs = append(diffs, fieldDiffs(path+"["+strconv.Itoa(i)+"]", currentList[i], desiredList[i])...)
+		}
+		return diffs
+	}
+	if reflect.DeepEqual(current, desired) {
+		return nil
+	}
+	return []fieldDiff{{Path: path, Current: current, New: desired}}
+}
+
+// fieldPath returns the path of the field of the object under the path,
+// fields with dots (e.g. labels) are quoted
+func fieldPath(path, field string) string {
+	if strings.ContainsAny(field, "./") {
+		return path + "['" + field + "']"
+	}
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// redactSecret hides values of the Secret in the planned change
+func redactSecret(change *plannedChange) {
+	for i := range change.Diff {
+		if strings.HasPrefix(change.Diff[i].Path, "data") || strings.HasPrefix(change.Diff[i].Path, "stringData") {
+			if change.Diff[i].Current != nil {
+				change.Diff[i].Current = redactedValue
+			}
+			if change.Diff[i].New != nil {
+				change.Diff[i].New = redactedValue
+			}
+		}
+	}
+	for _, field := range []string{"data", "stringData"} {
+		if values, ok := change.Object[field].(map[string]interface{}); ok {
+			for k := range values {
+				values[k] = redactedValue
+			}
+		}
+	}
+}
+
+// planSubResourceWriter sends changes of subresources as dry-run requests
+type planSubResourceWriter struct {
+	client.SubResourceWriter
+}
+
+func (w *planSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return w.SubResourceWriter.Create(ctx, obj, subResource, append(opts, client.DryRunAll)...)
+}
+
+func (w *planSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.SubResourceWriter.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (w *planSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.SubResourceWriter.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+// planSubResourceClient sends changes of subresources as dry-run requests
+type planSubResourceClient struct {
+	client.SubResourceClient
+}
+
+func (c *planSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return c.SubResourceClient.Create(ctx, obj, subResource, append(opts, client.DryRunAll)...)
+}
+
+func (c *planSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return c.SubResourceClient.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *planSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return c.SubResourceClient.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestPlanClient(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{appsv1.SchemeGroupVersion, corev1.SchemeGroupVersion})
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana-deployment", Namespace: "monitoring",
+			Labels: map[string]string{"app.kubernetes.io/name": "grafana"}},
+		Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana-admin-credentials", Namespace: "monitoring"},
+		Data:       map[string][]byte{"password": []byte("old")},
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithRESTMapper(mapper).
+		WithObjects(deployment, secret).Build()
+	ctx := context.Background()
+	pc := newPlanClient(c)
+	grafanaClient := pc.forComponent(grafanaComponent)
+	assert.True(t, utils.IsDryRun(utils.NewResourceTracker(nil, nil).Client(grafanaClient, grafanaComponent)))
+
+	// Changes are attributed to the component which made them
+	assert.NoError(t, pc.forComponent(pushgatewayComponent).Create(ctx,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "pushgateway", Namespace: "monitoring"}}))
+
+	updated := deployment.DeepCopy()
+	updated.Spec.Replicas = ptr.To(int32(2))
+	updated.Labels["app.kubernetes.io/version"] = "v1"
+	assert.NoError(t, grafanaClient.Update(ctx, updated))
+
+	unchanged := &appsv1.Deployment{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), unchanged))
+	assert.Equal(t, int32(1), *unchanged.Spec.Replicas)
+	// The object without changes is not planned
+	assert.NoError(t, grafanaClient.Update(ctx, unchanged))
+
+	newSecret := secret.DeepCopy()
+	newSecret.Data["password"] = []byte("new")
+	assert.NoError(t, grafanaClient.Update(ctx, newSecret))
+
+	applied := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "grafana-dashboards", Namespace: "monitoring"}}
+	applied.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	assert.NoError(t, grafanaClient.Patch(ctx, applied, client.Apply, client.FieldOwner(utils.FieldManager)))
+
+	assert.NoError(t, grafanaClient.Delete(ctx, deployment.DeepCopy()))
+	err := grafanaClient.Delete(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "absent", Namespace: "monitoring"}})
+	assert.True(t, errors.IsNotFound(err))
+
+	// Objects are not changed in the cluster
+	assert.True(t, errors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: "monitoring", Name: "pushgateway"}, &corev1.ConfigMap{})))
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}))
+	current := &corev1.Secret{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(secret), current))
+	assert.Equal(t, []byte("old"), current.Data["password"])
+
+	changes := pc.changes.sorted([]string{grafanaComponent, pushgatewayComponent})
+	var planned []qubershiporgv1.PlannedChange
+	for _, change := range changes {
+		planned = append(planned, change.PlannedChange)
+	}
+	assert.Equal(t, []qubershiporgv1.PlannedChange{
+		{
+			ManagedResource: qubershiporgv1.ManagedResource{Component: grafanaComponent, APIVersion: "apps/v1", Kind: "Deployment",
+				Namespace: "monitoring", Name: "grafana-deployment"},
+			Action: qubershiporgv1.PlanActionDelete,
+		},
+		{
+			ManagedResource: qubershiporgv1.ManagedResource{Component: grafanaComponent, APIVersion: "v1", Kind: "Secret",
+				Namespace: "monitoring", Name: "grafana-admin-credentials"},
+			Action: qubershiporgv1.PlanActionUpdate,
+			Fields: []string{"data.password"},
+		},
+		{
+			ManagedResource: qubershiporgv1.ManagedResource{Component: grafanaComponent, APIVersion: "v1", Kind: "ConfigMap",
+				Namespace: "monitoring", Name: "grafana-dashboards"},
+			Action: qubershiporgv1.PlanActionCreate,
+		},
+		{
+			ManagedResource: qubershiporgv1.ManagedResource{Component: pushgatewayComponent, APIVersion: "v1", Kind: "ConfigMap",
+				Namespace: "monitoring", Name: "pushgateway"},
+			Action: qubershiporgv1.PlanActionCreate,
+		},
+	}, planned)
+	// Values of Secrets are hidden
+	assert.Equal(t, []fieldDiff{{Path: "data.password", Current: redactedValue, New: redactedValue}}, changes[1].Diff)
+	assert.NotNil(t, changes[2].Object)
+}
+
+func TestPlanClientUpdateDiff(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{appsv1.SchemeGroupVersion})
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana-deployment", Namespace: "monitoring",
+			Labels: map[string]string{"app.kubernetes.io/name": "grafana"}},
+		Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithRESTMapper(mapper).WithObjects(deployment).Build()
+	pc := newPlanClient(c)
+
+	updated := deployment.DeepCopy()
+	updated.Spec.Replicas = ptr.To(int32(2))
+	updated.Labels["app.kubernetes.io/version"] = "v1"
+	updated.Annotations = map[string]string{utils.AppliedHashAnnotation: "hash"}
+	assert.NoError(t, pc.forComponent(grafanaComponent).Update(context.Background(), updated))
+
+	changes := pc.changes.sorted([]string{grafanaComponent})
+	if assert.Len(t, changes, 1) {
+		// The hash of the applied manifest is not a change of the object
+		assert.Equal(t, []string{"metadata.labels['app.kubernetes.io/version']", "spec.replicas"}, changes[0].Fields)
+		assert.Equal(t, fieldDiff{Path: "spec.replicas", Current: int64(1), New: int64(2)}, changes[0].Diff[1])
+	}
+}
+
+func TestFieldDiffs(t *testing.T) {
+	current := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "grafana", "image": "grafana:1"},
+			},
+			"args":    []interface{}{"--a"},
+			"removed": "value",
+		},
+	}
+	desired := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "grafana", "image": "grafana:2"},
+			},
+			"args": []interface{}{"--a", "--b"},
+		},
+	}
+	assert.Equal(t, []fieldDiff{
+		{Path: "spec.args", Current: []interface{}{"--a"}, New: []interface{}{"--a", "--b"}},
+		{Path: "spec.containers[0].image", Current: "grafana:1", New: "grafana:2"},
+		{Path: "spec.removed", Current: "value"},
+	}, fieldDiffs("", current, desired))
+	assert.Empty(t, fieldDiffs("", current, current))
+}
+
+func TestPlanConfigMap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := qubershiporgv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cr := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring", UID: "uid"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr).Build()
+	r := &PlatformMonitoringReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}
+	ctx := context.Background()
+	changes := []*plannedChange{{
+		PlannedChange: qubershiporgv1.PlannedChange{
+			ManagedResource: qubershiporgv1.ManagedResource{Component: grafanaComponent, APIVersion: "apps/v1", Kind: "Deployment",
+				Namespace: "monitoring", Name: "grafana-deployment"},
+			Action: qubershiporgv1.PlanActionUpdate,
+			Fields: []string{"spec.replicas"},
+		},
+		Diff: []fieldDiff{{Path: "spec.replicas", Current: int64(1), New: int64(2)}},
+	}}
+	assert.NoError(t, r.writePlanConfigMap(ctx, cr, changes))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "monitoring", Name: "platformmonitoring-plan"}, cm))
+	assert.Equal(t, `- action: Update
+  apiVersion: apps/v1
+  component: grafana
+  diff:
+  - current: 1
+    new: 2
+    path: spec.replicas
+  fields:
+  - spec.replicas
+  kind: Deployment
+  name: grafana-deployment
+  namespace: monitoring
+`, cm.Data[planConfigMapKey])
+	assert.Len(t, cm.GetOwnerReferences(), 1)
+
+	cr.Status.Plan = &qubershiporgv1.PlanStatus{ConfigMap: cm.GetName()}
+	r.clearPlan(ctx, cr)
+	assert.Nil(t, cr.Status.Plan)
+	assert.True(t, errors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})))
+}
+
+func TestPlanPredicate(t *testing.T) {
+	old := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Generation: 1}}
+	planned := old.DeepCopy()
+	planned.SetAnnotations(map[string]string{qubershiporgv1.PlanAnnotation: "true"})
+	p := ignoreDeletionPredicate()
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: planned}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: planned, ObjectNew: old}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: planned, ObjectNew: planned.DeepCopy()}))
+}
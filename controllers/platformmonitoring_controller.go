@@ -22,35 +22,19 @@ import (
 	"time"
 
 	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/alertmanager"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/etcd"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/grafana"
-	grafanaoperator "github.com/Netcracker/qubership-monitoring-operator/controllers/grafana-operator"
-	kubernetesmonitors "github.com/Netcracker/qubership-monitoring-operator/controllers/kubernetes-monitors"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/kubestatemetrics"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/nodeexporter"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus"
-	prometheusoperator "github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus-operator"
-	prometheusrules "github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus-rules"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/pushgateway"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmagent"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmalert"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmalertmanager"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmauth"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmcluster"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmoperator"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmsingle"
-	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmuser"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -64,6 +48,10 @@ type PlatformMonitoringReconciler struct {
 	Config *rest.Config
 	// Client to discovery cluster API
 	DiscoveryClient discovery.DiscoveryInterface
+	// Recorder creates events for PlatformMonitoring
+	Recorder record.EventRecorder
+	// owned maps watched objects to components which manage them
+	owned *ownedObjects
 }
 
 // +kubebuilder:rbac:groups=monitoring.qubership.org,resources=platformmonitorings,verbs=get;list;watch;create;update;patch;delete
@@ -86,203 +74,65 @@ func (r *PlatformMonitoringReconciler) Reconcile(context context.Context, reques
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Owned objects are automatically garbage collected, other objects are deleted by the finalizer.
 			// Return and don't requeue
 			return reconcile.Result{Requeue: false}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
-	customResourceInstance.FillEmptyWithDefaults()
-
-	r.Log.Info("Reconciliation started")
-	if err = r.updateStatus(customResourceInstance, "In progress", "False", "ReconcileCycleStatus", "Monitoring service reconcile cycle in progress"); err != nil {
-		r.Log.Error(err, "Error while update status")
-	}
-
-	// Prometheus Operator should create first because it should create CRDs:
-	// * Prometheus
-	// * ServiceMonitor
-	// * PodMonitor
-	// * Alertmanager
-	// * PrometheusRule
-	poReconciler := prometheusoperator.NewPrometheusOperatorReconciler(r.Client, r.Scheme)
-	err = poReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of prometheus-operator failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcilePrometheusOperatorStatus", "Prometheus operator reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcilePrometheusOperatorStatus")
-	}
-
-	etcdReconciler := etcd.NewEtcdMonitorReconciler(r.Client, r.Scheme, r.DiscoveryClient, r.Config)
-	err = etcdReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of etcd-monitor failed")
-	}
-
-	kubernetesMonitorsReconciler := kubernetesmonitors.NewKubernetesMonitorsReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = kubernetesMonitorsReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of kubernetes-monitors failed")
-	}
-
-	// Reconcile Victoriametrics Operator custom resources
-	vmoReconciler := vmoperator.NewVmOperatorReconciler(r.Client, r.Scheme, r.Config, r.DiscoveryClient)
-	err = vmoReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vm-operator failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsOperatorStatus", "Victoriametrics operator reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsOperatorStatus")
-	}
-
-	// Reconcile vmSingle Operator custom resources
-	vmSingleReconciler := vmsingle.NewVmSingleReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = vmSingleReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vmsingle failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsSingleStatus", "Victoriametrics Single reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsSingleStatus")
-	}
-
-	// Reconcile VmCluster Operator custom resources
-	vmclusterReconciler := vmcluster.NewVmClusterReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = vmclusterReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vmcluster failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsClusterStatus", "Victoriametrics Cluster reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsClusterStatus")
-	}
-
-	// Reconcile vmUser Operator custom resources
-	vmUserReconciler := vmuser.NewVmUserReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = vmUserReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vmuser failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsUserStatus", "Victoriametrics User reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsUserStatus")
+	if !customResourceInstance.GetDeletionTimestamp().IsZero() {
+		return r.finalize(context, customResourceInstance)
 	}
-
-	// Reconcile VmAgent Operator custom resources
-	vmagentReconciler := vmagent.NewVmAgentReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = vmagentReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vmagent failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsAgentStatus", "Victoriametrics Agent reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsAgentStatus")
-	}
-
-	// Reconcile vmAuth Operator custom resources
-	vmAuthReconciler := vmauth.NewVmAuthReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = vmAuthReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vmauth failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsAuthStatus", "Victoriametrics Auth reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsAuthStatus")
-	}
-
-	//Reconcile Prometheus Operator custom resources
-	pReconciler := prometheus.NewPrometheusReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = pReconciler.Run(customResourceInstance)
+	active, err := r.activeInstance(context, customResourceInstance)
 	if err != nil {
-		r.Log.Error(err, "Reconciliation of prometheus custom resources failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcilePrometheusStatus", "Prometheus reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcilePrometheusStatus")
-	}
-
-	// Reconcile vmAlertManager custom resources
-	vmAlertManagerReconciler := vmalertmanager.NewVmAlertManagerReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = vmAlertManagerReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vmalertmanager failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsAlertManagerStatus", "Victoriametrics Alert Manager reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsAlertManagerStatus")
-	}
-
-	aReconciler := alertmanager.NewAlertManagerReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = aReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of alertmanager custom resources failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileAlertManagerStatus", "AlertManager reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileAlertManagerStatus")
+		return reconcile.Result{}, err
 	}
-
-	// Reconcile vmAlert custom resources
-	vmAlertReconciler := vmalert.NewVmAlertReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = vmAlertReconciler.Run(context, customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of vmalert failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileVictoriametricsAlertStatus", "Victoriametrics Alert reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileVictoriametricsAlertStatus")
+	if active != customResourceInstance.GetName() {
+		r.Log.Info("Another PlatformMonitoring already manages the namespace, skip reconciliation", "active", active)
+		return reconcile.Result{}, r.rejectDuplicateInstance(context, customResourceInstance, active)
 	}
-
-	//// Reconcile Exporters
-	ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = ksmReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of kube-state-metrics failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileKubeStateMetricsStatus", "KubeStateMetrics reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileKubeStateMetricsStatus")
+	if err = r.ensureFinalizer(context, customResourceInstance); err != nil {
+		return reconcile.Result{}, err
 	}
+	r.applyProfile(context, customResourceInstance)
+	customResourceInstance.FillEmptyWithDefaults()
 
-	neReconciler := nodeexporter.NewNodeExporterReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = neReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of node-exporter failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileNodeExporterStatus", "NodeExporter reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileNodeExporterStatus")
+	if planRequested(customResourceInstance) {
+		return r.plan(context, customResourceInstance)
 	}
 
-	//// Grafana Operator should create first because it should create CRDs:
-	//// * Grafana
-	//// * GrafanaDatasource
-	//// * GrafanaDashboard
-	goReconciler := grafanaoperator.NewGrafanaOperatorReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = goReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of grafana-operator failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileGrafanaOperatorStatus", "Grafana operator reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileGrafanaOperatorStatus")
+	r.Log.Info("Reconciliation started")
+	if err = r.startReconcileStatus(context, customResourceInstance); err != nil {
+		r.Log.Error(err, "Error while update status")
 	}
 
-	gReconciler := grafana.NewGrafanaReconciler(r.Client, r.Scheme, r.DiscoveryClient, r.Config)
-	err = gReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of grafana custom resources failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileGrafanaStatus", "Grafana reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcileGrafanaStatus")
+	tracker := utils.NewResourceTracker(customResourceInstance, r.Recorder)
+	components := r.components(tracker)
+	// Only components which objects were changed are reconciled if the spec was not changed since the last reconciliation.
+	// All components are reconciled to apply the plan.
+	selected := r.owned.take(request.NamespacedName)
+	partial := selected != nil && customResourceInstance.Generation == customResourceInstance.Status.ObservedGeneration &&
+		customResourceInstance.Status.Plan == nil
+	if partial {
+		components = utils.SelectComponents(components, selected)
+		r.Log.Info("Reconciliation of changed components", "components", componentNames(components))
 	}
-
-	prReconciler := prometheusrules.NewPrometheusRulesReconciler(r.Client, r.Scheme)
-	err = prReconciler.Run(customResourceInstance)
+	engine, err := utils.NewEngine(r.Log, componentTimeout(), components...)
 	if err != nil {
-		r.Log.Error(err, "Reconciliation of prometheus rules custom resources failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcilePrometheusRulesStatus", "Prometheus Rules reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcilePrometheusRulesStatus")
+		return reconcile.Result{}, err
 	}
-
-	pgReconciler := pushgateway.NewPushgatewayReconciler(r.Client, r.Scheme, r.DiscoveryClient)
-	err = pgReconciler.Run(customResourceInstance)
-	if err != nil {
-		r.Log.Error(err, "Reconciliation of pushgateway failed")
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcilePushgatewayStatus", "Pushgateway reconcile cycle failed")
-	} else {
-		r.removeStatus(customResourceInstance, "ReconcilePushgatewayStatus")
+	results := engine.Run(context, customResourceInstance)
+	names := componentNames(components)
+	customResourceInstance.Status.ManagedResources = tracker.Merge(customResourceInstance.Status.ManagedResources, names)
+	r.owned.update(request.NamespacedName, names, tracker)
+	for _, result := range results {
+		switch {
+		case result.Skipped():
+			r.Log.Info("Reconciliation of "+result.Name+" skipped", "dependency", result.SkippedBy)
+		case result.Err != nil:
+			r.Log.Error(result.Err, "Reconciliation of "+result.Name+" failed")
+		}
 	}
 
 	rInterval, err := strconv.ParseInt(utils.GetEnvWithDefaultValue("RECONCILIATION_INTERVAL"), 10, 64)
@@ -290,50 +140,62 @@ func (r *PlatformMonitoringReconciler) Reconcile(context context.Context, reques
 		return reconcile.Result{}, err
 	}
 
-	status := customResourceInstance.Status.Conditions
-	failedStatus := false
-
-	for i := range status {
-		if status[i].Type == "Failed" {
-			failedStatus = true
-			break
-		}
+	degraded := r.finishReconcileStatus(context, customResourceInstance, results, partial)
+	r.clearPlan(context, customResourceInstance)
+	recordMetrics(customResourceInstance, results, tracker, degraded)
+	recordBackupMetrics(customResourceInstance)
+	if err = r.Client.Status().Update(context, customResourceInstance); err != nil {
+		r.Log.Error(err, "Update status failed")
 	}
 
-	if failedStatus {
-		r.prepareStatusForUpdate(customResourceInstance, "Failed", "False", "ReconcileCycleStatus", "Monitoring service reconcile cycle failed")
-
-		if err = r.Client.Status().Update(context, customResourceInstance); err != nil {
-			r.Log.Error(err, "Update status failed.")
-		}
-
+	if degraded {
 		r.Log.Info("Reconciliation failed. Run reconciliation again.")
 		return reconcile.Result{Requeue: true}, nil
 	}
 
-	r.prepareStatusForUpdate(customResourceInstance, "Successful", "True", "ReconcileCycleStatus", "Monitoring service reconcile cycle succeeded")
-	r.Log.Info("Reconciliation finished successful, next reconciliation after " + utils.GetEnvWithDefaultValue("RECONCILIATION_INTERVAL") + " seconds")
-
-	if err = r.Client.Status().Update(context, customResourceInstance); err != nil {
-		r.Log.Error(err, "Update status failed")
+	if (dataMigrationInProgress(customResourceInstance) || backupInProgress(customResourceInstance)) &&
+		(rInterval <= 0 || time.Duration(rInterval)*time.Second > dataMigrationPollInterval) {
+		r.Log.Info("Data migration or backup is in progress, next reconciliation after " + dataMigrationPollInterval.String())
+		return reconcile.Result{RequeueAfter: dataMigrationPollInterval}, nil
 	}
 
+	if rInterval <= 0 {
+		// Periodic reconciliation is disabled, changes of managed objects are handled by watches
+		r.Log.Info("Reconciliation finished successful")
+		return reconcile.Result{}, nil
+	}
+	r.Log.Info("Reconciliation finished successful, next reconciliation after " + utils.GetEnvWithDefaultValue("RECONCILIATION_INTERVAL") + " seconds")
 	return reconcile.Result{RequeueAfter: time.Duration(rInterval) * time.Second}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Objects managed by components are watched, so changes made by other managers are reverted
+// without waiting for the next periodic reconciliation. Changes of ConfigMaps with platform profiles
+// are applied to instances in the same namespace. Changes of namespaces selected by tenants are applied to vmagent.
 func (r *PlatformMonitoringReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&qubershiporgv1.PlatformMonitoring{}).
-		WithEventFilter(ignoreDeletionPredicate()).
-		Complete(r)
+	r.owned = newOwnedObjects()
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&qubershiporgv1.PlatformMonitoring{}, builder.WithPredicates(ignoreDeletionPredicate())).
+		Watches(&qubershiporgv1.PlatformMonitoring{}, handler.EnqueueRequestsFromMapFunc(r.mapOtherInstances),
+			builder.WithPredicates(instanceDeletedPredicate())).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapProfileConfigMap),
+			builder.WithPredicates(profileConfigMapPredicate()))
+	if utils.PrivilegedRights {
+		// Namespaces can be selected by tenants of VictoriaMetrics only with privileged rights
+		b = b.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapTenantNamespace),
+			builder.WithPredicates(tenantNamespacePredicate()))
+	}
+	return r.watchOwnedObjects(b, mgr).Complete(r)
 }
 
 func ignoreDeletionPredicate() predicate.Predicate {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			// Ignore updates to CR status in which case metadata.Generation does not change
-			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+			// Ignore updates to CR status in which case metadata.Generation does not change,
+			// but handle the start of deletion to run the finalizer and the start or the end of planning
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				(e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()) ||
+				planRequested(e.ObjectOld) != planRequested(e.ObjectNew)
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			// Evaluates to false if the object has been confirmed deleted.
@@ -342,71 +204,19 @@ func ignoreDeletionPredicate() predicate.Predicate {
 	}
 }
 
-// getCondition retrieves condition of custom resource instance by given reason.
-// Returns found condition and it's index in the list of conditions.
-func (r *PlatformMonitoringReconciler) getCondition(customResourceInstance *qubershiporgv1.PlatformMonitoring, newConditionReason string) (int, *qubershiporgv1.PlatformMonitoringCondition) {
-
-	if len(newConditionReason) == 0 || len(customResourceInstance.Status.Conditions) == 0 {
-		return -1, nil
-	}
-	for i := range customResourceInstance.Status.Conditions {
-		if customResourceInstance.Status.Conditions[i].Reason == newConditionReason {
-			return i, &customResourceInstance.Status.Conditions[i]
-		}
-	}
-	return -1, nil
-}
-
-// removeStatus removes condition of custom resource instance by given reason.
-func (r *PlatformMonitoringReconciler) removeStatus(customResourceInstance *qubershiporgv1.PlatformMonitoring, reason string) bool {
-	idx, condition := r.getCondition(customResourceInstance, reason)
-	if condition != nil {
-		customResourceInstance.Status.Conditions[idx] = customResourceInstance.Status.Conditions[len(customResourceInstance.Status.Conditions)-1]
-		customResourceInstance.Status.Conditions = customResourceInstance.Status.Conditions[:len(customResourceInstance.Status.Conditions)-1]
-		return true
-	}
-	return false
-}
-
-// updateStatus updates condition of custom resource instance
-func (r *PlatformMonitoringReconciler) updateStatus(customResourceInstance *qubershiporgv1.PlatformMonitoring, statusType string, status string, reason string, message string) error {
-	if r.prepareStatusForUpdate(customResourceInstance, statusType, status, reason, message) {
-		// Update status if not equal to the last one
-		if err := r.Client.Status().Update(context.TODO(), customResourceInstance); err != nil {
-			r.Log.Error(err, "Update status failed")
-			return err
-		}
+// componentNames returns names of components
+func componentNames(components []utils.Component) []string {
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		names = append(names, c.Name)
 	}
-	return nil
+	return names
 }
 
-// prepareStatusForUpdate checks if old condition with same reason exist and change it on the new condition
-func (r *PlatformMonitoringReconciler) prepareStatusForUpdate(customResourceInstance *qubershiporgv1.PlatformMonitoring, statusType string, status string, reason string, message string) bool {
-	// get status timestamp
-	transitionTime := metav1.Now()
-	newCondition := qubershiporgv1.PlatformMonitoringCondition{
-		Type:               statusType,
-		Status:             status,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: transitionTime.String(),
-	}
-	idx, oldCondition := r.getCondition(customResourceInstance, newCondition.Reason)
-
-	if oldCondition == nil {
-		customResourceInstance.Status.Conditions = append(customResourceInstance.Status.Conditions, newCondition)
-		return true
-	}
-
-	isEqual := newCondition.Type == oldCondition.Type &&
-		newCondition.Status == oldCondition.Status &&
-		newCondition.Reason == oldCondition.Reason &&
-		newCondition.Message == oldCondition.Message &&
-		newCondition.LastTransitionTime == oldCondition.LastTransitionTime
-
-	if !isEqual {
-		//replace old status
-		customResourceInstance.Status.Conditions[idx] = newCondition
+// event creates an event for the custom resource if the Recorder is set
+func (r *PlatformMonitoringReconciler) event(cr *qubershiporgv1.PlatformMonitoring, eventType, reason, message string) {
+	if r.Recorder == nil {
+		return
 	}
-	return !isEqual
+	r.Recorder.Event(cr, eventType, reason, message)
 }
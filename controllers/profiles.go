@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/profiles"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	reasonInvalidPlatformProfile = "InvalidPlatformProfile"
+	reasonUnknownPlatformProfile = "UnknownPlatformProfile"
+)
+
+// applyProfile applies the platform profile chosen by publicCloudName to the custom resource instance.
+// Profiles are loaded on each reconciliation, so changes of ConfigMaps with profiles are applied without restarts.
+// Invalid and unknown profiles are reported with events, the custom resource is reconciled without them.
+func (r *PlatformMonitoringReconciler) applyProfile(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) {
+	if cr.Spec.PublicCloudName == "" {
+		return
+	}
+	registry, err := profiles.Load(ctx, r.Client, cr.GetNamespace())
+	if err != nil {
+		r.Log.Error(err, "Invalid platform profiles are skipped")
+		r.event(cr, corev1.EventTypeWarning, reasonInvalidPlatformProfile, err.Error())
+	}
+	profile, ok := registry[cr.Spec.PublicCloudName]
+	if !ok {
+		message := fmt.Sprintf("platform profile %s is not found, settings of the custom resource are used as is", cr.Spec.PublicCloudName)
+		r.Log.Info(message)
+		r.event(cr, corev1.EventTypeWarning, reasonUnknownPlatformProfile, message)
+		return
+	}
+	if err = profile.Apply(cr); err != nil {
+		r.Log.Error(err, "Can not apply platform profile", "profile", profile.Name)
+		r.event(cr, corev1.EventTypeWarning, reasonInvalidPlatformProfile, err.Error())
+		return
+	}
+	r.Log.Info("Platform profile applied", "profile", profile.Name)
+}
+
+// mapProfileConfigMap returns requests for PlatformMonitoring instances in the namespace of the changed ConfigMap
+// with platform profiles. Instances are reconciled completely, because profiles can change any component.
+func (r *PlatformMonitoringReconciler) mapProfileConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &qubershiporgv1.PlatformMonitoringList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list PlatformMonitoring instances", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		if item.Spec.PublicCloudName == "" {
+			continue
+		}
+		name := types.NamespacedName{Namespace: item.GetNamespace(), Name: item.GetName()}
+		r.owned.reconcileFully(name)
+		requests = append(requests, reconcile.Request{NamespacedName: name})
+	}
+	return requests
+}
+
+// profileConfigMapPredicate passes only ConfigMaps with platform profiles
+func profileConfigMapPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, ok := obj.GetLabels()[profiles.Label]
+		return ok
+	})
+}
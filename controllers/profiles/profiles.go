@@ -0,0 +1,233 @@
+package profiles
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Label marks ConfigMaps with platform profiles in the namespace of the custom resource.
+// Each key of the ConfigMap with the .yaml or .yml suffix contains a profile.
+const Label = "monitoring.qubership.org/platform-profile"
+
+//go:embed assets/*.yaml
+var assets embed.FS
+
+// Profile declares which monitors, groups of rules and dashboards apply to a platform
+// and default settings of the monitoring on the platform
+type Profile struct {
+	// Name is chosen by publicCloudName of the custom resource
+	Name string `json:"name"`
+	// Description of the platform
+	Description string `json:"description,omitempty"`
+	// Monitors from kubernetesMonitors which are installed (true) or not installed (false)
+	// regardless of the custom resource
+	Monitors map[string]bool `json:"monitors,omitempty"`
+	// RuleGroups which are added to (true) or removed from (false) prometheusRules.ruleGroups
+	RuleGroups map[string]bool `json:"ruleGroups,omitempty"`
+	// Dashboards which are added to (true) or removed from (false) grafanaDashboards.list
+	Dashboards map[string]bool `json:"dashboards,omitempty"`
+	// Defaults are parameters of the spec of the custom resource which are used if they are not set
+	// in the custom resource
+	Defaults map[string]interface{} `json:"defaults,omitempty"`
+}
+
+// Registry contains platform profiles by names
+type Registry map[string]*Profile
+
+// Parse returns the profile from the YAML document.
+// Unknown fields of the profile and of its defaults are rejected.
+func Parse(data []byte) (*Profile, error) {
+	p := &Profile{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, err
+	}
+	if p.Name == "" {
+		return nil, errors.New("name of the profile is empty")
+	}
+	if len(p.Defaults) > 0 {
+		data, err := json.Marshal(p.Defaults)
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(&v1alpha1.PlatformMonitoringSpec{}); err != nil {
+			return nil, fmt.Errorf("invalid defaults of profile %s: %w", p.Name, err)
+		}
+	}
+	return p, nil
+}
+
+// Builtin returns profiles embedded into the operator
+func Builtin() (Registry, error) {
+	files, err := fs.Glob(assets, "assets/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	registry := make(Registry, len(files))
+	for _, file := range files {
+		data, err := assets.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		p, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Base(file), err)
+		}
+		registry[p.Name] = p
+	}
+	return registry, nil
+}
+
+// Load returns built-in profiles and profiles from ConfigMaps with the Label in the namespace.
+// Profiles from ConfigMaps replace built-in profiles with the same names. ConfigMaps are read in the order
+// of their names, and the first profile with the name is used if several ConfigMaps contain it.
+// Invalid profiles are skipped and reported with the error together with the registry of valid profiles.
+func Load(ctx context.Context, c client.Reader, namespace string) (Registry, error) {
+	registry, err := Builtin()
+	if err != nil {
+		return nil, err
+	}
+	list := &corev1.ConfigMapList{}
+	if err = c.List(ctx, list, client.InNamespace(namespace), client.HasLabels{Label}); err != nil {
+		return registry, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+
+	var errs []error
+	loaded := map[string]string{}
+	for _, cm := range list.Items {
+		keys := make([]string, 0, len(cm.Data))
+		for key := range cm.Data {
+			if strings.HasSuffix(key, ".yaml") || strings.HasSuffix(key, ".yml") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			source := "ConfigMap " + cm.GetName() + ", key " + key
+			p, err := Parse([]byte(cm.Data[key]))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", source, err))
+				continue
+			}
+			if other, ok := loaded[p.Name]; ok {
+				errs = append(errs, fmt.Errorf("%s: profile %s is already defined in %s", source, p.Name, other))
+				continue
+			}
+			loaded[p.Name] = source
+			registry[p.Name] = p
+		}
+	}
+	return registry, errors.Join(errs...)
+}
+
+// Apply changes the spec of the custom resource according to the profile. Defaults of the profile are set
+// for parameters which are not set, then monitors, groups of rules and dashboards of the profile are
+// enabled or disabled regardless of the custom resource. Components get the changed spec, so they don't
+// depend on profiles.
+func (p *Profile) Apply(cr *v1alpha1.PlatformMonitoring) error {
+	if err := p.applyDefaults(cr); err != nil {
+		return err
+	}
+	if len(p.Monitors) > 0 && cr.Spec.KubernetesMonitors == nil {
+		cr.Spec.KubernetesMonitors = make(map[string]v1alpha1.Monitor, len(p.Monitors))
+	}
+	for name, install := range p.Monitors {
+		monitor := cr.Spec.KubernetesMonitors[name]
+		monitor.Install = ptr.To(install)
+		cr.Spec.KubernetesMonitors[name] = monitor
+	}
+	if cr.Spec.PrometheusRules != nil {
+		cr.Spec.PrometheusRules.RuleGroups = applyList(cr.Spec.PrometheusRules.RuleGroups, p.RuleGroups)
+	}
+	if cr.Spec.GrafanaDashboards != nil {
+		cr.Spec.GrafanaDashboards.List = applyList(cr.Spec.GrafanaDashboards.List, p.Dashboards)
+	}
+	return nil
+}
+
+// applyDefaults sets defaults of the profile for parameters which are not set in the custom resource
+func (p *Profile) applyDefaults(cr *v1alpha1.PlatformMonitoring) error {
+	if len(p.Defaults) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(cr.Spec)
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{}
+	if err = json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if data, err = json.Marshal(mergeDefaults(p.Defaults, values)); err != nil {
+		return err
+	}
+	// Fields which are not serialized are kept, because the spec is decoded into its copy
+	spec := cr.Spec.DeepCopy()
+	if err = json.Unmarshal(data, spec); err != nil {
+		return fmt.Errorf("can not apply defaults of profile %s: %w", p.Name, err)
+	}
+	cr.Spec = *spec
+	return nil
+}
+
+// mergeDefaults returns values with defaults for keys which are not set. Objects are merged recursively,
+// lists are replaced as a whole. Null values and empty strings are considered as not set.
+func mergeDefaults(defaults, values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(defaults)+len(values))
+	for k, v := range defaults {
+		result[k] = v
+	}
+	for k, v := range values {
+		if v == nil || v == "" {
+			continue
+		}
+		if d, ok := result[k].(map[string]interface{}); ok {
+			if m, ok := v.(map[string]interface{}); ok {
+				result[k] = mergeDefaults(d, m)
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// applyList removes disabled items from the list and appends enabled items which are not in the list
+func applyList(list []string, items map[string]bool) []string {
+	if len(items) == 0 {
+		return list
+	}
+	result := make([]string, 0, len(list)+len(items))
+	present := make(map[string]bool, len(list))
+	for _, item := range list {
+		if enabled, ok := items[item]; ok && !enabled {
+			continue
+		}
+		present[item] = true
+		result = append(result, item)
+	}
+	enabled := make([]string, 0, len(items))
+	for item, ok := range items {
+		if ok && !present[item] {
+			enabled = append(enabled, item)
+		}
+	}
+	sort.Strings(enabled)
+	return append(result, enabled...)
+}
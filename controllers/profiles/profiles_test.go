@@ -0,0 +1,166 @@
+package profiles
+
+import (
+	"context"
+	"testing"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuiltin(t *testing.T) {
+	registry, err := Builtin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"aws", "azure", "google"} {
+		if assert.Contains(t, registry, name) {
+			p := registry[name]
+			assert.Equal(t, map[string]bool{"etcdServiceMonitor": false, "coreDnsServiceMonitor": false}, p.Monitors)
+			assert.Equal(t, map[string]bool{"Etcd": false, "CoreDnsAlerts": false}, p.RuleGroups)
+			assert.False(t, p.Dashboards["etcd-dashboard"])
+			assert.False(t, p.Dashboards["core-dns-dashboard"])
+		}
+	}
+	assert.True(t, registry["aws"].Dashboards["kubernetes-pods-distribution-by-zone"])
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(`
+name: k3s
+monitors:
+  kubeSchedulerServiceMonitor: false
+defaults:
+  victoriametrics:
+    vmSingle:
+      retentionPeriod: 14d
+`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "k3s", p.Name)
+		assert.Equal(t, map[string]bool{"kubeSchedulerServiceMonitor": false}, p.Monitors)
+	}
+
+	_, err = Parse([]byte("monitors: {}"))
+	assert.EqualError(t, err, "name of the profile is empty")
+	_, err = Parse([]byte("name: k3s\nmonitor: {}"))
+	assert.ErrorContains(t, err, "unknown field")
+	_, err = Parse([]byte("name: k3s\ndefaults:\n  victoriametrics:\n    vmSingel: {}"))
+	assert.ErrorContains(t, err, "invalid defaults of profile k3s")
+}
+
+func TestApply(t *testing.T) {
+	p, err := Parse([]byte(`
+name: on-prem
+monitors:
+  etcdServiceMonitor: true
+  coreDnsServiceMonitor: false
+ruleGroups:
+  Etcd: false
+  HAmode: true
+dashboards:
+  etcd-dashboard: false
+  ha-services: true
+defaults:
+  grafanaDashboards:
+    install: true
+  victoriametrics:
+    vmSingle:
+      retentionPeriod: 30d
+      image: victoriametrics/victoria-metrics:v1.101.0
+      nodeSelector:
+        role: storage
+        disk: ssd
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr := &v1alpha1.PlatformMonitoring{Spec: v1alpha1.PlatformMonitoringSpec{
+		PublicCloudName: "on-prem",
+		KubernetesMonitors: map[string]v1alpha1.Monitor{
+			"etcdServiceMonitor":    {Interval: "60s"},
+			"kubeletServiceMonitor": {Install: ptr.To(true)},
+		},
+		PrometheusRules:   &v1alpha1.PrometheusRules{RuleGroups: []string{"SelfMonitoring", "Etcd"}},
+		GrafanaDashboards: &v1alpha1.GrafanaDashboards{List: []string{"etcd-dashboard", "home-dashboard"}},
+		Victoriametrics: &v1alpha1.Victoriametrics{
+			VmSingle: v1alpha1.VmSingle{
+				RetentionPeriod: "7d",
+				NodeSelector:    map[string]string{"disk": "nvme"},
+			},
+			VmCluster: v1alpha1.VmCluster{Install: ptr.To(true)},
+		},
+	}}
+	if err = p.Apply(cr); err != nil {
+		t.Fatal(err)
+	}
+
+	// Monitors are enabled or disabled regardless of the custom resource, other parameters are kept
+	assert.Equal(t, v1alpha1.Monitor{Install: ptr.To(true), Interval: "60s"}, cr.Spec.KubernetesMonitors["etcdServiceMonitor"])
+	assert.Equal(t, v1alpha1.Monitor{Install: ptr.To(false)}, cr.Spec.KubernetesMonitors["coreDnsServiceMonitor"])
+	assert.True(t, cr.Spec.KubernetesMonitors["kubeletServiceMonitor"].IsInstall())
+	assert.Equal(t, []string{"SelfMonitoring", "HAmode"}, cr.Spec.PrometheusRules.RuleGroups)
+	assert.Equal(t, []string{"home-dashboard", "ha-services"}, cr.Spec.GrafanaDashboards.List)
+
+	// Parameters of the custom resource have priority over defaults
+	assert.True(t, cr.Spec.GrafanaDashboards.IsInstall())
+	assert.Equal(t, "7d", cr.Spec.Victoriametrics.VmSingle.RetentionPeriod)
+	assert.Equal(t, "victoriametrics/victoria-metrics:v1.101.0", cr.Spec.Victoriametrics.VmSingle.Image)
+	assert.Equal(t, map[string]string{"role": "storage", "disk": "nvme"}, cr.Spec.Victoriametrics.VmSingle.NodeSelector)
+	// Parameters which are not serialized are kept
+	assert.Equal(t, ptr.To(true), cr.Spec.Victoriametrics.VmCluster.Install)
+
+	// Groups of rules are not added if prometheusRules is not set, grafanaDashboards is set by defaults
+	cr = &v1alpha1.PlatformMonitoring{}
+	assert.NoError(t, p.Apply(cr))
+	assert.Nil(t, cr.Spec.PrometheusRules)
+	assert.Equal(t, []string{"ha-services"}, cr.Spec.GrafanaDashboards.List)
+	assert.Len(t, cr.Spec.KubernetesMonitors, 2)
+}
+
+func TestLoad(t *testing.T) {
+	configMap := func(name string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring", Labels: map[string]string{Label: "true"}},
+			Data:       data,
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		configMap("platform-profiles", map[string]string{
+			"aws.yaml":  "name: aws\nruleGroups:\n  Etcd: false\n",
+			"k3s.yml":   "name: k3s\nmonitors:\n  kubeSchedulerServiceMonitor: false\n",
+			"README.md": "Profiles of our platforms",
+		}),
+		configMap("team-profiles", map[string]string{
+			"k3s.yaml":    "name: k3s\n",
+			"broken.yaml": "name: broken\nmonitors: []\n",
+		}),
+		// ConfigMaps without the label and in other namespaces are ignored
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "monitoring"}, Data: map[string]string{"rke2.yaml": "name: rke2"}},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-profiles", Namespace: "team-a", Labels: map[string]string{Label: "true"}},
+			Data:       map[string]string{"rke2.yaml": "name: rke2"},
+		},
+	).Build()
+
+	registry, err := Load(context.Background(), c, "monitoring")
+	assert.ErrorContains(t, err, "ConfigMap team-profiles, key broken.yaml: ")
+	assert.ErrorContains(t, err, "ConfigMap team-profiles, key k3s.yaml: profile k3s is already defined in ConfigMap platform-profiles, key k3s.yml")
+	assert.ElementsMatch(t, []string{"aws", "azure", "google", "k3s"}, keys(registry))
+	// Profiles from ConfigMaps replace built-in profiles
+	assert.Equal(t, map[string]bool{"Etcd": false}, registry["aws"].RuleGroups)
+	assert.Nil(t, registry["aws"].Monitors)
+	assert.Equal(t, map[string]bool{"kubeSchedulerServiceMonitor": false}, registry["k3s"].Monitors)
+}
+
+func keys(registry Registry) []string {
+	result := make([]string, 0, len(registry))
+	for name := range registry {
+		result = append(result, name)
+	}
+	return result
+}
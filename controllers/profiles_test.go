@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/profiles"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestApplyProfile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := qubershiporgv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	profileConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform-profiles", Namespace: "monitoring", Labels: map[string]string{profiles.Label: ""}},
+		Data: map[string]string{"k3s.yaml": `
+name: k3s
+monitors:
+  kubeSchedulerServiceMonitor: false
+ruleGroups:
+  Etcd: false
+`},
+	}
+	instance := func(name, profile string) *qubershiporgv1.PlatformMonitoring {
+		return &qubershiporgv1.PlatformMonitoring{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring"},
+			Spec: qubershiporgv1.PlatformMonitoringSpec{
+				PublicCloudName: profile,
+				PrometheusRules: &qubershiporgv1.PrometheusRules{RuleGroups: []string{"SelfMonitoring", "Etcd"}},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(profileConfigMap, instance("platformmonitoring", "k3s"), instance("without-profile", "")).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &PlatformMonitoringReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test"), Recorder: recorder, owned: newOwnedObjects()}
+	ctx := context.Background()
+
+	t.Run("Test profile from ConfigMap", func(t *testing.T) {
+		cr := instance("platformmonitoring", "k3s")
+		r.applyProfile(ctx, cr)
+		assert.Equal(t, []string{"SelfMonitoring"}, cr.Spec.PrometheusRules.RuleGroups)
+		assert.False(t, cr.Spec.KubernetesMonitors[utils.KubeSchedulerServiceMonitorName].IsInstall())
+		assert.Empty(t, recorder.Events)
+	})
+	t.Run("Test built-in profile", func(t *testing.T) {
+		cr := instance("platformmonitoring", "aws")
+		r.applyProfile(ctx, cr)
+		assert.Equal(t, []string{"SelfMonitoring"}, cr.Spec.PrometheusRules.RuleGroups)
+		assert.False(t, cr.Spec.KubernetesMonitors[utils.EtcdServiceMonitorName].IsInstall())
+	})
+	t.Run("Test unknown profile", func(t *testing.T) {
+		cr := instance("platformmonitoring", "rke2")
+		r.applyProfile(ctx, cr)
+		assert.Equal(t, []string{"SelfMonitoring", "Etcd"}, cr.Spec.PrometheusRules.RuleGroups)
+		if assert.Len(t, recorder.Events, 1) {
+			assert.Contains(t, <-recorder.Events, reasonUnknownPlatformProfile)
+		}
+	})
+	t.Run("Test changes of ConfigMaps with profiles", func(t *testing.T) {
+		assert.True(t, profileConfigMapPredicate().Create(event.CreateEvent{Object: profileConfigMap}))
+		assert.False(t, profileConfigMapPredicate().Create(event.CreateEvent{Object: &corev1.ConfigMap{}}))
+
+		// Only instances with profiles are reconciled, all their components are reconciled
+		requests := r.mapProfileConfigMap(ctx, profileConfigMap)
+		name := types.NamespacedName{Namespace: "monitoring", Name: "platformmonitoring"}
+		if assert.Len(t, requests, 1) {
+			assert.Equal(t, name, requests[0].NamespacedName)
+		}
+		assert.Contains(t, r.owned.full, name)
+	})
+}
@@ -1,9 +1,12 @@
 package prometheus_operator
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -11,12 +14,13 @@ type PrometheusOperatorReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewPrometheusOperatorReconciler(c client.Client, s *runtime.Scheme) *PrometheusOperatorReconciler {
+func NewPrometheusOperatorReconciler(c client.Client, s *runtime.Scheme, rec record.EventRecorder) *PrometheusOperatorReconciler {
 	return &PrometheusOperatorReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Log:    utils.Logger("prometheusoperator_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Log:      utils.Logger("prometheusoperator_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -25,7 +29,7 @@ func NewPrometheusOperatorReconciler(c client.Client, s *runtime.Scheme) *Promet
 // Creates new deployment, service, service account, cluster role and cluster role binding if its don't exists.
 // Updates deployment and service in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *PrometheusOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *PrometheusOperatorReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if cr.Spec.Prometheus != nil && cr.Spec.Prometheus.IsInstall() {
@@ -1,61 +1,208 @@
 package prometheus_rules
 
 import (
+	"context"
+	"errors"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func (r *PrometheusRulesReconciler) handlePrometheusRules(cr *v1alpha1.PlatformMonitoring) error {
-	m, err := prometheusRules(cr)
-	if err != nil {
+func (r *PrometheusRulesReconciler) handlePrometheusRules(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	manifests, err := prometheusRules(cr)
+	var invalid *InvalidRulesError
+	if err != nil && !errors.As(err, &invalid) {
 		r.Log.Error(err, "Failed creating PrometheusRules manifest")
 		return err
 	}
 
-	// Set labels
-	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
-	if label, ok := cr.Labels["app.kubernetes.io/version"]; ok {
-		m.Labels["app.kubernetes.io/version"] = label
+	// PrometheusRules of groups with invalid expressions are not updated, so the last valid rules are kept
+	keep := make(map[string]struct{}, len(manifests))
+	if invalid != nil {
+		r.Log.Error(invalid, "PrometheusRules of groups with invalid expressions are not updated")
+		for _, rule := range invalid.Rules {
+			keep[ruleGroupID(rule.Group)] = struct{}{}
+		}
+	}
+	var errs []error
+	for _, m := range manifests {
+		// Set labels
+		m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
+		if label, ok := cr.Labels["app.kubernetes.io/version"]; ok {
+			m.Labels["app.kubernetes.io/version"] = label
+		}
+		keep[m.Labels[RuleGroupLabel]] = struct{}{}
+		if err = r.ApplyResource(cr, m); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	e := &promv1.PrometheusRule{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
+	// Remove PrometheusRules of groups which are no longer chosen
+	if err = r.deleteStalePrometheusRules(ctx, cr, keep); err != nil {
+		errs = append(errs, err)
+	}
+	// VMRules of groups are not needed if vmalert is no longer the evaluator
+	if err = r.deleteVMRules(ctx, cr, nil); err != nil {
+		errs = append(errs, err)
+	}
+	if invalid != nil {
+		// The legacy PrometheusRule can contain the last valid rules of invalid groups
+		errs = append(errs, invalid)
+	} else if err = r.deleteLegacyPrometheusRule(cr); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// handleVMRules creates a VMRule for each chosen group of rules if vmalert is the evaluator.
+// PrometheusRules of groups and VMRules converted from them by the VictoriaMetrics operator are deleted,
+// so vmalert doesn't evaluate rules twice.
+func (r *PrometheusRulesReconciler) handleVMRules(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	manifests, err := vmRules(cr)
+	var invalid *InvalidRulesError
+	if err != nil && !errors.As(err, &invalid) {
+		r.Log.Error(err, "Failed creating VMRules manifest")
+		return err
+	}
+
+	// Objects of groups with invalid expressions are not updated, so the last valid rules are kept
+	keep := make(map[string]struct{}, len(manifests))
+	if invalid != nil {
+		r.Log.Error(invalid, "VMRules of groups with invalid expressions are not updated")
+		for _, rule := range invalid.Rules {
+			keep[ruleGroupID(rule.Group)] = struct{}{}
 		}
+	}
+	var errs []error
+	// PrometheusRules of valid groups are replaced with VMRules
+	if err = r.deleteStalePrometheusRules(ctx, cr, keep); err != nil {
+		return err
+	}
+	// VMRules converted from PrometheusRules have the same names, so they are deleted before VMRules of groups are created
+	if err = r.deleteVMRules(ctx, cr, func(rule *vmetricsv1b1.VMRule) bool {
+		_, ok := keep[rule.Labels[RuleGroupLabel]]
+		return ok || !convertedFromPrometheusRule(rule)
+	}); err != nil {
 		return err
 	}
+	for _, m := range manifests {
+		// Set labels
+		m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
+		if label, ok := cr.Labels["app.kubernetes.io/version"]; ok {
+			m.Labels["app.kubernetes.io/version"] = label
+		}
+		keep[m.Labels[RuleGroupLabel]] = struct{}{}
+		if err = r.ApplyResource(cr, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Remove VMRules of groups which are no longer chosen
+	if err = r.deleteVMRules(ctx, cr, func(rule *vmetricsv1b1.VMRule) bool {
+		_, ok := keep[rule.Labels[RuleGroupLabel]]
+		return ok
+	}); err != nil {
+		errs = append(errs, err)
+	}
+	if invalid != nil {
+		// The legacy PrometheusRule can contain the last valid rules of invalid groups
+		errs = append(errs, invalid)
+	} else if err = r.deleteLegacyPrometheusRule(cr); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
 
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
+// deleteStalePrometheusRules removes PrometheusRules of groups which are not kept
+func (r *PrometheusRulesReconciler) deleteStalePrometheusRules(ctx context.Context, cr *v1alpha1.PlatformMonitoring, keep map[string]struct{}) error {
+	list := &promv1.PrometheusRuleList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()), client.HasLabels{RuleGroupLabel}); err != nil {
+		return err
+	}
+	var errs []error
+	for _, rule := range list.Items {
+		if _, ok := keep[rule.Labels[RuleGroupLabel]]; ok {
+			continue
+		}
+		r.Log.Info("Delete PrometheusRule of the group", "name", rule.GetName())
+		if err := r.DeleteResource(rule); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
 
-	if err = r.UpdateResource(e); err != nil {
+// deleteVMRules removes VMRules of groups except ones for which keep returns true.
+// VMRules converted from PrometheusRules by the VictoriaMetrics operator are kept if keep is nil,
+// they are removed together with PrometheusRules. Nothing is done if the VMRule API is not installed.
+func (r *PrometheusRulesReconciler) deleteVMRules(ctx context.Context, cr *v1alpha1.PlatformMonitoring, keep func(rule *vmetricsv1b1.VMRule) bool) error {
+	if keep == nil {
+		keep = convertedFromPrometheusRule
+	}
+	list := &vmetricsv1b1.VMRuleList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()), client.HasLabels{RuleGroupLabel}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
 		return err
 	}
-	return nil
+	var errs []error
+	for _, rule := range list.Items {
+		if keep(rule) {
+			continue
+		}
+		r.Log.Info("Delete VMRule of the group", "name", rule.GetName())
+		if err := r.DeleteResource(rule); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// convertedFromPrometheusRule returns true if the VMRule is created by the VictoriaMetrics operator
+// from the PrometheusRule
+func convertedFromPrometheusRule(rule *vmetricsv1b1.VMRule) bool {
+	for _, ref := range rule.GetOwnerReferences() {
+		if ref.Kind == "PrometheusRule" {
+			return true
+		}
+	}
+	return false
 }
 
-func (r *PrometheusRulesReconciler) deletePrometheusRules(cr *v1alpha1.PlatformMonitoring) error {
-	m, err := prometheusRules(cr)
+// deleteLegacyPrometheusRule removes the PrometheusRule with all groups created by previous versions of the operator
+func (r *PrometheusRulesReconciler) deleteLegacyPrometheusRule(cr *v1alpha1.PlatformMonitoring) error {
+	// Only metadata of the manifest is used, so overrides which can be invalid are not applied
+	m, err := prometheusRuleGroups(&v1alpha1.PlatformMonitoring{ObjectMeta: cr.ObjectMeta})
 	if err != nil {
-		r.Log.Error(err, "Failed creating PrometheusRules manifest")
 		return err
 	}
-	e := &promv1.PrometheusRule{ObjectMeta: m.ObjectMeta}
+	e := &promv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: m.GetName(), Namespace: m.GetNamespace()}}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			return nil
 		}
 		return err
 	}
-	if err = r.DeleteResource(e); err != nil {
-		return err
+	return r.DeleteResource(e)
+}
+
+func (r *PrometheusRulesReconciler) deletePrometheusRules(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	var errs []error
+	if err := r.deleteStalePrometheusRules(ctx, cr, nil); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.deleteVMRules(ctx, cr, func(*vmetricsv1b1.VMRule) bool { return false }); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.deleteLegacyPrometheusRule(cr); err != nil {
+		errs = append(errs, err)
 	}
-	return nil
+	return errors.Join(errs...)
 }
@@ -2,58 +2,100 @@ package prometheus_rules
 
 import (
 	"embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
+// RuleGroupLabel contains the name of the group of rules in the PrometheusRule
+const RuleGroupLabel = "monitoring.qubership.org/rule-group"
+
 //go:embed  assets/*.yaml
 var assets embed.FS
 
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
 func prepareOverrideConfigMap(cr *v1alpha1.PlatformMonitoring) map[string]map[string]*v1alpha1.PrometheusRule {
 	// Init map with info about chosen groups and overridden rules from CR
+	// Groups of the platform profile are already added to or removed from the list of groups
 	overrideConfigMap := make(map[string]map[string]*v1alpha1.PrometheusRule)
-	restrictedGroups := make(map[string]bool)
-	if cr.Spec.PublicCloudName != "" {
-		if pcMap, ok := utils.PublicCloudRulesEnabled[cr.Spec.PublicCloudName]; ok {
-			for group, included := range pcMap {
-				if included {
-					cr.Spec.PrometheusRules.RuleGroups = append(cr.Spec.PrometheusRules.RuleGroups, group)
-				} else {
-					restrictedGroups[group] = true
-				}
-			}
-		}
-	}
 	for _, group := range cr.Spec.PrometheusRules.RuleGroups {
-		// Groups that should not be in the public cloud will be skipped
-		if restrictedGroups[group] {
-			continue
-		}
 		if overrideConfigMap[group] == nil {
 			overrideConfigMap[group] = make(map[string]*v1alpha1.PrometheusRule)
 		}
-		for i := range cr.Spec.PrometheusRules.Override {
-			rule := cr.Spec.PrometheusRules.Override[i]
-			if rule.Group == group {
-				if rule.Alert != "" {
-					overrideConfigMap[rule.Group][rule.Alert] = &rule
-				} else if rule.Record != "" {
-					overrideConfigMap[rule.Group][rule.Record] = &rule
-				} else {
-					continue
-				}
+		for name, rule := range groupOverrides(cr, group) {
+			overrideConfigMap[group][name] = rule
+		}
+	}
+	return overrideConfigMap
+}
+
+// groupOverrides returns overrides of rules of the group by names of alerts and recording rules
+func groupOverrides(cr *v1alpha1.PlatformMonitoring, group string) map[string]*v1alpha1.PrometheusRule {
+	overrides := make(map[string]*v1alpha1.PrometheusRule)
+	for i := range cr.Spec.PrometheusRules.Override {
+		rule := cr.Spec.PrometheusRules.Override[i]
+		if rule.Group == group {
+			if rule.Alert != "" {
+				overrides[rule.Alert] = &rule
+			} else if rule.Record != "" {
+				overrides[rule.Record] = &rule
+			} else {
+				continue
 			}
+		}
 
+	}
+	return overrides
+}
+
+// overrideRuleGroup sets parameters of the group and appends custom rules from the custom resource,
+// then applies overrides to rules of the group. Disabled rules are removed from the group.
+func overrideRuleGroup(group *promv1.RuleGroup, customGroup *v1alpha1.PrometheusRuleGroup, overrides map[string]*v1alpha1.PrometheusRule) {
+	if customGroup != nil {
+		if customGroup.Interval != nil {
+			group.Interval = customGroup.Interval
+		}
+		if customGroup.Limit != nil {
+			group.Limit = customGroup.Limit
+		}
+		for _, rule := range customGroup.Rules {
+			group.Rules = append(group.Rules, *rule.DeepCopy())
 		}
 	}
-	return overrideConfigMap
+	if len(overrides) == 0 {
+		return
+	}
+	// Search possible override config and apply it for each rule in manifest
+	result := group.Rules[:0]
+	for j := range group.Rules {
+		rule := &group.Rules[j]
+		name := rule.Alert
+		if name == "" {
+			name = rule.Record
+		}
+		if override, ok := overrides[name]; ok && name != "" {
+			if override.Disabled {
+				continue
+			}
+			override.OverridePrometheusRule(rule)
+		}
+		result = append(result, *rule)
+	}
+	group.Rules = result
 }
 
-func prometheusRules(cr *v1alpha1.PlatformMonitoring) (*promv1.PrometheusRule, error) {
+// prometheusRuleGroups returns the PrometheusRule with all chosen groups of rules with applied overrides
+// and custom groups. Expressions of rules are not validated.
+func prometheusRuleGroups(cr *v1alpha1.PlatformMonitoring) (*promv1.PrometheusRule, error) {
 	rules := promv1.PrometheusRule{}
 
 	if err := yaml.NewYAMLOrJSONDecoder(utils.MustAssetReader(assets, utils.PrometheusRulesAsset), 100).Decode(&rules); err != nil {
@@ -66,6 +108,10 @@ func prometheusRules(cr *v1alpha1.PlatformMonitoring) (*promv1.PrometheusRule, e
 	if cr.Spec.PrometheusRules != nil {
 		// Init map with info about chosen groups and overridden rules from CR
 		overrideConfigMap := prepareOverrideConfigMap(cr)
+		customGroups := make(map[string]*v1alpha1.PrometheusRuleGroup, len(cr.Spec.PrometheusRules.Groups))
+		for i := range cr.Spec.PrometheusRules.Groups {
+			customGroups[cr.Spec.PrometheusRules.Groups[i].Name] = &cr.Spec.PrometheusRules.Groups[i]
+		}
 
 		// Result spec contains only chosen groups with override alerts
 		resultSpec := promv1.PrometheusRuleSpec{}
@@ -73,32 +119,27 @@ func prometheusRules(cr *v1alpha1.PlatformMonitoring) (*promv1.PrometheusRule, e
 		// Iterate over Rules Groups from asset. Get those chosen in CR and apply overridden configs if needed
 		for i := range rules.Spec.Groups {
 			group := &rules.Spec.Groups[i]
+			customGroup := customGroups[group.Name]
+			delete(customGroups, group.Name)
 			if overrideRulesByGroup, isGroupIncluded := overrideConfigMap[group.Name]; isGroupIncluded {
 
 				// Do not include AlertManager group in case of AlertManager is not installed
 				if group.Name == utils.AlertManagerGroupName && (cr.Spec.AlertManager == nil || !cr.Spec.AlertManager.IsInstall()) {
 					continue
 				}
-				if overrideRulesByGroup != nil {
-					// Search possible override config and apply it for each rule in manifest
-					for j := range group.Rules {
-						rule := &group.Rules[j]
-						if rule.Alert != "" {
-							if _, ok := overrideRulesByGroup[rule.Alert]; ok {
-								overrideRulesByGroup[rule.Alert].OverridePrometheusRule(rule)
-							}
-						} else if rule.Record != "" {
-							if _, ok := overrideRulesByGroup[rule.Record]; ok {
-								overrideRulesByGroup[rule.Record].OverridePrometheusRule(rule)
-							}
-						} else {
-							continue
-						}
-					}
-				}
+				overrideRuleGroup(group, customGroup, overrideRulesByGroup)
 				resultSpec.Groups = append(resultSpec.Groups, *group)
 			}
 		}
+		// Groups which are not in the asset are added in the order of the custom resource
+		for _, customGroup := range cr.Spec.PrometheusRules.Groups {
+			if _, ok := customGroups[customGroup.Name]; !ok {
+				continue
+			}
+			group := promv1.RuleGroup{Name: customGroup.Name}
+			overrideRuleGroup(&group, &customGroup, groupOverrides(cr, customGroup.Name))
+			resultSpec.Groups = append(resultSpec.Groups, group)
+		}
 		rules.Spec = resultSpec
 	}
 
@@ -122,3 +163,137 @@ func prometheusRules(cr *v1alpha1.PlatformMonitoring) (*promv1.PrometheusRule, e
 
 	return &rules, nil
 }
+
+// ruleGroupID returns the stable identifier of the group of rules which is used in the name
+// and in the RuleGroupLabel of its PrometheusRule
+func ruleGroupID(group string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(group), "-"), "-")
+}
+
+// prometheusRules returns a PrometheusRule for each chosen group of rules. Each PrometheusRule has
+// the RuleGroupLabel, so rule selectors can choose groups individually.
+// Groups with invalid expressions of PromQL are not returned, they are reported with InvalidRulesError
+// together with PrometheusRules of valid groups.
+func prometheusRules(cr *v1alpha1.PlatformMonitoring) ([]*promv1.PrometheusRule, error) {
+	return ruleGroupManifests(cr, parsePromQL)
+}
+
+// ruleGroupManifests returns a PrometheusRule for each chosen group of rules which expressions are parsed
+// by the parser of the evaluator of rules
+func ruleGroupManifests(cr *v1alpha1.PlatformMonitoring, parse exprParser) ([]*promv1.PrometheusRule, error) {
+	all, err := prometheusRuleGroups(cr)
+	if err != nil {
+		return nil, err
+	}
+	var result []*promv1.PrometheusRule
+	var invalid []RuleError
+	groups := make(map[string]string, len(all.Spec.Groups))
+	for _, group := range all.Spec.Groups {
+		id := ruleGroupID(group.Name)
+		if other, ok := groups[id]; ok {
+			return nil, fmt.Errorf("groups %s and %s have the same name of PrometheusRule", other, group.Name)
+		}
+		groups[id] = group.Name
+		if err = validateRules([]promv1.RuleGroup{group}, parse); err != nil {
+			invalid = append(invalid, err.(*InvalidRulesError).Rules...)
+			continue
+		}
+		m := &promv1.PrometheusRule{
+			TypeMeta:   all.TypeMeta,
+			ObjectMeta: *all.ObjectMeta.DeepCopy(),
+			Spec:       promv1.PrometheusRuleSpec{Groups: []promv1.RuleGroup{group}},
+		}
+		m.SetName(all.GetName() + "-" + id)
+		if m.Labels == nil {
+			m.Labels = map[string]string{}
+		}
+		m.Labels[RuleGroupLabel] = id
+		result = append(result, m)
+	}
+	if len(invalid) > 0 {
+		return result, &InvalidRulesError{Rules: invalid}
+	}
+	return result, nil
+}
+
+// evaluatedByVmAlert returns true if rules are evaluated by vmalert of the VictoriaMetrics stack
+// which replaces Prometheus
+func evaluatedByVmAlert(cr *v1alpha1.PlatformMonitoring) bool {
+	return cr.Spec.Victoriametrics != nil &&
+		cr.Spec.Victoriametrics.VmOperator.IsInstall() &&
+		cr.Spec.Victoriametrics.VmAlert.IsInstall()
+}
+
+// vmRules returns a VMRule for each chosen group of rules. VMRules have the same names and labels
+// as PrometheusRules of groups, and use parameters of vmalert from the custom resource:
+// debug of rules and concurrency of groups.
+// Expressions are parsed as MetricsQL. Groups with invalid expressions are not returned,
+// they are reported with InvalidRulesError together with VMRules of valid groups.
+func vmRules(cr *v1alpha1.PlatformMonitoring) ([]*vmetricsv1b1.VMRule, error) {
+	manifests, err := ruleGroupManifests(cr, parseMetricsQL)
+	var invalid *InvalidRulesError
+	if err != nil && !errors.As(err, &invalid) {
+		return nil, err
+	}
+	customGroups := map[string]*v1alpha1.PrometheusRuleGroup{}
+	if cr.Spec.PrometheusRules != nil {
+		for i := range cr.Spec.PrometheusRules.Groups {
+			customGroups[cr.Spec.PrometheusRules.Groups[i].Name] = &cr.Spec.PrometheusRules.Groups[i]
+		}
+	}
+	result := make([]*vmetricsv1b1.VMRule, 0, len(manifests))
+	for _, m := range manifests {
+		vmRule := &vmetricsv1b1.VMRule{
+			ObjectMeta: *m.ObjectMeta.DeepCopy(),
+		}
+		vmRule.SetGroupVersionKind(vmetricsv1b1.GroupVersion.WithKind("VMRule"))
+		for _, group := range m.Spec.Groups {
+			var overrides map[string]*v1alpha1.PrometheusRule
+			if cr.Spec.PrometheusRules != nil {
+				overrides = groupOverrides(cr, group.Name)
+			}
+			vmRule.Spec.Groups = append(vmRule.Spec.Groups, vmRuleGroup(group, customGroups[group.Name], overrides))
+		}
+		result = append(result, vmRule)
+	}
+	return result, err
+}
+
+// vmRuleGroup converts the group of rules with applied overrides to the group of vmalert
+func vmRuleGroup(group promv1.RuleGroup, customGroup *v1alpha1.PrometheusRuleGroup, overrides map[string]*v1alpha1.PrometheusRule) vmetricsv1b1.RuleGroup {
+	result := vmetricsv1b1.RuleGroup{Name: group.Name}
+	if group.Interval != nil {
+		result.Interval = string(*group.Interval)
+	}
+	if group.Limit != nil {
+		result.Limit = *group.Limit
+	}
+	if customGroup != nil {
+		result.Concurrency = customGroup.Concurrency
+	}
+	result.Rules = make([]vmetricsv1b1.Rule, 0, len(group.Rules))
+	for _, rule := range group.Rules {
+		vmRule := vmetricsv1b1.Rule{
+			Alert:       rule.Alert,
+			Record:      rule.Record,
+			Expr:        rule.Expr.String(),
+			Labels:      rule.Labels,
+			Annotations: rule.Annotations,
+		}
+		if rule.For != nil {
+			vmRule.For = string(*rule.For)
+		}
+		if rule.KeepFiringFor != nil {
+			vmRule.KeepFiringFor = string(*rule.KeepFiringFor)
+		}
+		name := rule.Alert
+		if name == "" {
+			name = rule.Record
+		}
+		if override, ok := overrides[name]; ok && name != "" {
+			vmRule.Debug = override.Debug
+		}
+		result.Rules = append(result.Rules, vmRule)
+	}
+	return result
+}
@@ -1,11 +1,20 @@
 package prometheus_rules
 
 import (
+	"context"
 	"testing"
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var (
@@ -25,15 +34,17 @@ func TestPrometheusRuleManifests(t *testing.T) {
 		},
 	}
 	t.Run("Test PrometheusRule manifest", func(t *testing.T) {
-		m, err := prometheusRules(cr)
+		manifests, err := prometheusRules(cr)
 		if err != nil {
 			t.Fatal(err)
 		}
-		assert.NotNil(t, m, "PrometheusRule manifest should not be empty")
-		assert.NotNil(t, m.GetLabels())
-		assert.Equal(t, labelValue, m.GetLabels()[labelKey])
-		assert.NotNil(t, m.GetAnnotations())
-		assert.Equal(t, annotationValue, m.GetAnnotations()[annotationKey])
+		assert.NotEmpty(t, manifests, "PrometheusRule manifests should not be empty")
+		for _, m := range manifests {
+			assert.NotNil(t, m.GetLabels())
+			assert.Equal(t, labelValue, m.GetLabels()[labelKey])
+			assert.NotNil(t, m.GetAnnotations())
+			assert.Equal(t, annotationValue, m.GetAnnotations()[annotationKey])
+		}
 	})
 	cr = &v1alpha1.PlatformMonitoring{
 		ObjectMeta: metav1.ObjectMeta{
@@ -41,12 +52,360 @@ func TestPrometheusRuleManifests(t *testing.T) {
 		},
 	}
 	t.Run("Test PrometheusRule manifest with nil labels and annotation", func(t *testing.T) {
-		m, err := prometheusRules(cr)
+		manifests, err := prometheusRules(cr)
 		if err != nil {
 			t.Fatal(err)
 		}
-		assert.NotNil(t, m, "PrometheusRule manifest should not be empty")
-		assert.NotNil(t, m.GetLabels())
-		assert.Nil(t, m.GetAnnotations())
+		assert.NotEmpty(t, manifests, "PrometheusRule manifests should not be empty")
+		for _, m := range manifests {
+			assert.NotNil(t, m.GetLabels())
+			assert.Nil(t, m.GetAnnotations())
+		}
 	})
+	t.Run("Test PrometheusRule per group", func(t *testing.T) {
+		cr := &v1alpha1.PlatformMonitoring{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+			Spec: v1alpha1.PlatformMonitoringSpec{PrometheusRules: &v1alpha1.PrometheusRules{
+				RuleGroups: []string{"SelfMonitoring", "Etcd"},
+				Groups: []v1alpha1.PrometheusRuleGroup{{
+					Name:  "Team A/Custom",
+					Rules: []promv1.Rule{{Alert: "TeamAlert", Expr: intstr.FromString("vector(1)")}},
+				}},
+			}},
+		}
+		manifests, err := prometheusRules(cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names, groups []string
+		for _, m := range manifests {
+			names = append(names, m.GetName())
+			groups = append(groups, m.Labels[RuleGroupLabel])
+			assert.Equal(t, "monitoring", m.GetNamespace())
+			assert.Equal(t, "prometheus-rules", m.Labels["app.kubernetes.io/name"])
+			assert.Len(t, m.Spec.Groups, 1)
+		}
+		assert.Equal(t, []string{"prometheus-rules-selfmonitoring", "prometheus-rules-etcd", "prometheus-rules-team-a-custom"}, names)
+		assert.Equal(t, []string{"selfmonitoring", "etcd", "team-a-custom"}, groups)
+
+		// Names of PrometheusRules must be unique
+		cr.Spec.PrometheusRules.Groups = append(cr.Spec.PrometheusRules.Groups, v1alpha1.PrometheusRuleGroup{
+			Name:  "team-a-custom",
+			Rules: []promv1.Rule{{Alert: "TeamAlert", Expr: intstr.FromString("vector(1)")}},
+		})
+		_, err = prometheusRules(cr)
+		assert.Error(t, err)
+	})
+}
+
+func TestPrometheusRulesOverride(t *testing.T) {
+	interval := promv1.Duration("1m")
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{PrometheusRules: &v1alpha1.PrometheusRules{
+			RuleGroups: []string{"SelfMonitoring", "Etcd"},
+			Override: []v1alpha1.PrometheusRule{
+				// Previous format of overrides
+				{Group: "SelfMonitoring", Alert: "PrometheusTargetMissing", For: "10m", Expr: "up{job!=\"test\"} == 0", Severity: "warning"},
+				{Group: "SelfMonitoring", Alert: "PrometheusJobMissing", Disabled: true},
+				{
+					Group:         "SelfMonitoring",
+					Alert:         "PrometheusAllTargetsMissing",
+					KeepFiringFor: "15m",
+					Labels:        map[string]string{"team": "platform"},
+					Annotations:   map[string]string{"runbook_url": "https://runbooks/all-targets-missing"},
+				},
+				{Group: "Platform", Alert: "TeamAlert", Severity: "critical"},
+			},
+			Groups: []v1alpha1.PrometheusRuleGroup{
+				{
+					Name:     "SelfMonitoring",
+					Interval: &interval,
+					Rules:    []promv1.Rule{{Alert: "PrometheusCustomAlert", Expr: intstr.FromString("vector(1)")}},
+				},
+				// Groups which are not chosen are not added
+				{Name: "NodeExporters", Rules: []promv1.Rule{{Alert: "Ignored", Expr: intstr.FromString("vector(1)")}}},
+				{Name: "Platform", Rules: []promv1.Rule{{Alert: "TeamAlert", Expr: intstr.FromString("vector(1)")}}},
+			},
+		}},
+	}
+	m, err := prometheusRuleGroups(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := map[string]promv1.RuleGroup{}
+	var names []string
+	for _, group := range m.Spec.Groups {
+		groups[group.Name] = group
+		names = append(names, group.Name)
+	}
+	assert.Equal(t, []string{"SelfMonitoring", "Etcd", "Platform"}, names)
+
+	rules := map[string]promv1.Rule{}
+	for _, rule := range groups["SelfMonitoring"].Rules {
+		rules[rule.Alert] = rule
+	}
+	assert.Equal(t, &interval, groups["SelfMonitoring"].Interval)
+	assert.NotContains(t, rules, "PrometheusJobMissing")
+	assert.Equal(t, promv1.Duration("10m"), *rules["PrometheusTargetMissing"].For)
+	expr := rules["PrometheusTargetMissing"].Expr
+	assert.Equal(t, "up{job!=\"test\"} == 0", expr.String())
+	assert.Equal(t, "warning", rules["PrometheusTargetMissing"].Labels["severity"])
+	allTargets := rules["PrometheusAllTargetsMissing"]
+	assert.Equal(t, promv1.NonEmptyDuration("15m"), *allTargets.KeepFiringFor)
+	assert.Equal(t, map[string]string{"severity": "critical", "team": "platform"}, allTargets.Labels)
+	assert.Equal(t, "https://runbooks/all-targets-missing", allTargets.Annotations["runbook_url"])
+	assert.NotEmpty(t, allTargets.Annotations["summary"])
+	assert.Contains(t, rules, "PrometheusCustomAlert")
+
+	// Overrides are applied to custom groups too
+	if assert.Len(t, groups["Platform"].Rules, 1) {
+		assert.Equal(t, map[string]string{"severity": "critical"}, groups["Platform"].Rules[0].Labels)
+	}
+	// The custom resource is not changed
+	assert.Nil(t, cr.Spec.PrometheusRules.Groups[2].Rules[0].Labels)
+}
+
+func TestParseExpressions(t *testing.T) {
+	for _, expr := range []string{
+		`up == 0`,
+		`sum by (job) (rate(http_requests_total[5m])) > 10`,
+		`vector(1)`,
+		`time()`,
+	} {
+		assert.NoError(t, parsePromQL(expr), expr)
+		assert.NoError(t, parseMetricsQL(expr), expr)
+	}
+	// Expressions which are valid in MetricsQL, but can't be evaluated by Prometheus
+	for _, expr := range []string{
+		`rate(foo)`,
+		`foo[5m]`,
+		`"text"`,
+		`WITH (f = foo{job="a"}) rate(f[5m])`,
+		`rollup_candlestick(foo[5m])`,
+		`range_median(foo)`,
+	} {
+		assert.Error(t, parsePromQL(expr), expr)
+	}
+	assert.NoError(t, parseMetricsQL(`WITH (f = foo{job="a"}) rate(f[5m])`))
+	assert.NoError(t, parseMetricsQL(`range_median(foo)`))
+	assert.Error(t, parseMetricsQL(`absent(up{job="prometheus"}`))
+}
+
+func TestPrometheusRulesValidation(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{PrometheusRules: &v1alpha1.PrometheusRules{
+			RuleGroups: []string{"SelfMonitoring", "Etcd"},
+			Override: []v1alpha1.PrometheusRule{
+				{Group: "SelfMonitoring", Alert: "PrometheusJobMissing", Expr: "absent(up{job=\"prometheus\"}"},
+				{Group: "SelfMonitoring", Alert: "PrometheusTargetMissing", Expr: "up == 0"},
+			},
+			Groups: []v1alpha1.PrometheusRuleGroup{{
+				Name:  "Platform",
+				Rules: []promv1.Rule{{Record: "job:up:sum", Expr: intstr.FromString("sum by job (up)")}},
+			}},
+		}},
+	}
+	// Extensions of MetricsQL are valid only if rules are evaluated by vmalert
+	cr.Spec.PrometheusRules.Override = append(cr.Spec.PrometheusRules.Override,
+		v1alpha1.PrometheusRule{Group: "Etcd", Alert: "EtcdNoLeader", Expr: "range_median(etcd_server_has_leader) == 0"})
+	vmManifests, err := vmRules(cr)
+	var invalid *InvalidRulesError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Len(t, vmManifests, 1)
+
+	manifests, err := prometheusRules(cr)
+	assert.ErrorAs(t, err, &invalid)
+	assert.Empty(t, manifests)
+	cr.Spec.PrometheusRules.Override = cr.Spec.PrometheusRules.Override[:2]
+
+	manifests, err = prometheusRules(cr)
+	// Only groups with invalid expressions are not returned
+	if assert.Len(t, manifests, 1) {
+		assert.Equal(t, "prometheus-rules-etcd", manifests[0].GetName())
+	}
+	if assert.ErrorAs(t, err, &invalid) && assert.Len(t, invalid.Rules, 2) {
+		assert.Equal(t, "SelfMonitoring", invalid.Rules[0].Group)
+		assert.Equal(t, "PrometheusJobMissing", invalid.Rules[0].Alert)
+		assert.Equal(t, "Platform", invalid.Rules[1].Group)
+		assert.Equal(t, "job:up:sum", invalid.Rules[1].Record)
+		assert.Contains(t, err.Error(), "group SelfMonitoring, alert PrometheusJobMissing: ")
+	}
+
+	// The last valid rules of invalid groups are kept, rules of groups which are no longer chosen are removed
+	rule := func(name, group string) *promv1.PrometheusRule {
+		m := &promv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring"}}
+		if group != "" {
+			m.Labels = map[string]string{RuleGroupLabel: group}
+		}
+		return m
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, promv1.AddToScheme(scheme))
+	assert.NoError(t, vmetricsv1b1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		rule("prometheus-rules", ""),
+		rule("prometheus-rules-selfmonitoring", "selfmonitoring"),
+		rule("prometheus-rules-nodeexporters", "nodeexporters"),
+	).Build()
+	r := &PrometheusRulesReconciler{ComponentReconciler: &utils.ComponentReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}}
+	// The fake client doesn't support server-side apply, so only removal of PrometheusRules is checked
+	assert.ErrorAs(t, r.handlePrometheusRules(context.Background(), cr), &invalid)
+	list := &promv1.PrometheusRuleList{}
+	assert.NoError(t, c.List(context.Background(), list))
+	var names []string
+	for _, m := range list.Items {
+		names = append(names, m.GetName())
+	}
+	assert.ElementsMatch(t, []string{"prometheus-rules", "prometheus-rules-selfmonitoring"}, names)
+
+	// Rules are deleted regardless of invalid overrides
+	assert.NoError(t, r.deletePrometheusRules(context.Background(), cr))
+	assert.NoError(t, c.List(context.Background(), list))
+	assert.Empty(t, list.Items)
+}
+
+func TestVMRules(t *testing.T) {
+	interval := promv1.Duration("1m")
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Labels: map[string]string{labelKey: labelValue}},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			PrometheusRules: &v1alpha1.PrometheusRules{
+				RuleGroups: []string{"SelfMonitoring", "Etcd"},
+				Override: []v1alpha1.PrometheusRule{
+					{Group: "SelfMonitoring", Alert: "PrometheusTargetMissing", For: "10m", KeepFiringFor: "5m", Debug: ptr.To(true)},
+					{Group: "Platform", Record: "job:up:sum", Debug: ptr.To(false)},
+				},
+				Groups: []v1alpha1.PrometheusRuleGroup{
+					{Name: "SelfMonitoring", Interval: &interval, Limit: ptr.To(100), Concurrency: 4},
+					{Name: "Platform", Rules: []promv1.Rule{{Record: "job:up:sum", Expr: intstr.FromString("sum by (job) (up)")}}},
+				},
+			},
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmOperator: v1alpha1.VmOperator{Image: "victoriametrics/operator"},
+				VmAlert:    v1alpha1.VmAlert{Image: "victoriametrics/vmalert"},
+			},
+		},
+	}
+	assert.True(t, evaluatedByVmAlert(cr))
+
+	// Limit is a parameter of groups of Prometheus too
+	promRules, err := prometheusRules(cr)
+	if assert.NoError(t, err) && assert.Len(t, promRules, 3) {
+		assert.Equal(t, ptr.To(100), promRules[0].Spec.Groups[0].Limit)
+	}
+
+	manifests, err := vmRules(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, manifests, 3) {
+		return
+	}
+	for i, m := range manifests {
+		// VMRules are named and labeled as PrometheusRules, so rule selectors keep working
+		assert.Equal(t, "VMRule", m.Kind)
+		assert.Equal(t, promRules[i].GetName(), m.GetName())
+		assert.Equal(t, promRules[i].Labels, m.Labels)
+		assert.Equal(t, labelValue, m.Labels[labelKey])
+		assert.Len(t, m.Spec.Groups, 1)
+	}
+	selfMonitoring := manifests[0].Spec.Groups[0]
+	assert.Equal(t, "SelfMonitoring", selfMonitoring.Name)
+	assert.Equal(t, "1m", selfMonitoring.Interval)
+	assert.Equal(t, 100, selfMonitoring.Limit)
+	assert.Equal(t, 4, selfMonitoring.Concurrency)
+	rules := map[string]vmetricsv1b1.Rule{}
+	for _, rule := range selfMonitoring.Rules {
+		rules[rule.Alert] = rule
+	}
+	targetMissing := rules["PrometheusTargetMissing"]
+	assert.Equal(t, "10m", targetMissing.For)
+	assert.Equal(t, "5m", targetMissing.KeepFiringFor)
+	assert.Equal(t, ptr.To(true), targetMissing.Debug)
+	assert.Equal(t, "high", targetMissing.Labels["severity"])
+	assert.NotEmpty(t, targetMissing.Expr)
+	assert.NotEmpty(t, targetMissing.Annotations["summary"])
+	assert.Nil(t, rules["PrometheusJobMissing"].Debug)
+
+	etcd := manifests[1].Spec.Groups[0]
+	assert.Empty(t, etcd.Interval)
+	assert.Zero(t, etcd.Concurrency)
+	if platform := manifests[2].Spec.Groups[0]; assert.Len(t, platform.Rules, 1) {
+		assert.Equal(t, "sum by (job) (up)", platform.Rules[0].Expr)
+		assert.Equal(t, ptr.To(false), platform.Rules[0].Debug)
+	}
+
+	// Rules are evaluated by Prometheus if vmalert is not installed
+	cr.Spec.Victoriametrics.VmAlert.Install = ptr.To(false)
+	assert.False(t, evaluatedByVmAlert(cr))
+}
+
+func TestVMRulesHandling(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{PrometheusRules: &v1alpha1.PrometheusRules{
+			RuleGroups: []string{"SelfMonitoring", "Etcd"},
+			Override: []v1alpha1.PrometheusRule{
+				{Group: "SelfMonitoring", Alert: "PrometheusJobMissing", Expr: "absent(up{job=\"prometheus\"}"},
+			},
+		}},
+	}
+	prometheusRule := func(name, group string) *promv1.PrometheusRule {
+		return &promv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "monitoring", Labels: map[string]string{RuleGroupLabel: group},
+		}}
+	}
+	vmRule := func(name, group string, converted bool) *vmetricsv1b1.VMRule {
+		m := &vmetricsv1b1.VMRule{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "monitoring", Labels: map[string]string{RuleGroupLabel: group},
+		}}
+		if converted {
+			m.OwnerReferences = []metav1.OwnerReference{{APIVersion: "monitoring.coreos.com/v1", Kind: "PrometheusRule", Name: name}}
+		}
+		return m
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, promv1.AddToScheme(scheme))
+	assert.NoError(t, vmetricsv1b1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		prometheusRule("prometheus-rules-selfmonitoring", "selfmonitoring"),
+		prometheusRule("prometheus-rules-etcd", "etcd"),
+		vmRule("prometheus-rules-selfmonitoring", "selfmonitoring", true),
+		vmRule("prometheus-rules-etcd", "etcd", true),
+		vmRule("prometheus-rules-nodeexporters", "nodeexporters", false),
+	).Build()
+	r := &PrometheusRulesReconciler{ComponentReconciler: &utils.ComponentReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}}
+	names := func(list client.ObjectList) []string {
+		assert.NoError(t, c.List(context.Background(), list))
+		var result []string
+		switch l := list.(type) {
+		case *promv1.PrometheusRuleList:
+			for _, m := range l.Items {
+				result = append(result, m.GetName())
+			}
+		case *vmetricsv1b1.VMRuleList:
+			for _, m := range l.Items {
+				result = append(result, m.GetName())
+			}
+		}
+		return result
+	}
+
+	// The fake client doesn't support server-side apply, so only removal of objects is checked.
+	// Objects of the invalid group are kept, objects of valid groups are replaced with VMRules.
+	var invalid *InvalidRulesError
+	assert.ErrorAs(t, r.handleVMRules(context.Background(), cr), &invalid)
+	assert.Equal(t, []string{"prometheus-rules-selfmonitoring"}, names(&promv1.PrometheusRuleList{}))
+	assert.Equal(t, []string{"prometheus-rules-selfmonitoring"}, names(&vmetricsv1b1.VMRuleList{}))
+
+	// VMRules of groups are removed if rules are evaluated by Prometheus, converted VMRules are kept
+	assert.NoError(t, c.Create(context.Background(), vmRule("prometheus-rules-etcd", "etcd", false)))
+	assert.ErrorAs(t, r.handlePrometheusRules(context.Background(), cr), &invalid)
+	assert.Equal(t, []string{"prometheus-rules-selfmonitoring"}, names(&vmetricsv1b1.VMRuleList{}))
+
+	assert.NoError(t, r.deletePrometheusRules(context.Background(), cr))
+	assert.Empty(t, names(&promv1.PrometheusRuleList{}))
+	assert.Empty(t, names(&vmetricsv1b1.VMRuleList{}))
 }
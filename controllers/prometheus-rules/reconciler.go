@@ -1,9 +1,12 @@
 package prometheus_rules
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -13,28 +16,34 @@ type PrometheusRulesReconciler struct {
 }
 
 // NewPrometheusRulesReconciler  returns PrometheusRulesReconciler by specified parameters
-func NewPrometheusRulesReconciler(c client.Client, s *runtime.Scheme) *PrometheusRulesReconciler {
+func NewPrometheusRulesReconciler(c client.Client, s *runtime.Scheme, rec record.EventRecorder) *PrometheusRulesReconciler {
 	return &PrometheusRulesReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Log:    utils.Logger("prometheus_rules_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Log:      utils.Logger("prometheus_rules_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
 
 // Run reconciles k8s prometheus rules
-// Creates, updates and deletes prometheus rules depending of configuration
-func (r *PrometheusRulesReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+// Creates, updates and deletes prometheus rules depending of configuration.
+// Rules are created as VMRules if they are evaluated by vmalert and as PrometheusRules otherwise
+func (r *PrometheusRulesReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if cr.Spec.PrometheusRules != nil && cr.Spec.PrometheusRules.IsInstall() && len(cr.Spec.PrometheusRules.RuleGroups) > 0 {
-		if err := r.handlePrometheusRules(cr); err != nil {
+		if evaluatedByVmAlert(cr) {
+			if err := r.handleVMRules(ctx, cr); err != nil {
+				return err
+			}
+		} else if err := r.handlePrometheusRules(ctx, cr); err != nil {
 			return err
 		}
 	} else {
 		r.Log.Info("Uninstalling PrometheusRules")
-		if err := r.deletePrometheusRules(cr); err != nil {
+		if err := r.deletePrometheusRules(ctx, cr); err != nil {
 			r.Log.Error(err, "Can not delete PrometheusRules")
 		}
 	}
@@ -0,0 +1,512 @@
+package prometheus_rules
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/go-kit/log"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/promql/promqltest"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// Unit tests of rules in the format of `promtool test rules`. Each file in testdata/rules contains
+// tests of the group of rules with the same ID, for example testdata/rules/selfmonitoring.yaml
+// contains tests of the SelfMonitoring group, and overrides.yaml contains tests of TestOverriddenRules.
+// Rules are evaluated by the rule engine of Prometheus like promtool does. Differences from promtool:
+//   - rules are not listed in files, tests are run against groups passed to runRuleTests
+//   - annotations of alerts are compared only if exp_annotations is set
+//   - values of samples are compared with the relative tolerance sampleValueTolerance
+
+const (
+	rulesTestData        = "testdata/rules"
+	sampleValueTolerance = 1e-12
+)
+
+type ruleTestFile struct {
+	// EvaluationInterval is the default interval of groups, 1m by default
+	EvaluationInterval string          `json:"evaluation_interval,omitempty"`
+	Tests              []ruleTestGroup `json:"tests"`
+}
+
+type ruleTestGroup struct {
+	Name string `json:"name,omitempty"`
+	// Interval is the time between samples of input series, 1m by default
+	Interval        string            `json:"interval,omitempty"`
+	InputSeries     []inputSeries     `json:"input_series,omitempty"`
+	AlertRuleTests  []alertTestCase   `json:"alert_rule_test,omitempty"`
+	PromqlExprTests []promqlTestCase  `json:"promql_expr_test,omitempty"`
+	ExternalLabels  map[string]string `json:"external_labels,omitempty"`
+}
+
+type inputSeries struct {
+	Series string `json:"series"`
+	Values string `json:"values"`
+}
+
+type alertTestCase struct {
+	EvalTime  string     `json:"eval_time"`
+	Alertname string     `json:"alertname"`
+	ExpAlerts []expAlert `json:"exp_alerts,omitempty"`
+}
+
+type expAlert struct {
+	ExpLabels      map[string]string `json:"exp_labels,omitempty"`
+	ExpAnnotations map[string]string `json:"exp_annotations,omitempty"`
+}
+
+type promqlTestCase struct {
+	Expr       string      `json:"expr"`
+	EvalTime   string      `json:"eval_time"`
+	ExpSamples []expSample `json:"exp_samples,omitempty"`
+}
+
+type expSample struct {
+	Labels string  `json:"labels,omitempty"`
+	Value  float64 `json:"value"`
+}
+
+// readRuleTests reads the file with tests, unknown fields are not allowed
+func readRuleTests(file string) (*ruleTestFile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tests := &ruleTestFile{}
+	if err = yaml.UnmarshalStrict(data, tests); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return tests, nil
+}
+
+// runRuleTests runs tests of the file against groups of rules
+func runRuleTests(t *testing.T, file string, groups []promv1.RuleGroup) {
+	tests, err := readRuleTests(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluationInterval, err := parseTestDuration(tests.EvaluationInterval, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tg := range tests.Tests {
+		name := tg.Name
+		if name == "" {
+			name = fmt.Sprintf("test %d", i)
+		}
+		t.Run(name, func(t *testing.T) {
+			for _, err := range tg.run(groups, evaluationInterval) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func parseTestDuration(s string, defaultDuration time.Duration) (time.Duration, error) {
+	if s == "" {
+		return defaultDuration, nil
+	}
+	d, err := model.ParseDuration(s)
+	return time.Duration(d), err
+}
+
+// seriesLoadingString returns input series in the notation of the load command of promqltest
+func (tg *ruleTestGroup) seriesLoadingString() (string, error) {
+	interval, err := parseTestDuration(tg.Interval, time.Minute)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "load %s\n", model.Duration(interval))
+	for _, input := range tg.InputSeries {
+		fmt.Fprintf(&b, "  %s %s\n", input.Series, input.Values)
+	}
+	return b.String(), nil
+}
+
+// newRuleGroups creates groups of the rule engine of Prometheus from groups of PrometheusRules
+func newRuleGroups(groups []promv1.RuleGroup, evaluationInterval time.Duration, externalLabels labels.Labels,
+	opts *rules.ManagerOptions) ([]*rules.Group, error) {
+	var result []*rules.Group
+	for _, group := range groups {
+		interval := evaluationInterval
+		if group.Interval != nil {
+			d, err := model.ParseDuration(string(*group.Interval))
+			if err != nil {
+				return nil, fmt.Errorf("group %s: %w", group.Name, err)
+			}
+			interval = time.Duration(d)
+		}
+		var groupRules []rules.Rule
+		for _, rule := range group.Rules {
+			expr, err := parser.ParseExpr(rule.Expr.String())
+			if err != nil {
+				return nil, RuleError{Group: group.Name, Alert: rule.Alert, Record: rule.Record, Err: err}
+			}
+			if rule.Record != "" {
+				groupRules = append(groupRules, rules.NewRecordingRule(rule.Record, expr, labels.FromMap(rule.Labels)))
+				continue
+			}
+			var hold, keepFiringFor model.Duration
+			if rule.For != nil {
+				if hold, err = model.ParseDuration(string(*rule.For)); err != nil {
+					return nil, RuleError{Group: group.Name, Alert: rule.Alert, Err: err}
+				}
+			}
+			if rule.KeepFiringFor != nil {
+				if keepFiringFor, err = model.ParseDuration(string(*rule.KeepFiringFor)); err != nil {
+					return nil, RuleError{Group: group.Name, Alert: rule.Alert, Err: err}
+				}
+			}
+			// Rules are marked as restored, so the ALERTS series is created like in promtool
+			groupRules = append(groupRules, rules.NewAlertingRule(rule.Alert, expr, time.Duration(hold), time.Duration(keepFiringFor),
+				labels.FromMap(rule.Labels), labels.FromMap(rule.Annotations), externalLabels, "", true, opts.Logger))
+		}
+		result = append(result, rules.NewGroup(rules.GroupOptions{
+			Name:     group.Name,
+			Interval: interval,
+			Rules:    groupRules,
+			Opts:     opts,
+		}))
+	}
+	return result, nil
+}
+
+// run evaluates groups of rules from zero to the last evaluation time of tests and returns failures of tests
+func (tg *ruleTestGroup) run(groups []promv1.RuleGroup, evaluationInterval time.Duration) (errs []error) {
+	load, err := tg.seriesLoadingString()
+	if err != nil {
+		return []error{err}
+	}
+	suite, err := promqltest.NewLazyLoader(load, promqltest.LazyLoaderOpts{EnableAtModifier: true, EnableNegativeOffset: true})
+	if err != nil {
+		return []error{err}
+	}
+	defer func() {
+		if err := suite.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}()
+	suite.SubqueryInterval = evaluationInterval
+
+	opts := &rules.ManagerOptions{
+		QueryFunc:  rules.EngineQueryFunc(suite.QueryEngine(), suite.Storage()),
+		Appendable: suite.Storage(),
+		Context:    context.Background(),
+		NotifyFunc: func(context.Context, string, ...*rules.Alert) {},
+		Logger:     log.NewNopLogger(),
+	}
+	ruleGroups, err := newRuleGroups(groups, evaluationInterval, labels.FromMap(tg.ExternalLabels), opts)
+	if err != nil {
+		return []error{err}
+	}
+
+	type scheduledTest struct {
+		alertTestCase
+		evalTime time.Duration
+	}
+	alertTests := make([]scheduledTest, 0, len(tg.AlertRuleTests))
+	var maxEvalTime time.Duration
+	for _, test := range tg.AlertRuleTests {
+		evalTime, err := parseTestDuration(test.EvalTime, 0)
+		if err != nil {
+			return []error{err}
+		}
+		alertTests = append(alertTests, scheduledTest{alertTestCase: test, evalTime: evalTime})
+		maxEvalTime = max(maxEvalTime, evalTime)
+	}
+	sort.SliceStable(alertTests, func(i, j int) bool { return alertTests[i].evalTime < alertTests[j].evalTime })
+	for _, test := range tg.PromqlExprTests {
+		evalTime, err := parseTestDuration(test.EvalTime, 0)
+		if err != nil {
+			return []error{err}
+		}
+		maxEvalTime = max(maxEvalTime, evalTime)
+	}
+
+	mint := time.Unix(0, 0).UTC()
+	next := 0
+	for ts := time.Duration(0); ts <= maxEvalTime; ts += evaluationInterval {
+		var evalErrs []error
+		suite.WithSamplesTill(mint.Add(ts), func(err error) {
+			if err != nil {
+				evalErrs = append(evalErrs, err)
+				return
+			}
+			for _, group := range ruleGroups {
+				group.Eval(suite.Context(), mint.Add(ts))
+				for _, rule := range group.Rules() {
+					if rule.LastError() != nil {
+						evalErrs = append(evalErrs, fmt.Errorf("rule %s at %s: %w", rule.Name(), model.Duration(ts), rule.LastError()))
+					}
+				}
+			}
+		})
+		if len(evalErrs) > 0 {
+			return append(errs, evalErrs...)
+		}
+		// Alerts expected at the time between evaluations are compared with the state of the last evaluation
+		for ; next < len(alertTests) && alertTests[next].evalTime < ts+evaluationInterval; next++ {
+			if err := compareAlerts(alertTests[next].alertTestCase, alertTests[next].evalTime, ruleGroups); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, test := range tg.PromqlExprTests {
+		if err := test.run(suite, mint); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func compareAlerts(test alertTestCase, evalTime time.Duration, groups []*rules.Group) error {
+	var got []*rules.Alert
+	for _, group := range groups {
+		for _, rule := range group.Rules() {
+			ar, ok := rule.(*rules.AlertingRule)
+			if !ok || ar.Name() != test.Alertname {
+				continue
+			}
+			for _, a := range ar.ActiveAlerts() {
+				if a.State == rules.StateFiring {
+					got = append(got, a)
+				}
+			}
+		}
+	}
+	sort.Slice(got, func(i, j int) bool { return labels.Compare(got[i].Labels, got[j].Labels) < 0 })
+
+	matched := make([]bool, len(got))
+	var missing []string
+	for _, exp := range test.ExpAlerts {
+		expLabels := labels.NewBuilder(labels.FromMap(exp.ExpLabels)).Set(labels.AlertName, test.Alertname).Labels()
+		found := false
+		for i, a := range got {
+			if matched[i] || !labels.Equal(a.Labels, expLabels) {
+				continue
+			}
+			if exp.ExpAnnotations != nil && !labels.Equal(a.Annotations, labels.FromMap(exp.ExpAnnotations)) {
+				continue
+			}
+			matched[i], found = true, true
+			break
+		}
+		if !found {
+			missing = append(missing, fmt.Sprintf("%s %v", expLabels, exp.ExpAnnotations))
+		}
+	}
+	var unexpected []string
+	for i, a := range got {
+		if !matched[i] {
+			unexpected = append(unexpected, fmt.Sprintf("%s %s", a.Labels, a.Annotations))
+		}
+	}
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil
+	}
+	return fmt.Errorf("alertname: %s, time: %s,\n    missing: %s\n    unexpected: %s",
+		test.Alertname, model.Duration(evalTime), strings.Join(missing, "; "), strings.Join(unexpected, "; "))
+}
+
+func (test promqlTestCase) run(suite *promqltest.LazyLoader, mint time.Time) error {
+	evalTime, err := parseTestDuration(test.EvalTime, 0)
+	if err != nil {
+		return err
+	}
+	q, err := suite.QueryEngine().NewInstantQuery(suite.Context(), suite.Queryable(), nil, test.Expr, mint.Add(evalTime))
+	if err != nil {
+		return fmt.Errorf("expression %s at %s: %w", test.Expr, model.Duration(evalTime), err)
+	}
+	defer q.Close()
+	res := q.Exec(suite.Context())
+	if res.Err != nil {
+		return fmt.Errorf("expression %s at %s: %w", test.Expr, model.Duration(evalTime), res.Err)
+	}
+	var got promql.Vector
+	switch v := res.Value.(type) {
+	case promql.Vector:
+		got = v
+	case promql.Scalar:
+		got = promql.Vector{{T: v.T, F: v.V, Metric: labels.EmptyLabels()}}
+	default:
+		return fmt.Errorf("expression %s at %s: unexpected result type %s", test.Expr, model.Duration(evalTime), res.Value.Type())
+	}
+	gotSamples := map[string]float64{}
+	for _, s := range got {
+		gotSamples[s.Metric.String()] = s.F
+	}
+	expSamples := map[string]float64{}
+	for _, s := range test.ExpSamples {
+		lb, err := parser.ParseMetric(s.Labels)
+		if err != nil {
+			return fmt.Errorf("expression %s at %s, labels %q: %w", test.Expr, model.Duration(evalTime), s.Labels, err)
+		}
+		expSamples[lb.String()] = s.Value
+	}
+	if !samplesAlmostEqual(expSamples, gotSamples) {
+		return fmt.Errorf("expression %s at %s,\n    exp: %v\n    got: %v", test.Expr, model.Duration(evalTime), expSamples, gotSamples)
+	}
+	return nil
+}
+
+// samplesAlmostEqual compares values of samples with the relative tolerance,
+// because the result of aggregations depends on the order of summation
+func samplesAlmostEqual(exp, got map[string]float64) bool {
+	if len(exp) != len(got) {
+		return false
+	}
+	for metric, e := range exp {
+		g, ok := got[metric]
+		if !ok {
+			return false
+		}
+		if math.IsNaN(e) || math.IsNaN(g) {
+			if math.IsNaN(e) != math.IsNaN(g) {
+				return false
+			}
+			continue
+		}
+		if e != g && math.Abs(e-g) > sampleValueTolerance*math.Max(math.Abs(e), math.Abs(g)) {
+			return false
+		}
+	}
+	return true
+}
+
+// alwaysFiringAlerts are alerts which check the alerting pipeline, so they are never resolved
+var alwaysFiringAlerts = map[string]bool{"DeadMansSwitch": true}
+
+// ruleTestCoverage returns alerts of groups which are not proven to fire and to resolve by tests of the file.
+// An alert is resolved if a later test case of the same test expects no alerts.
+func ruleTestCoverage(file string, groups []promv1.RuleGroup) ([]string, error) {
+	tests, err := readRuleTests(file)
+	if err != nil {
+		return nil, err
+	}
+	covered := map[string]bool{}
+	for _, tg := range tests.Tests {
+		firedAt := map[string]time.Duration{}
+		for _, test := range tg.AlertRuleTests {
+			evalTime, err := parseTestDuration(test.EvalTime, 0)
+			if err != nil {
+				return nil, err
+			}
+			if len(test.ExpAlerts) > 0 {
+				if alwaysFiringAlerts[test.Alertname] {
+					covered[test.Alertname] = true
+				}
+				if fired, ok := firedAt[test.Alertname]; !ok || evalTime < fired {
+					firedAt[test.Alertname] = evalTime
+				}
+			}
+		}
+		for _, test := range tg.AlertRuleTests {
+			evalTime, _ := parseTestDuration(test.EvalTime, 0)
+			if fired, ok := firedAt[test.Alertname]; ok && len(test.ExpAlerts) == 0 && evalTime > fired {
+				covered[test.Alertname] = true
+			}
+		}
+	}
+	var uncovered []string
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			if rule.Alert != "" && !covered[rule.Alert] {
+				uncovered = append(uncovered, rule.Alert)
+			}
+		}
+	}
+	return uncovered, nil
+}
+
+// ruleTestFileName returns the file with tests of the group
+func ruleTestFileName(group string) string {
+	return filepath.Join(rulesTestData, ruleGroupID(group)+".yaml")
+}
+
+// TestBuiltinRules runs unit tests of each built-in group of rules. Each alert must be proven
+// to fire and to resolve.
+func TestBuiltinRules(t *testing.T) {
+	rules, err := prometheusRuleGroups(&v1alpha1.PlatformMonitoring{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, group := range rules.Spec.Groups {
+		t.Run(group.Name, func(t *testing.T) {
+			file := ruleTestFileName(group.Name)
+			uncovered, err := ruleTestCoverage(file, []promv1.RuleGroup{group})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Empty(t, uncovered, "alerts without tests which fire and resolve them in %s", file)
+			runRuleTests(t, file, []promv1.RuleGroup{group})
+		})
+	}
+}
+
+// TestOverriddenRules runs unit tests against PrometheusRules with overrides and custom groups
+func TestOverriddenRules(t *testing.T) {
+	interval := promv1.Duration("30s")
+	forDuration := promv1.Duration("2m")
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			PrometheusRules: &v1alpha1.PrometheusRules{
+				RuleGroups: []string{"SelfMonitoring"},
+				Override: []v1alpha1.PrometheusRule{
+					{
+						Group:         "SelfMonitoring",
+						Alert:         "PrometheusTargetMissing",
+						For:           "10m",
+						KeepFiringFor: "5m",
+						Severity:      "critical",
+						Labels:        map[string]string{"team": "monitoring"},
+						Annotations:   map[string]string{"summary": "Target {{ $labels.instance }} of {{ $labels.job }} is down"},
+					},
+					{Group: "SelfMonitoring", Alert: "PrometheusAllTargetsMissing", Expr: "count by (job) (up == 0) >= 2"},
+					{Group: "SelfMonitoring", Alert: "PrometheusTargetEmpty", Disabled: true},
+					{Group: "CustomRules", Alert: "JobAvailabilityLow", Labels: map[string]string{"team": "{{ $labels.job }}"}},
+				},
+				Groups: []v1alpha1.PrometheusRuleGroup{{
+					Name:     "CustomRules",
+					Interval: &interval,
+					Rules: []promv1.Rule{
+						{Record: "job:up:ratio", Expr: intstr.FromString("avg by (job) (up)")},
+						{
+							Alert:  "JobAvailabilityLow",
+							Expr:   intstr.FromString("job:up:ratio < 0.5"),
+							For:    &forDuration,
+							Labels: map[string]string{"severity": "warning"},
+						},
+					},
+				}},
+			},
+		},
+	}
+	manifests, err := prometheusRules(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var groups []promv1.RuleGroup
+	for _, m := range manifests {
+		groups = append(groups, m.Spec.Groups...)
+	}
+	runRuleTests(t, filepath.Join(rulesTestData, "overrides.yaml"), groups)
+}
@@ -0,0 +1,83 @@
+package prometheus_rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/metricsql"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// RuleError describes a rule which expression can't be parsed
+type RuleError struct {
+	// Group of the rule
+	Group string
+	// Alert is the name of the alert, empty for recording rules
+	Alert string
+	// Record is the name of the recording rule, empty for alerts
+	Record string
+	// Err is the parse error of the expression
+	Err error
+}
+
+func (e RuleError) Error() string {
+	if e.Alert != "" {
+		return fmt.Sprintf("group %s, alert %s: %v", e.Group, e.Alert, e.Err)
+	}
+	return fmt.Sprintf("group %s, record %s: %v", e.Group, e.Record, e.Err)
+}
+
+// InvalidRulesError is returned if expressions of some rules can't be parsed.
+// Such rules are not applied, so Prometheus and vmalert keep the last valid rules.
+type InvalidRulesError struct {
+	Rules []RuleError
+}
+
+func (e *InvalidRulesError) Error() string {
+	messages := make([]string, 0, len(e.Rules))
+	for _, rule := range e.Rules {
+		messages = append(messages, rule.Error())
+	}
+	return "invalid expressions of rules: " + strings.Join(messages, "; ")
+}
+
+// exprParser parses the expression of a rule in the query language of the evaluator of rules
+type exprParser func(expr string) error
+
+// parsePromQL parses the expression for Prometheus. Results of rules must be instant vectors or scalars,
+// so range vectors and strings are rejected as Prometheus rejects them during the evaluation.
+// Extensions of MetricsQL (e.g. WITH templates and its functions) are not supported by Prometheus.
+func parsePromQL(expr string) error {
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return err
+	}
+	if t := parsed.Type(); t != parser.ValueTypeVector && t != parser.ValueTypeScalar {
+		return fmt.Errorf("expression must return an instant vector or a scalar, got %s", parser.DocumentedType(t))
+	}
+	return nil
+}
+
+// parseMetricsQL parses the expression for vmalert, which supports extensions of MetricsQL
+func parseMetricsQL(expr string) error {
+	_, err := metricsql.Parse(expr)
+	return err
+}
+
+// validateRules parses expressions of all rules with the parser of the evaluator of rules.
+// Returns InvalidRulesError with all rules which expressions can't be parsed.
+func validateRules(groups []promv1.RuleGroup, parse exprParser) error {
+	var invalid []RuleError
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			if err := parse(rule.Expr.String()); err != nil {
+				invalid = append(invalid, RuleError{Group: group.Name, Alert: rule.Alert, Record: rule.Record, Err: err})
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return &InvalidRulesError{Rules: invalid}
+	}
+	return nil
+}
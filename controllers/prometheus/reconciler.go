@@ -6,11 +6,13 @@ import (
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,13 +22,14 @@ type PrometheusReconciler struct {
 }
 
 // NewPrometheusReconciler creates an instance of PrometheusReconciler
-func NewPrometheusReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *PrometheusReconciler {
+func NewPrometheusReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *PrometheusReconciler {
 	return &PrometheusReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("prometheus_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("prometheus_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -35,10 +38,17 @@ func NewPrometheusReconciler(c client.Client, s *runtime.Scheme, dc discovery.Di
 // Creates new deployment, service, service account, cluster role and cluster role binding if its don't exists.
 // Updates deployment and service in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *PrometheusReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *PrometheusReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
-	if err := r.removePrometheusPVC(cr); err != nil {
+	if (cr.Spec.Prometheus == nil || !cr.Spec.Prometheus.IsInstall()) &&
+		victoriametrics.KeepMigrationSource(cr, v1alpha1.VmMigrationSourcePrometheus) {
+		// Prometheus and its PVC are kept until its data is migrated to VictoriaMetrics
+		r.Log.Info("Component is kept as the source of the data migration")
+		return nil
+	}
+
+	if err := r.removePrometheusPVC(ctx, cr); err != nil {
 		return err
 	}
 
@@ -116,7 +126,7 @@ func (r *PrometheusReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 }
 
 // removePrometheusPVC deletes PVC for Prometheus pod if it isn't needed anymore.
-func (r *PrometheusReconciler) removePrometheusPVC(cr *v1alpha1.PlatformMonitoring) error {
+func (r *PrometheusReconciler) removePrometheusPVC(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	if cr.Spec.Prometheus == nil || !cr.Spec.Prometheus.IsInstall() || cr.Spec.Prometheus.Storage == nil {
 		// We can use hardcoded value for now because prometheus-operator < v0.40.0
 		// doesn't allow setting metadata for PVC template.
@@ -127,7 +137,7 @@ func (r *PrometheusReconciler) removePrometheusPVC(cr *v1alpha1.PlatformMonitori
 		// Find PVC resource
 		pvc := &corev1.PersistentVolumeClaim{}
 		err := r.Client.Get(
-			context.TODO(),
+			ctx,
 			types.NamespacedName{Name: pvcName, Namespace: cr.GetNamespace()},
 			pvc,
 		)
@@ -140,7 +150,7 @@ func (r *PrometheusReconciler) removePrometheusPVC(cr *v1alpha1.PlatformMonitori
 		}
 		// Delete PVC
 		r.Log.Info(fmt.Sprintf("delete Prometheus PVC with name %q as it is not needed anymore", pvc.GetName()))
-		err = r.Client.Delete(context.TODO(), pvc)
+		err = r.Client.Delete(ctx, pvc)
 		if err != nil {
 			r.Log.Error(err, fmt.Sprintf("can not delete PVC %q", pvc.GetName()))
 			return err
@@ -1,10 +1,13 @@
 package pushgateway
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -12,13 +15,14 @@ type PushgatewayReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewPushgatewayReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *PushgatewayReconciler {
+func NewPushgatewayReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *PushgatewayReconciler {
 	return &PushgatewayReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("pushgateway_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("pushgateway_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -27,7 +31,7 @@ func NewPushgatewayReconciler(c client.Client, s *runtime.Scheme, dc discovery.D
 // Creates new deployment, service and service monitor if its don't exists.
 // Updates deployment, service and service monitor in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *PushgatewayReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *PushgatewayReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if cr.Spec.Pushgateway != nil && cr.Spec.Pushgateway.IsInstall() {
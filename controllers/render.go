@@ -0,0 +1,399 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	secv1 "github.com/openshift/api/security/v1"
+	pspApi "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+// Available values of RenderOptions.IngressAPI
+const (
+	IngressAPIV1      = "v1"
+	IngressAPIV1beta1 = "v1beta1"
+	IngressAPINone    = "none"
+)
+
+// RenderOptions describes the cluster which manifests are rendered for.
+// These facts are discovered from the cluster API when the operator runs in the cluster.
+type RenderOptions struct {
+	// KubernetesVersion is the version of Kubernetes, e.g. v1.30.0
+	KubernetesVersion string
+	// OpenShift indicates that the cluster has OpenShift APIs (Route, SecurityContextConstraints)
+	OpenShift bool
+	// IngressAPI is the version of networking.k8s.io Ingress API: v1, v1beta1 or none
+	IngressAPI string
+	// PodSecurityPolicy indicates that the cluster has policy/v1beta1 PodSecurityPolicy API
+	PodSecurityPolicy bool
+}
+
+// discovery returns the discovery client which reports the cluster facts
+func (o RenderOptions) discovery() (discovery.DiscoveryInterface, error) {
+	info, err := parseKubernetesVersion(o.KubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	resources := []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "services", Kind: "Service", Namespaced: true}}},
+	}
+	switch o.IngressAPI {
+	case IngressAPIV1:
+		resources = append(resources, apiResourceList("networking.k8s.io/v1", "ingresses", "Ingress"))
+	case IngressAPIV1beta1:
+		resources = append(resources, apiResourceList("networking.k8s.io/v1beta1", "ingresses", "Ingress"))
+	case IngressAPINone, "":
+	default:
+		return nil, fmt.Errorf("unknown Ingress API %q, must be one of: %s, %s, %s", o.IngressAPI, IngressAPIV1, IngressAPIV1beta1, IngressAPINone)
+	}
+	if o.OpenShift {
+		resources = append(resources,
+			apiResourceList("route.openshift.io/v1", "routes", "Route"),
+			apiResourceList(secv1.GroupVersion.String(), "securitycontextconstraints", "SecurityContextConstraints"))
+	}
+	if o.PodSecurityPolicy {
+		resources = append(resources, apiResourceList(pspApi.SchemeGroupVersion.String(), "podsecuritypolicies", "PodSecurityPolicy"))
+	}
+	return &fakediscovery.FakeDiscovery{
+		Fake:               &clienttesting.Fake{Resources: resources},
+		FakedServerVersion: info,
+	}, nil
+}
+
+func apiResourceList(groupVersion, name, kind string) *metav1.APIResourceList {
+	return &metav1.APIResourceList{GroupVersion: groupVersion, APIResources: []metav1.APIResource{{Name: name, Kind: kind}}}
+}
+
+// parseKubernetesVersion parses the version like v1.30.0 or 1.30
+func parseKubernetesVersion(v string) (*version.Info, error) {
+	parts := strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid Kubernetes version %q, must be like v1.30.0", v)
+	}
+	return &version.Info{Major: parts[0], Minor: parts[1], GitVersion: "v" + strings.TrimPrefix(v, "v")}, nil
+}
+
+// Render writes objects which the operator creates for the custom resource instance to w as a multi-document YAML.
+// Components are reconciled one by one in the dependency order against an in-memory client, so objects
+// are written in the order of their creation. Cluster facts are taken from options instead of the cluster API.
+// Data which can be read only from the live cluster (e.g. etcd certificates, addresses of nodes) is empty.
+// Returns results of components, objects of failed components may be incomplete.
+func Render(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring, opts RenderOptions, scheme *runtime.Scheme, w io.Writer) ([]utils.ComponentResult, error) {
+	dc, err := opts.discovery()
+	if err != nil {
+		return nil, err
+	}
+	if cr.GetNamespace() == "" {
+		cr.SetNamespace(utils.DefaultWatchNamespace)
+	}
+	if cr.GetUID() == "" {
+		// Owner references require the UID of the owner
+		cr.SetUID(types.UID("00000000-0000-0000-0000-000000000000"))
+	}
+	rc := newRenderClient(fake.NewClientBuilder().WithScheme(scheme).Build())
+	r := &PlatformMonitoringReconciler{
+		Client:          rc,
+		Scheme:          scheme,
+		Log:             utils.Logger("render"),
+		Config:          &rest.Config{Host: "https://render.invalid", Transport: renderTransport{}},
+		DiscoveryClient: dc,
+	}
+	// Only built-in platform profiles are available, because ConfigMaps are not read from the cluster
+	r.applyProfile(ctx, cr)
+	cr.FillEmptyWithDefaults()
+	components, err := renderOrder(r.components(utils.NewResourceTracker(cr, nil)))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]utils.ComponentResult, 0, len(components))
+	failed := map[string]bool{}
+	for _, c := range components {
+		result := utils.ComponentResult{Name: c.Name}
+		for _, dep := range c.DependsOn {
+			if failed[dep] {
+				result.SkippedBy = dep
+				break
+			}
+		}
+		if !result.Skipped() {
+			result.Err = runRenderComponent(ctx, c, cr.DeepCopy())
+		}
+		failed[c.Name] = result.Failed()
+		results = append(results, result)
+	}
+	return results, rc.write(w)
+}
+
+// runRenderComponent runs the component and converts its panic to an error
+func runRenderComponent(ctx context.Context, c utils.Component, cr *qubershiporgv1.PlatformMonitoring) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: rendering of component %s panicked: %v", utils.ErrComponentPanic, c.Name, p)
+		}
+	}()
+	return c.Run(ctx, cr)
+}
+
+// renderOrder returns components in the dependency order.
+// Components which don't depend on each other keep the declaration order.
+func renderOrder(components []utils.Component) ([]utils.Component, error) {
+	// NewEngine validates names and dependencies of components
+	if _, err := utils.NewEngine(utils.Logger("render"), 0, components...); err != nil {
+		return nil, err
+	}
+	ordered := make([]utils.Component, 0, len(components))
+	done := make(map[string]bool, len(components))
+	for len(ordered) < len(components) {
+		for _, c := range components {
+			if done[c.Name] {
+				continue
+			}
+			ready := true
+			for _, dep := range c.DependsOn {
+				ready = ready && done[dep]
+			}
+			if ready {
+				ordered = append(ordered, c)
+				done[c.Name] = true
+			}
+		}
+	}
+	return ordered, nil
+}
+
+// renderTransport answers requests of clientsets created by components from the rest.Config.
+// The cluster is empty, so lists have no items and other requests fail with NotFound.
+type renderTransport struct{}
+
+func (renderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404,` +
+		`"message":"the cluster is not available in render mode"}`
+	code := http.StatusNotFound
+	if req.Method == http.MethodGet && isListPath(req.URL.Path) {
+		body = `{"metadata":{},"items":[]}`
+		code = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// isListPath returns true for paths of collections, e.g. /api/v1/nodes or /apis/apps/v1/namespaces/ns/deployments
+func isListPath(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) > 0 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) > 0 && parts[0] == "apis" && len(parts) >= 3:
+		parts = parts[3:]
+	default:
+		return false
+	}
+	if len(parts) >= 2 && parts[0] == "namespaces" {
+		// The namespace itself or objects in the namespace
+		parts = parts[2:]
+		if len(parts) == 0 {
+			return false
+		}
+	}
+	return len(parts) == 1
+}
+
+// renderClient records objects written by components in the order of their creation.
+// Server-side apply is not supported by the fake client, so it's replaced with create or update.
+type renderClient struct {
+	client.Client
+	mu      sync.Mutex
+	keys    []renderKey
+	objects map[renderKey]client.Object
+}
+
+type renderKey struct {
+	schema.GroupKind
+	types.NamespacedName
+}
+
+func newRenderClient(c client.Client) *renderClient {
+	return &renderClient{Client: c, objects: map[renderKey]client.Object{}}
+}
+
+// DryRun returns true, objects are not created in the cluster
+func (c *renderClient) DryRun() bool {
+	return true
+}
+
+func (c *renderClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	return c.record(obj)
+}
+
+func (c *renderClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	return c.record(obj)
+}
+
+func (c *renderClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+			return err
+		}
+		return c.record(obj)
+	}
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unsupported object %T", obj)
+	}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		obj.SetResourceVersion("")
+		return c.Create(ctx, obj)
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return c.Update(ctx, obj)
+}
+
+func (c *renderClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	key, err := c.key(obj)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.objects[key]; ok {
+		delete(c.objects, key)
+		for i := range c.keys {
+			if c.keys[i] == key {
+				c.keys = append(c.keys[:i], c.keys[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (c *renderClient) key(obj client.Object) (renderKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return renderKey{}, err
+	}
+	return renderKey{
+		GroupKind:      gvk.GroupKind(),
+		NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+	}, nil
+}
+
+// record remembers the last state of the object, the position of the object is kept on updates
+func (c *renderClient) record(obj client.Object) error {
+	key, err := c.key(obj)
+	if err != nil {
+		return err
+	}
+	gvk, _ := apiutil.GVKForObject(obj, c.Scheme())
+	copied, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unsupported object %T", obj)
+	}
+	copied.GetObjectKind().SetGroupVersionKind(gvk)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.objects[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.objects[key] = copied
+	return nil
+}
+
+// write writes recorded objects as a multi-document YAML.
+// Fields which are set by the API server and the status are removed.
+func (c *renderClient) write(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var buf bytes.Buffer
+	for _, key := range c.keys {
+		content, err := objectManifest(c.objects[key])
+		if err != nil {
+			return err
+		}
+		if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+			// The rendered custom resource instance doesn't exist in the cluster, the garbage collector
+			// would delete objects with references to it
+			removeRenderOwnerReferences(metadata)
+		}
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// objectManifest returns the manifest of the object without the status and fields which are set by the API server
+func objectManifest(obj client.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"resourceVersion", "creationTimestamp", "managedFields", "uid", "generation", "selfLink"} {
+			delete(metadata, field)
+		}
+	}
+	return content, nil
+}
+
+// removeRenderOwnerReferences removes owner references to PlatformMonitoring from the metadata of the object
+func removeRenderOwnerReferences(metadata map[string]interface{}) {
+	refs, ok := metadata["ownerReferences"].([]interface{})
+	if !ok {
+		return
+	}
+	var kept []interface{}
+	for _, ref := range refs {
+		if r, ok := ref.(map[string]interface{}); ok && r["kind"] == "PlatformMonitoring" {
+			continue
+		}
+		kept = append(kept, ref)
+	}
+	if len(kept) == 0 {
+		delete(metadata, "ownerReferences")
+		return
+	}
+	metadata["ownerReferences"] = kept
+}
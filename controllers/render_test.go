@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	grafv1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	secv1 "github.com/openshift/api/security/v1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func renderScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		qubershiporgv1.AddToScheme,
+		promv1.AddToScheme,
+		vmetricsv1b1.AddToScheme,
+		secv1.Install,
+		grafv1.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return scheme
+}
+
+func TestRender(t *testing.T) {
+	scheme := renderScheme(t)
+	newCR := func() *qubershiporgv1.PlatformMonitoring {
+		install := true
+		return &qubershiporgv1.PlatformMonitoring{
+			ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"},
+			Spec: qubershiporgv1.PlatformMonitoringSpec{
+				Pushgateway: &qubershiporgv1.Pushgateway{Install: &install},
+			},
+		}
+	}
+	opts := RenderOptions{KubernetesVersion: "v1.30.0", IngressAPI: IngressAPIV1}
+
+	t.Run("Manifests", func(t *testing.T) {
+		var out bytes.Buffer
+		results, err := Render(context.Background(), newCR(), opts, scheme, &out)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, results)
+		for _, result := range results {
+			assert.False(t, result.Failed(), "component %s failed: %v", result.Name, result.Err)
+		}
+		manifests := out.String()
+		assert.Contains(t, manifests, "kind: Deployment")
+		assert.Contains(t, manifests, "kind: Service")
+		assert.Contains(t, manifests, "name: pushgateway")
+		assert.Contains(t, manifests, "namespace: monitoring")
+		assert.NotContains(t, manifests, "ownerReferences")
+		assert.NotContains(t, manifests, "resourceVersion")
+		assert.NotContains(t, manifests, "managedFields")
+		assert.True(t, strings.HasPrefix(manifests, "---\n"))
+	})
+	t.Run("Deterministic", func(t *testing.T) {
+		var first, second bytes.Buffer
+		_, err := Render(context.Background(), newCR(), opts, scheme, &first)
+		assert.NoError(t, err)
+		_, err = Render(context.Background(), newCR(), opts, scheme, &second)
+		assert.NoError(t, err)
+		assert.Equal(t, first.String(), second.String())
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		var out bytes.Buffer
+		_, err := Render(context.Background(), newCR(), RenderOptions{KubernetesVersion: "v1.30.0", IngressAPI: "v2"}, scheme, &out)
+		assert.Error(t, err)
+		_, err = Render(context.Background(), newCR(), RenderOptions{KubernetesVersion: "latest", IngressAPI: IngressAPIV1}, scheme, &out)
+		assert.Error(t, err)
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestParseKubernetesVersion(t *testing.T) {
+	info, err := parseKubernetesVersion("v1.30.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.Major)
+	assert.Equal(t, "30", info.Minor)
+	assert.Equal(t, "v1.30.2", info.GitVersion)
+
+	info, err = parseKubernetesVersion("1.28")
+	assert.NoError(t, err)
+	assert.Equal(t, "28", info.Minor)
+	assert.Equal(t, "v1.28", info.GitVersion)
+
+	_, err = parseKubernetesVersion("v1")
+	assert.Error(t, err)
+}
+
+func TestIsListPath(t *testing.T) {
+	assert.True(t, isListPath("/api/v1/nodes"))
+	assert.True(t, isListPath("/api/v1/namespaces/monitoring/secrets"))
+	assert.True(t, isListPath("/apis/apps/v1/namespaces/monitoring/deployments"))
+	assert.True(t, isListPath("/apis/apps/v1/deployments"))
+	assert.False(t, isListPath("/api/v1/nodes/node-1"))
+	assert.False(t, isListPath("/api/v1/namespaces/monitoring/secrets/etcd-certs"))
+	assert.False(t, isListPath("/apis/apps/v1/namespaces/monitoring/deployments/pushgateway"))
+	assert.False(t, isListPath("/version"))
+}
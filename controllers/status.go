@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	prometheusrules "github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus-rules"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the conditions of PlatformMonitoring
+const (
+	reasonReconciling        = "Reconciling"
+	reasonReconciled         = "Reconciled"
+	reasonComponentsFailed   = "ComponentsFailed"
+	reasonComponentsNotReady = "ComponentsNotReady"
+	reasonComponentsReady    = "ComponentsReady"
+	reasonInvalidRules       = "InvalidRuleExpressions"
+	reasonRulesValid         = "RulesValid"
+)
+
+// setCondition adds or updates the condition of custom resource instance.
+// LastTransitionTime changes only if the status of the condition changes.
+// Returns true if the condition was changed.
+func setCondition(cr *qubershiporgv1.PlatformMonitoring, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: cr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// startReconcileStatus marks custom resource instance as progressing
+func (r *PlatformMonitoringReconciler) startReconcileStatus(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) error {
+	if !setCondition(cr, qubershiporgv1.ConditionProgressing, metav1.ConditionTrue, reasonReconciling, "Monitoring service reconcile cycle in progress") {
+		// Update status only if it is not equal to the last one
+		return nil
+	}
+	return r.Client.Status().Update(ctx, cr)
+}
+
+// finishReconcileStatus fills status of custom resource instance by results of components reconciliation.
+// If only a part of components was reconciled, statuses of other components are kept.
+// Returns true if at least one component failed or was skipped.
+func (r *PlatformMonitoringReconciler) finishReconcileStatus(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring, results []utils.ComponentResult, partial bool) bool {
+	components := make(map[string]qubershiporgv1.ComponentStatus, len(results))
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		components[result.Name] = r.componentStatus(ctx, cr, result)
+		names = append(names, result.Name)
+	}
+	if partial {
+		var kept []string
+		for name, status := range cr.Status.Components {
+			if _, ok := components[name]; !ok {
+				components[name] = status
+				kept = append(kept, name)
+			}
+		}
+		sort.Strings(kept)
+		names = append(names, kept...)
+	}
+	var failed, notReady []string
+	for _, name := range names {
+		switch components[name].Phase {
+		case qubershiporgv1.ComponentFailed, qubershiporgv1.ComponentSkipped:
+			failed = append(failed, name)
+		case qubershiporgv1.ComponentProgressing:
+			notReady = append(notReady, name)
+		}
+	}
+	cr.Status.Components = components
+	cr.Status.ObservedGeneration = cr.Generation
+
+	if len(failed) > 0 {
+		setCondition(cr, qubershiporgv1.ConditionDegraded, metav1.ConditionTrue, reasonComponentsFailed,
+			"Failed or skipped components: "+strings.Join(failed, ", "))
+	} else {
+		setCondition(cr, qubershiporgv1.ConditionDegraded, metav1.ConditionFalse, reasonReconciled,
+			"Monitoring service reconcile cycle succeeded")
+	}
+	switch {
+	case len(failed) > 0:
+		setCondition(cr, qubershiporgv1.ConditionAvailable, metav1.ConditionFalse, reasonComponentsFailed,
+			"Failed or skipped components: "+strings.Join(failed, ", "))
+	case len(notReady) > 0:
+		setCondition(cr, qubershiporgv1.ConditionAvailable, metav1.ConditionFalse, reasonComponentsNotReady,
+			"Components are not ready: "+strings.Join(notReady, ", "))
+	default:
+		setCondition(cr, qubershiporgv1.ConditionAvailable, metav1.ConditionTrue, reasonComponentsReady,
+			"All enabled components are ready")
+	}
+	setCondition(cr, qubershiporgv1.ConditionProgressing, metav1.ConditionFalse, reasonReconciled,
+		"Monitoring service reconcile cycle finished")
+	setRulesCondition(cr, results)
+	r.setStorageStatus(cr, metav1.Now())
+	r.setDataMigrationStatus(ctx, cr, metav1.Now())
+	r.setBackupStatus(ctx, cr)
+	r.setRetentionPolicyStatus(ctx, cr)
+	return len(failed) > 0
+}
+
+// setRulesCondition sets the RulesValid condition by the result of the prometheus-rules component.
+// The condition is kept if the component was not reconciled or failed by other reasons
+// and removed if Prometheus rules are not installed.
+func setRulesCondition(cr *qubershiporgv1.PlatformMonitoring, results []utils.ComponentResult) {
+	for _, result := range results {
+		if result.Name != prometheusRulesComponent || result.Skipped() {
+			continue
+		}
+		var invalid *prometheusrules.InvalidRulesError
+		switch {
+		case !componentInfos[prometheusRulesComponent].installed(cr):
+			meta.RemoveStatusCondition(&cr.Status.Conditions, qubershiporgv1.ConditionRulesValid)
+		case errors.As(result.Err, &invalid):
+			setCondition(cr, qubershiporgv1.ConditionRulesValid, metav1.ConditionFalse, reasonInvalidRules, invalid.Error())
+		case result.Err == nil:
+			setCondition(cr, qubershiporgv1.ConditionRulesValid, metav1.ConditionTrue, reasonRulesValid,
+				"Expressions of all Prometheus rules are valid")
+		}
+	}
+}
+
+// componentStatus returns the observed state of the component by result of its reconciliation
+func (r *PlatformMonitoringReconciler) componentStatus(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring, result utils.ComponentResult) qubershiporgv1.ComponentStatus {
+	info, found := componentInfos[result.Name]
+	if found && info.installed != nil && !info.installed(cr) {
+		return qubershiporgv1.ComponentStatus{Phase: qubershiporgv1.ComponentDisabled}
+	}
+
+	status := qubershiporgv1.ComponentStatus{}
+	if found && info.image != nil {
+		status.Version = utils.GetTagFromImage(info.image(cr))
+	}
+	switch {
+	case result.Skipped():
+		status.Phase = qubershiporgv1.ComponentSkipped
+		status.LastError = "dependency " + result.SkippedBy + " failed"
+		return status
+	case result.Err != nil:
+		status.Phase = qubershiporgv1.ComponentFailed
+		status.LastError = result.Err.Error()
+		return status
+	}
+
+	status.Phase = qubershiporgv1.ComponentReady
+	if !found || len(info.workloads) == 0 {
+		return status
+	}
+	for _, w := range info.workloads {
+		ready, desired, err := r.workloadReplicas(ctx, cr.GetNamespace(), w)
+		if err != nil {
+			r.Log.Error(err, "Failed to get replicas of "+w.kind+" "+w.name, utils.ComponentKey, result.Name)
+			status.Phase = qubershiporgv1.ComponentProgressing
+			continue
+		}
+		status.ReadyReplicas += ready
+		status.DesiredReplicas += desired
+		if ready < desired {
+			status.Phase = qubershiporgv1.ComponentProgressing
+		}
+	}
+	return status
+}
+
+// workloadReplicas returns ready and desired replicas of the Deployment, StatefulSet or DaemonSet
+func (r *PlatformMonitoringReconciler) workloadReplicas(ctx context.Context, namespace string, w workloadRef) (int32, int32, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: w.name}
+	switch w.kind {
+	case "StatefulSet":
+		sts := &appsv1.StatefulSet{}
+		if err := r.Client.Get(ctx, key, sts); err != nil {
+			return 0, 0, err
+		}
+		return sts.Status.ReadyReplicas, replicasOrDefault(sts.Spec.Replicas), nil
+	case "DaemonSet":
+		ds := &appsv1.DaemonSet{}
+		if err := r.Client.Get(ctx, key, ds); err != nil {
+			return 0, 0, err
+		}
+		return ds.Status.NumberReady, ds.Status.DesiredNumberScheduled, nil
+	default:
+		deployment := &appsv1.Deployment{}
+		if err := r.Client.Get(ctx, key, deployment); err != nil {
+			return 0, 0, err
+		}
+		return deployment.Status.ReadyReplicas, replicasOrDefault(deployment.Spec.Replicas), nil
+	}
+}
+
+// replicasOrDefault returns the number of replicas, Kubernetes uses 1 if replicas are not set
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// MigrateLegacyStatus removes conditions in the format used by previous versions of the operator
+// from all PlatformMonitoring objects in the namespace.
+// Previous versions stored lastTransitionTime as an arbitrary string which can't be decoded as metav1.Condition,
+// so the objects must be fixed before the controller starts reading them.
+// The client should not use the cache of the manager because the cache is not started yet.
+func MigrateLegacyStatus(ctx context.Context, c client.Client, namespace string) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(qubershiporgv1.SchemeGroupVersion.WithKind("PlatformMonitoringList"))
+	if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	for i := range list.Items {
+		item := &list.Items[i]
+		conditions, found, err := unstructured.NestedSlice(item.Object, "status", "conditions")
+		if err != nil || !found || !hasLegacyConditions(conditions) {
+			continue
+		}
+		unstructured.RemoveNestedField(item.Object, "status", "conditions")
+		if err = c.Status().Update(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasLegacyConditions returns true if at least one condition has lastTransitionTime not in RFC3339 format
+func hasLegacyConditions(conditions []interface{}) bool {
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			return true
+		}
+		transitionTime, _ := condition["lastTransitionTime"].(string)
+		if _, err := time.Parse(time.RFC3339, transitionTime); err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	prometheusrules "github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus-rules"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func statusReconciler(t *testing.T, objects ...runtime.Object) *PlatformMonitoringReconciler {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return &PlatformMonitoringReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build(),
+		Scheme: scheme,
+		Log:    utils.Logger("test"),
+	}
+}
+
+func statusCR() *qubershiporgv1.PlatformMonitoring {
+	install := true
+	return &qubershiporgv1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Generation: 3},
+		Spec: qubershiporgv1.PlatformMonitoringSpec{
+			Pushgateway: &qubershiporgv1.Pushgateway{Install: &install, Image: "prom/pushgateway:v1.9.0"},
+		},
+	}
+}
+
+func TestComponentStatus(t *testing.T) {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.PushgatewayComponentName, Namespace: "monitoring"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	r := statusReconciler(t, deployment)
+	cr := statusCR()
+
+	t.Run("Test disabled component", func(t *testing.T) {
+		status := r.componentStatus(context.Background(), cr, utils.ComponentResult{Name: grafanaComponent})
+		assert.Equal(t, qubershiporgv1.ComponentDisabled, status.Phase)
+	})
+	t.Run("Test progressing component", func(t *testing.T) {
+		status := r.componentStatus(context.Background(), cr, utils.ComponentResult{Name: pushgatewayComponent})
+		assert.Equal(t, qubershiporgv1.ComponentProgressing, status.Phase)
+		assert.Equal(t, "v1.9.0", status.Version)
+		assert.Equal(t, int32(1), status.ReadyReplicas)
+		assert.Equal(t, int32(2), status.DesiredReplicas)
+	})
+	t.Run("Test failed component", func(t *testing.T) {
+		status := r.componentStatus(context.Background(), cr,
+			utils.ComponentResult{Name: pushgatewayComponent, Err: errors.New("deployment is invalid")})
+		assert.Equal(t, qubershiporgv1.ComponentFailed, status.Phase)
+		assert.Equal(t, "deployment is invalid", status.LastError)
+	})
+	t.Run("Test skipped component", func(t *testing.T) {
+		status := r.componentStatus(context.Background(), cr,
+			utils.ComponentResult{Name: pushgatewayComponent, SkippedBy: prometheusOperatorComponent})
+		assert.Equal(t, qubershiporgv1.ComponentSkipped, status.Phase)
+		assert.Contains(t, status.LastError, prometheusOperatorComponent)
+	})
+}
+
+func TestFinishReconcileStatus(t *testing.T) {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.PushgatewayComponentName, Namespace: "monitoring"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	r := statusReconciler(t, deployment)
+
+	t.Run("Test all components ready", func(t *testing.T) {
+		cr := statusCR()
+		degraded := r.finishReconcileStatus(context.Background(), cr, []utils.ComponentResult{
+			{Name: pushgatewayComponent},
+			{Name: grafanaComponent},
+		}, false)
+		assert.False(t, degraded)
+		assert.Equal(t, int64(3), cr.Status.ObservedGeneration)
+		assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, qubershiporgv1.ConditionAvailable))
+		assert.True(t, meta.IsStatusConditionFalse(cr.Status.Conditions, qubershiporgv1.ConditionDegraded))
+		assert.True(t, meta.IsStatusConditionFalse(cr.Status.Conditions, qubershiporgv1.ConditionProgressing))
+		assert.Equal(t, qubershiporgv1.ComponentReady, cr.Status.Components[pushgatewayComponent].Phase)
+		assert.Equal(t, qubershiporgv1.ComponentDisabled, cr.Status.Components[grafanaComponent].Phase)
+	})
+	t.Run("Test failed component", func(t *testing.T) {
+		cr := statusCR()
+		degraded := r.finishReconcileStatus(context.Background(), cr, []utils.ComponentResult{
+			{Name: prometheusOperatorComponent, Err: errors.New("failed")},
+			{Name: pushgatewayComponent, SkippedBy: prometheusOperatorComponent},
+		}, false)
+		assert.True(t, degraded)
+		degradedCondition := meta.FindStatusCondition(cr.Status.Conditions, qubershiporgv1.ConditionDegraded)
+		if assert.NotNil(t, degradedCondition) {
+			assert.Equal(t, metav1.ConditionTrue, degradedCondition.Status)
+			assert.Contains(t, degradedCondition.Message, pushgatewayComponent)
+			assert.Equal(t, int64(3), degradedCondition.ObservedGeneration)
+		}
+		assert.True(t, meta.IsStatusConditionFalse(cr.Status.Conditions, qubershiporgv1.ConditionAvailable))
+	})
+	t.Run("Test partial reconciliation", func(t *testing.T) {
+		cr := statusCR()
+		cr.Status.Components = map[string]qubershiporgv1.ComponentStatus{
+			prometheusOperatorComponent: {Phase: qubershiporgv1.ComponentFailed, LastError: "failed"},
+			pushgatewayComponent:        {Phase: qubershiporgv1.ComponentProgressing},
+		}
+		degraded := r.finishReconcileStatus(context.Background(), cr, []utils.ComponentResult{
+			{Name: pushgatewayComponent},
+		}, true)
+		assert.True(t, degraded)
+		assert.Equal(t, qubershiporgv1.ComponentReady, cr.Status.Components[pushgatewayComponent].Phase)
+		assert.Equal(t, qubershiporgv1.ComponentFailed, cr.Status.Components[prometheusOperatorComponent].Phase)
+		degradedCondition := meta.FindStatusCondition(cr.Status.Conditions, qubershiporgv1.ConditionDegraded)
+		if assert.NotNil(t, degradedCondition) {
+			assert.Contains(t, degradedCondition.Message, prometheusOperatorComponent)
+		}
+	})
+}
+
+func TestRulesCondition(t *testing.T) {
+	install := true
+	cr := statusCR()
+	cr.Spec.PrometheusRules = &qubershiporgv1.PrometheusRules{Install: &install}
+	invalid := &prometheusrules.InvalidRulesError{Rules: []prometheusrules.RuleError{
+		{Group: "SelfMonitoring", Alert: "PrometheusJobMissing", Err: errors.New("unparsed data left: \"==\"")},
+	}}
+
+	setRulesCondition(cr, []utils.ComponentResult{{Name: prometheusRulesComponent, Err: invalid}})
+	condition := meta.FindStatusCondition(cr.Status.Conditions, qubershiporgv1.ConditionRulesValid)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, reasonInvalidRules, condition.Reason)
+		assert.Contains(t, condition.Message, "group SelfMonitoring, alert PrometheusJobMissing: unparsed data left")
+	}
+
+	// The condition is kept if the component was not reconciled or failed by other reasons
+	setRulesCondition(cr, []utils.ComponentResult{{Name: prometheusRulesComponent, Err: errors.New("timeout")}})
+	setRulesCondition(cr, []utils.ComponentResult{{Name: pushgatewayComponent}})
+	assert.True(t, meta.IsStatusConditionFalse(cr.Status.Conditions, qubershiporgv1.ConditionRulesValid))
+
+	setRulesCondition(cr, []utils.ComponentResult{{Name: prometheusRulesComponent}})
+	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, qubershiporgv1.ConditionRulesValid))
+
+	cr.Spec.PrometheusRules.Install = nil
+	setRulesCondition(cr, []utils.ComponentResult{{Name: prometheusRulesComponent}})
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, qubershiporgv1.ConditionRulesValid))
+}
+
+func TestHasLegacyConditions(t *testing.T) {
+	assert.True(t, hasLegacyConditions([]interface{}{
+		map[string]interface{}{
+			"type":               "Successful",
+			"lastTransitionTime": "2024-01-01 10:00:00.123456 +0000 UTC m=+1.000000001",
+		},
+	}))
+	assert.False(t, hasLegacyConditions([]interface{}{
+		map[string]interface{}{
+			"type":               qubershiporgv1.ConditionAvailable,
+			"lastTransitionTime": "2024-01-01T10:00:00Z",
+		},
+	}))
+}
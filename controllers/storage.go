@@ -0,0 +1,383 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmbackup"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	reasonStorageMigrationStarted   = "StorageMigrationStarted"
+	reasonStorageMigrationCompleted = "StorageMigrationCompleted"
+	reasonDataMigrationStarted      = "DataMigrationStarted"
+	reasonDataMigrationSucceeded    = "DataMigrationSucceeded"
+	reasonDataMigrationFailed       = "DataMigrationFailed"
+	reasonMigrationSourceDeleted    = "MigrationSourceDeleted"
+	reasonBackupSucceeded           = "BackupSucceeded"
+	reasonBackupFailed              = "BackupFailed"
+)
+
+// dataMigrationPollInterval is the interval of reconciliations while the data migration or a backup is in progress,
+// because changes of the status of the Job don't trigger reconciliation
+const dataMigrationPollInterval = 30 * time.Second
+
+// storageBackend returns the installed storage backend of VictoriaMetrics and the name of its component.
+// Returns empty strings if neither VmSingle nor VmCluster is installed.
+func storageBackend(cr *qubershiporgv1.PlatformMonitoring) (qubershiporgv1.StorageBackend, string) {
+	switch {
+	case componentInfos[vmClusterComponent].installed(cr):
+		return qubershiporgv1.StorageBackendVMCluster, vmClusterComponent
+	case componentInfos[vmSingleComponent].installed(cr):
+		return qubershiporgv1.StorageBackendVMSingle, vmSingleComponent
+	}
+	return "", ""
+}
+
+// setStorageStatus tracks the storage backend of VictoriaMetrics by statuses of components.
+// If the backend differs from the last observed one, the migration is started and it is completed
+// when the component of the new backend becomes ready. The status is kept while no backend is installed,
+// so the switch is tracked even if the old backend is disabled before the new one is enabled.
+func (r *PlatformMonitoringReconciler) setStorageStatus(cr *qubershiporgv1.PlatformMonitoring, now metav1.Time) {
+	backend, component := storageBackend(cr)
+	if backend == "" {
+		return
+	}
+	status := cr.Status.Storage
+	if status == nil {
+		// The backend of instances created before the status was introduced is observed without migration
+		cr.Status.Storage = &qubershiporgv1.StorageStatus{Backend: backend}
+		return
+	}
+	if status.Backend != backend {
+		status.Migration = &qubershiporgv1.StorageMigration{
+			From:      status.Backend,
+			To:        backend,
+			Phase:     qubershiporgv1.StorageMigrationInProgress,
+			StartedAt: now,
+		}
+		status.Backend = backend
+		r.event(cr, corev1.EventTypeNormal, reasonStorageMigrationStarted,
+			fmt.Sprintf("Storage of VictoriaMetrics is switched from %s to %s", status.Migration.From, backend))
+	}
+
+	migration := status.Migration
+	if migration == nil || migration.Phase != qubershiporgv1.StorageMigrationInProgress {
+		return
+	}
+	componentStatus := cr.Status.Components[component]
+	switch componentStatus.Phase {
+	case qubershiporgv1.ComponentReady:
+		migration.Phase = qubershiporgv1.StorageMigrationCompleted
+		migration.CompletedAt = &now
+		migration.Message = fmt.Sprintf("%s is ready", backend)
+		r.event(cr, corev1.EventTypeNormal, reasonStorageMigrationCompleted, migration.Message)
+	case qubershiporgv1.ComponentFailed, qubershiporgv1.ComponentSkipped:
+		migration.Message = componentStatus.LastError
+	default:
+		migration.Message = fmt.Sprintf("%d of %d pods of %s are ready",
+			componentStatus.ReadyReplicas, componentStatus.DesiredReplicas, backend)
+	}
+}
+
+// setDataMigrationStatus tracks the vmctl Job of the data migration by its status.
+// The status of the migration is reset if parameters of the migration are changed, so the new Job is tracked,
+// and removed if the migration is removed. The source is reported as deleted if the migration succeeded
+// and the deletion was confirmed, because the source was deleted by this reconciliation.
+func (r *PlatformMonitoringReconciler) setDataMigrationStatus(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring, now metav1.Time) {
+	status := cr.Status.Storage
+	if status == nil {
+		return
+	}
+	destination := victoriametrics.GetMigrationDestination(cr)
+	if destination == "" {
+		status.DataMigration = nil
+		return
+	}
+	source := cr.Spec.Victoriametrics.Migration.Source
+	// Must be checked before the status is changed because components used the last observed status
+	sourceDeleted := !victoriametrics.KeepMigrationSource(cr, source)
+	jobName := victoriametrics.GetMigrationJobName(cr)
+	migration := status.DataMigration
+	if migration == nil || migration.Job != jobName {
+		migration = &qubershiporgv1.DataMigrationStatus{
+			Source:      source,
+			Destination: destination,
+			Phase:       qubershiporgv1.DataMigrationPending,
+			Job:         jobName,
+		}
+		status.DataMigration = migration
+	}
+	if migration.SourceDeleted {
+		return
+	}
+
+	job := &batchv1.Job{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: jobName}, job)
+	if apierrors.IsNotFound(err) {
+		migration.Phase = qubershiporgv1.DataMigrationPending
+		migration.StartedAt = nil
+		migration.CompletedAt = nil
+		migration.Message = fmt.Sprintf("Waiting for %s to be operational", destination)
+		return
+	}
+	if err != nil {
+		r.Log.Error(err, "Failed to get Job "+jobName)
+		return
+	}
+
+	previous := migration.Phase
+	migration.StartedAt = job.Status.StartTime
+	migration.Phase, migration.Message = jobPhase(job)
+	switch migration.Phase {
+	case qubershiporgv1.DataMigrationSucceeded:
+		migration.CompletedAt = job.Status.CompletionTime
+		if migration.CompletedAt == nil {
+			migration.CompletedAt = &now
+		}
+	case qubershiporgv1.DataMigrationFailed:
+		if migration.CompletedAt == nil {
+			migration.CompletedAt = &now
+		}
+	default:
+		migration.CompletedAt = nil
+	}
+	if migration.Phase != previous {
+		switch migration.Phase {
+		case qubershiporgv1.DataMigrationRunning:
+			r.event(cr, corev1.EventTypeNormal, reasonDataMigrationStarted,
+				fmt.Sprintf("Migration of data from %s to %s is started by Job %s", source, destination, jobName))
+		case qubershiporgv1.DataMigrationSucceeded:
+			r.event(cr, corev1.EventTypeNormal, reasonDataMigrationSucceeded,
+				fmt.Sprintf("Migration of data from %s to %s succeeded", source, destination))
+		case qubershiporgv1.DataMigrationFailed:
+			r.event(cr, corev1.EventTypeWarning, reasonDataMigrationFailed,
+				fmt.Sprintf("Migration of data from %s to %s failed: %s", source, destination, migration.Message))
+		}
+	}
+	if previous == qubershiporgv1.DataMigrationSucceeded && migration.Phase == qubershiporgv1.DataMigrationSucceeded && sourceDeleted {
+		migration.SourceDeleted = true
+		r.event(cr, corev1.EventTypeNormal, reasonMigrationSourceDeleted,
+			fmt.Sprintf("%s is deleted after the migration of its data to %s", source, destination))
+	}
+}
+
+// jobPhase returns the phase of the data migration and the message by conditions of the Job
+func jobPhase(job *batchv1.Job) (qubershiporgv1.DataMigrationPhase, string) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return qubershiporgv1.DataMigrationSucceeded, "Job " + job.GetName() + " completed"
+		case batchv1.JobFailed:
+			return qubershiporgv1.DataMigrationFailed, fmt.Sprintf("Job %s failed: %s", job.GetName(), c.Message)
+		}
+	}
+	return qubershiporgv1.DataMigrationRunning, fmt.Sprintf("Job %s is running: %d active pods, %d failed pods",
+		job.GetName(), job.Status.Active, job.Status.Failed)
+}
+
+// dataMigrationInProgress returns true if the data migration is pending or running
+func dataMigrationInProgress(cr *qubershiporgv1.PlatformMonitoring) bool {
+	if cr.Status.Storage == nil || cr.Status.Storage.DataMigration == nil {
+		return false
+	}
+	phase := cr.Status.Storage.DataMigration.Phase
+	return phase == qubershiporgv1.DataMigrationPending || phase == qubershiporgv1.DataMigrationRunning
+}
+
+// setBackupStatus tracks vmbackup CronJobs of storage nodes by their last Jobs.
+// The generation and the time of the last successful backup are kept in the status
+// after its Job is removed by the history limit of the CronJob.
+func (r *PlatformMonitoringReconciler) setBackupStatus(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) {
+	status := cr.Status.Storage
+	if status == nil {
+		return
+	}
+	if victoriametrics.GetBackup(cr) == nil {
+		status.Backups = nil
+		return
+	}
+	selector := client.MatchingLabels{vmbackup.ComponentLabel: utils.VmBackupComponentName}
+	cronJobs := &batchv1.CronJobList{}
+	if err := r.Client.List(ctx, cronJobs, client.InNamespace(cr.GetNamespace()), selector); err != nil {
+		r.Log.Error(err, "Failed to list CronJobs of backups")
+		return
+	}
+	jobs := &batchv1.JobList{}
+	if err := r.Client.List(ctx, jobs, client.InNamespace(cr.GetNamespace()), selector); err != nil {
+		r.Log.Error(err, "Failed to list Jobs of backups")
+		return
+	}
+	sort.Slice(cronJobs.Items, func(i, j int) bool { return cronJobs.Items[i].GetName() < cronJobs.Items[j].GetName() })
+
+	previous := map[string]qubershiporgv1.BackupStatus{}
+	for _, backup := range status.Backups {
+		previous[backup.Target] = backup
+	}
+	backups := make([]qubershiporgv1.BackupStatus, 0, len(cronJobs.Items))
+	for _, cronJob := range cronJobs.Items {
+		target := cronJob.Labels[victoriametrics.BackupTargetLabel]
+		backup := previous[target]
+		backup.Target = target
+		backup.CronJob = cronJob.GetName()
+		if cronJob.Status.LastScheduleTime != nil {
+			backup.LastScheduleTime = cronJob.Status.LastScheduleTime
+		}
+
+		var latest, succeeded *batchv1.Job
+		for i := range jobs.Items {
+			job := &jobs.Items[i]
+			if job.Labels[victoriametrics.BackupTargetLabel] != target {
+				continue
+			}
+			if latest == nil || latest.CreationTimestamp.Before(&job.CreationTimestamp) {
+				latest = job
+			}
+			if phase, _ := jobPhase(job); phase == qubershiporgv1.DataMigrationSucceeded && job.Status.CompletionTime != nil &&
+				(succeeded == nil || succeeded.Status.CompletionTime.Before(job.Status.CompletionTime)) {
+				succeeded = job
+			}
+		}
+		if succeeded != nil {
+			if generation, err := strconv.ParseInt(succeeded.Labels[victoriametrics.BackupGenerationLabel], 10, 32); err == nil {
+				backup.Generation = ptr.To(int32(generation))
+			}
+			backup.LastSuccessTime = succeeded.Status.CompletionTime
+		}
+		if latest != nil {
+			// Jobs of backups have the same phases as the Job of the data migration
+			phase, message := jobPhase(latest)
+			backup.Message = message
+			if backup.Phase != qubershiporgv1.BackupPhase(phase) {
+				backup.Phase = qubershiporgv1.BackupPhase(phase)
+				switch backup.Phase {
+				case qubershiporgv1.BackupSucceeded:
+					r.event(cr, corev1.EventTypeNormal, reasonBackupSucceeded,
+						fmt.Sprintf("Backup of %s succeeded by Job %s", target, latest.GetName()))
+				case qubershiporgv1.BackupFailed:
+					r.event(cr, corev1.EventTypeWarning, reasonBackupFailed,
+						fmt.Sprintf("Backup of %s failed: %s", target, message))
+				}
+			}
+		}
+		backups = append(backups, backup)
+	}
+	status.Backups = backups
+}
+
+// backupInProgress returns true if a backup of any storage node is running
+func backupInProgress(cr *qubershiporgv1.PlatformMonitoring) bool {
+	if cr.Status.Storage == nil {
+		return false
+	}
+	for _, backup := range cr.Status.Storage.Backups {
+		if backup.Phase == qubershiporgv1.BackupRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// setRetentionPolicyStatus reports flags of the retention policy and the estimated disk usage of the storage.
+// The estimate is multiplied by the replication factor of VMCluster, divided between vmstorage replicas
+// and compared with the requested size of the volume of one storage node.
+func (r *PlatformMonitoringReconciler) setRetentionPolicyStatus(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) {
+	status := cr.Status.Storage
+	if status == nil {
+		return
+	}
+	policy := victoriametrics.GetRetentionPolicy(cr)
+	if policy == nil {
+		status.RetentionPolicy = nil
+		return
+	}
+	args := victoriametrics.GetRetentionPolicyArgs(cr)
+	result := &qubershiporgv1.RetentionPolicyStatus{
+		RetentionFilter:    args[victoriametrics.RetentionFilterFlag],
+		DownsamplingPeriod: args[victoriametrics.DownsamplingPeriodFlag],
+	}
+	status.RetentionPolicy = result
+	if policy.Estimate == nil {
+		return
+	}
+	size, err := victoriametrics.EstimateDiskUsage(cr)
+	if err != nil {
+		result.Message = fmt.Sprintf("Can't estimate the disk usage: %v", err)
+		return
+	}
+	replication, nodes, request, err := r.storageCapacity(ctx, cr)
+	if err != nil {
+		r.Log.Error(err, "Failed to get the capacity of the storage")
+		result.Message = fmt.Sprintf("Can't get the capacity of the storage: %v", err)
+		return
+	}
+	total := size * replication
+	result.EstimatedDiskUsage = diskUsageQuantity(total)
+	result.EstimatedDiskUsagePerNode = diskUsageQuantity(total / nodes)
+	if request != nil && request.Cmp(*result.EstimatedDiskUsagePerNode) < 0 {
+		result.Message = fmt.Sprintf("The estimated disk usage %s of a storage node exceeds the requested storage %s",
+			result.EstimatedDiskUsagePerNode, request)
+	}
+}
+
+// storageCapacity returns the replication factor, the number of storage nodes and the requested size of the volume
+// of one storage node from VMCluster or VMSingle. The size is nil if the storage is not created yet or has no volumes.
+func (r *PlatformMonitoringReconciler) storageCapacity(ctx context.Context, cr *qubershiporgv1.PlatformMonitoring) (int64, int64, *resource.Quantity, error) {
+	key := types.NamespacedName{Name: utils.VmComponentName, Namespace: cr.GetNamespace()}
+	if cr.Spec.Victoriametrics.VmCluster.IsInstall() {
+		vmCluster := &vmetricsv1b1.VMCluster{}
+		if err := r.Client.Get(ctx, key, vmCluster); err != nil {
+			return 1, 1, nil, client.IgnoreNotFound(err)
+		}
+		replication := int64(ptr.Deref(vmCluster.Spec.ReplicationFactor, 1))
+		vmStorage := vmCluster.Spec.VMStorage
+		if vmStorage == nil {
+			return replication, 1, nil, nil
+		}
+		nodes := int64(ptr.Deref(vmStorage.ReplicaCount, 1))
+		if vmStorage.Storage == nil {
+			return replication, nodes, nil, nil
+		}
+		return replication, nodes, storageRequest(vmStorage.Storage.VolumeClaimTemplate.Spec.Resources.Requests), nil
+	}
+	vmSingle := &vmetricsv1b1.VMSingle{}
+	if err := r.Client.Get(ctx, key, vmSingle); err != nil {
+		return 1, 1, nil, client.IgnoreNotFound(err)
+	}
+	if vmSingle.Spec.Storage == nil {
+		return 1, 1, nil, nil
+	}
+	return 1, 1, storageRequest(vmSingle.Spec.Storage.Resources.Requests), nil
+}
+
+// storageRequest returns the requested storage or nil if it is not set
+func storageRequest(requests corev1.ResourceList) *resource.Quantity {
+	if request, ok := requests[corev1.ResourceStorage]; ok && !request.IsZero() {
+		return &request
+	}
+	return nil
+}
+
+// diskUsageQuantity returns the size in bytes rounded up to mebibytes
+func diskUsageQuantity(size int64) *resource.Quantity {
+	const mebibyte = 1 << 20
+	return resource.NewQuantity((size+mebibyte-1)/mebibyte*mebibyte, resource.BinarySI)
+}
@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics/vmbackup"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func storageCR() *qubershiporgv1.PlatformMonitoring {
+	return &qubershiporgv1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: qubershiporgv1.PlatformMonitoringSpec{
+			Victoriametrics: &qubershiporgv1.Victoriametrics{
+				VmSingle: qubershiporgv1.VmSingle{Image: "victoriametrics/victoria-metrics:v1.103.0"},
+				VmCluster: qubershiporgv1.VmCluster{
+					Install:        ptr.To(false),
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+				},
+			},
+		},
+	}
+}
+
+func TestSetStorageStatus(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &PlatformMonitoringReconciler{Log: utils.Logger("test"), Recorder: recorder}
+	cr := storageCR()
+	now := metav1.Now()
+
+	t.Run("Test backend is observed without migration", func(t *testing.T) {
+		r.setStorageStatus(cr, now)
+		assert.Equal(t, &qubershiporgv1.StorageStatus{Backend: qubershiporgv1.StorageBackendVMSingle}, cr.Status.Storage)
+	})
+	t.Run("Test status is kept while no backend is installed", func(t *testing.T) {
+		cr.Spec.Victoriametrics.VmSingle.Install = ptr.To(false)
+		r.setStorageStatus(cr, now)
+		assert.Equal(t, qubershiporgv1.StorageBackendVMSingle, cr.Status.Storage.Backend)
+		assert.Nil(t, cr.Status.Storage.Migration)
+	})
+	t.Run("Test migration is started", func(t *testing.T) {
+		cr.Spec.Victoriametrics.VmCluster.Install = ptr.To(true)
+		cr.Status.Components = map[string]qubershiporgv1.ComponentStatus{
+			vmClusterComponent: {Phase: qubershiporgv1.ComponentProgressing, ReadyReplicas: 2, DesiredReplicas: 6},
+		}
+		r.setStorageStatus(cr, now)
+		assert.Equal(t, qubershiporgv1.StorageBackendVMCluster, cr.Status.Storage.Backend)
+		migration := cr.Status.Storage.Migration
+		assert.NotNil(t, migration)
+		assert.Equal(t, qubershiporgv1.StorageBackendVMSingle, migration.From)
+		assert.Equal(t, qubershiporgv1.StorageBackendVMCluster, migration.To)
+		assert.Equal(t, qubershiporgv1.StorageMigrationInProgress, migration.Phase)
+		assert.Equal(t, "2 of 6 pods of VMCluster are ready", migration.Message)
+		assert.Contains(t, <-recorder.Events, reasonStorageMigrationStarted)
+	})
+	t.Run("Test migration is in progress while the backend fails", func(t *testing.T) {
+		cr.Status.Components[vmClusterComponent] = qubershiporgv1.ComponentStatus{
+			Phase: qubershiporgv1.ComponentFailed, LastError: "vmcluster is invalid",
+		}
+		r.setStorageStatus(cr, now)
+		assert.Equal(t, qubershiporgv1.StorageMigrationInProgress, cr.Status.Storage.Migration.Phase)
+		assert.Equal(t, "vmcluster is invalid", cr.Status.Storage.Migration.Message)
+	})
+	t.Run("Test migration is completed", func(t *testing.T) {
+		cr.Status.Components[vmClusterComponent] = qubershiporgv1.ComponentStatus{Phase: qubershiporgv1.ComponentReady}
+		r.setStorageStatus(cr, now)
+		migration := cr.Status.Storage.Migration
+		assert.Equal(t, qubershiporgv1.StorageMigrationCompleted, migration.Phase)
+		assert.NotNil(t, migration.CompletedAt)
+		assert.Contains(t, <-recorder.Events, reasonStorageMigrationCompleted)
+
+		r.setStorageStatus(cr, now)
+		assert.Empty(t, recorder.Events, "completed migration should not be reported again")
+	})
+}
+
+func TestSetDataMigrationStatus(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	cr := storageCR()
+	cr.Spec.Victoriametrics.VmSingle.Install = ptr.To(false)
+	cr.Spec.Victoriametrics.VmCluster.Install = ptr.To(true)
+	cr.Spec.Victoriametrics.Migration = &qubershiporgv1.VmMigration{
+		Source:    qubershiporgv1.VmMigrationSourceVMSingle,
+		Image:     "victoriametrics/vmctl:v1.104.0",
+		TimeStart: "2024-01-01T00:00:00Z",
+	}
+	cr.Status.Storage = &qubershiporgv1.StorageStatus{Backend: qubershiporgv1.StorageBackendVMCluster}
+	jobName := victoriametrics.GetMigrationJobName(cr)
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: cr.GetNamespace()}}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	r := &PlatformMonitoringReconciler{Client: c, Log: utils.Logger("test"), Recorder: recorder}
+	ctx := context.Background()
+	now := metav1.Now()
+
+	t.Run("Test migration is pending until the Job is created", func(t *testing.T) {
+		r.setDataMigrationStatus(ctx, cr, now)
+		migration := cr.Status.Storage.DataMigration
+		assert.NotNil(t, migration)
+		assert.Equal(t, qubershiporgv1.VmMigrationSourceVMSingle, migration.Source)
+		assert.Equal(t, qubershiporgv1.StorageBackendVMCluster, migration.Destination)
+		assert.Equal(t, qubershiporgv1.DataMigrationPending, migration.Phase)
+		assert.Equal(t, jobName, migration.Job)
+		assert.True(t, dataMigrationInProgress(cr))
+	})
+	t.Run("Test migration is running", func(t *testing.T) {
+		job.Status = batchv1.JobStatus{StartTime: &now, Active: 1}
+		status := job.Status
+		assert.NoError(t, c.Create(ctx, job))
+		job.Status = status
+		assert.NoError(t, c.Status().Update(ctx, job))
+		r.setDataMigrationStatus(ctx, cr, now)
+		assert.Equal(t, qubershiporgv1.DataMigrationRunning, cr.Status.Storage.DataMigration.Phase)
+		assert.NotNil(t, cr.Status.Storage.DataMigration.StartedAt)
+		assert.Contains(t, <-recorder.Events, reasonDataMigrationStarted)
+		assert.True(t, dataMigrationInProgress(cr))
+	})
+	t.Run("Test migration failed", func(t *testing.T) {
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+		}
+		assert.NoError(t, c.Status().Update(ctx, job))
+		r.setDataMigrationStatus(ctx, cr, now)
+		migration := cr.Status.Storage.DataMigration
+		assert.Equal(t, qubershiporgv1.DataMigrationFailed, migration.Phase)
+		assert.Contains(t, migration.Message, "backoff limit")
+		assert.NotNil(t, migration.CompletedAt)
+		assert.Contains(t, <-recorder.Events, reasonDataMigrationFailed)
+		assert.False(t, dataMigrationInProgress(cr))
+	})
+	t.Run("Test source is kept after the migration succeeded until the deletion is confirmed", func(t *testing.T) {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		job.Status.CompletionTime = &now
+		assert.NoError(t, c.Status().Update(ctx, job))
+		r.setDataMigrationStatus(ctx, cr, now)
+		assert.Equal(t, qubershiporgv1.DataMigrationSucceeded, cr.Status.Storage.DataMigration.Phase)
+		assert.Contains(t, <-recorder.Events, reasonDataMigrationSucceeded)
+		assert.True(t, victoriametrics.KeepMigrationSource(cr, qubershiporgv1.VmMigrationSourceVMSingle))
+
+		r.setDataMigrationStatus(ctx, cr, now)
+		assert.False(t, cr.Status.Storage.DataMigration.SourceDeleted)
+		assert.Empty(t, recorder.Events)
+	})
+	t.Run("Test source is deleted after the confirmation", func(t *testing.T) {
+		cr.Spec.Victoriametrics.Migration.ConfirmSourceDeletion = true
+		assert.False(t, victoriametrics.KeepMigrationSource(cr, qubershiporgv1.VmMigrationSourceVMSingle))
+		r.setDataMigrationStatus(ctx, cr, now)
+		assert.True(t, cr.Status.Storage.DataMigration.SourceDeleted)
+		assert.Contains(t, <-recorder.Events, reasonMigrationSourceDeleted)
+	})
+	t.Run("Test migration is restarted if its parameters are changed", func(t *testing.T) {
+		cr.Spec.Victoriametrics.Migration.TimeStart = "2024-02-01T00:00:00Z"
+		assert.True(t, victoriametrics.KeepMigrationSource(cr, qubershiporgv1.VmMigrationSourceVMSingle))
+		r.setDataMigrationStatus(ctx, cr, now)
+		migration := cr.Status.Storage.DataMigration
+		assert.NotEqual(t, jobName, migration.Job)
+		assert.Equal(t, qubershiporgv1.DataMigrationPending, migration.Phase)
+		assert.False(t, migration.SourceDeleted)
+	})
+	t.Run("Test status is removed with the migration", func(t *testing.T) {
+		cr.Spec.Victoriametrics.Migration = nil
+		r.setDataMigrationStatus(ctx, cr, now)
+		assert.Nil(t, cr.Status.Storage.DataMigration)
+	})
+}
+
+func TestSetBackupStatus(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	cr := storageCR()
+	cr.Spec.Victoriametrics.Backup = &qubershiporgv1.VmBackup{
+		Image:    "victoriametrics/vmbackup:v1.103.0",
+		Schedule: "0 1 * * *",
+		S3:       &qubershiporgv1.VmBackupS3{Bucket: "backups"},
+	}
+	cr.Status.Storage = &qubershiporgv1.StorageStatus{Backend: qubershiporgv1.StorageBackendVMSingle}
+	labels := func(generation string) map[string]string {
+		return map[string]string{
+			vmbackup.ComponentLabel:               utils.VmBackupComponentName,
+			victoriametrics.BackupTargetLabel:     victoriametrics.BackupTargetVmSingle,
+			victoriametrics.BackupGenerationLabel: generation,
+		}
+	}
+	cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{
+		Name: victoriametrics.GetBackupCronJobName(victoriametrics.BackupTargetVmSingle), Namespace: cr.GetNamespace(), Labels: labels(""),
+	}}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(cronJob).Build()
+	r := &PlatformMonitoringReconciler{Client: c, Log: utils.Logger("test"), Recorder: recorder}
+	ctx := context.Background()
+	now := metav1.Now()
+	createJob := func(name, generation string, status batchv1.JobStatus) {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: cr.GetNamespace(), Labels: labels(generation), CreationTimestamp: now,
+		}}
+		assert.NoError(t, c.Create(ctx, job))
+		job.Status = status
+		assert.NoError(t, c.Status().Update(ctx, job))
+	}
+
+	t.Run("Test CronJob without Jobs is reported", func(t *testing.T) {
+		r.setBackupStatus(ctx, cr)
+		assert.Equal(t, []qubershiporgv1.BackupStatus{{Target: "vmsingle", CronJob: "vmbackup-vmsingle"}}, cr.Status.Storage.Backups)
+		assert.False(t, backupInProgress(cr))
+	})
+	t.Run("Test running backup is reported", func(t *testing.T) {
+		createJob("vmbackup-vmsingle-1", "0", batchv1.JobStatus{Active: 1})
+		r.setBackupStatus(ctx, cr)
+		backup := cr.Status.Storage.Backups[0]
+		assert.Equal(t, qubershiporgv1.BackupRunning, backup.Phase)
+		assert.Nil(t, backup.Generation)
+		assert.True(t, backupInProgress(cr))
+	})
+	t.Run("Test successful backup is reported with its generation", func(t *testing.T) {
+		job := &batchv1.Job{}
+		assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: "vmbackup-vmsingle-1"}, job))
+		job.Status = batchv1.JobStatus{
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			CompletionTime: &now,
+		}
+		assert.NoError(t, c.Status().Update(ctx, job))
+		r.setBackupStatus(ctx, cr)
+		backup := cr.Status.Storage.Backups[0]
+		assert.Equal(t, qubershiporgv1.BackupSucceeded, backup.Phase)
+		assert.Equal(t, ptr.To(int32(0)), backup.Generation)
+		assert.NotNil(t, backup.LastSuccessTime)
+		assert.Contains(t, <-recorder.Events, reasonBackupSucceeded)
+		assert.False(t, backupInProgress(cr))
+	})
+	t.Run("Test failed backup keeps the last successful generation after its Job is removed", func(t *testing.T) {
+		assert.NoError(t, c.Delete(ctx, &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "vmbackup-vmsingle-1", Namespace: cr.GetNamespace()}}))
+		createJob("vmbackup-vmsingle-2", "1", batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}},
+		})
+		r.setBackupStatus(ctx, cr)
+		backup := cr.Status.Storage.Backups[0]
+		assert.Equal(t, qubershiporgv1.BackupFailed, backup.Phase)
+		assert.Equal(t, ptr.To(int32(0)), backup.Generation)
+		assert.NotNil(t, backup.LastSuccessTime)
+		assert.Contains(t, <-recorder.Events, reasonBackupFailed)
+	})
+	t.Run("Test status is removed with backups", func(t *testing.T) {
+		cr.Spec.Victoriametrics.Backup = nil
+		r.setBackupStatus(ctx, cr)
+		assert.Nil(t, cr.Status.Storage.Backups)
+	})
+}
+
+func TestSetRetentionPolicyStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, vmetricsv1b1.AddToScheme(scheme))
+	cr := storageCR()
+	cr.Spec.Victoriametrics.VmSingle.Install = ptr.To(false)
+	cr.Spec.Victoriametrics.VmCluster.Install = ptr.To(true)
+	cr.Spec.Victoriametrics.VmCluster.RetentionPeriod = "30d"
+	cr.Spec.Victoriametrics.RetentionPolicy = &qubershiporgv1.VmRetentionPolicy{
+		Tenants:      []qubershiporgv1.VmTenantRetention{{Tenant: "5", RetentionPeriod: "7d"}},
+		Downsampling: []qubershiporgv1.VmDownsampling{{Offset: "7d", Interval: "5m"}},
+	}
+	cr.Status.Storage = &qubershiporgv1.StorageStatus{Backend: qubershiporgv1.StorageBackendVMCluster}
+	vmCluster := &vmetricsv1b1.VMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.VmComponentName, Namespace: cr.GetNamespace()},
+		Spec: vmetricsv1b1.VMClusterSpec{
+			ReplicationFactor: ptr.To(int32(2)),
+			VMStorage: &vmetricsv1b1.VMStorage{Storage: &vmetricsv1b1.StorageSpec{
+				VolumeClaimTemplate: vmetricsv1b1.EmbeddedPersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("512Mi")}},
+				}},
+			}},
+		},
+	}
+	vmCluster.Spec.VMStorage.ReplicaCount = ptr.To(int32(2))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmCluster).Build()
+	r := &PlatformMonitoringReconciler{Client: c, Log: utils.Logger("test")}
+	ctx := context.Background()
+
+	t.Run("Test flags are reported without the estimate", func(t *testing.T) {
+		r.setRetentionPolicyStatus(ctx, cr)
+		assert.Equal(t, &qubershiporgv1.RetentionPolicyStatus{
+			RetentionFilter:    `{vm_account_id="5",vm_project_id="0"}:7d`,
+			DownsamplingPeriod: "7d:5m",
+		}, cr.Status.Storage.RetentionPolicy)
+	})
+	t.Run("Test estimate includes replication and is compared with the requested storage", func(t *testing.T) {
+		cr.Spec.Victoriametrics.RetentionPolicy.Estimate = &qubershiporgv1.VmStorageEstimate{IngestionRate: 1000}
+		r.setRetentionPolicyStatus(ctx, cr)
+		status := cr.Status.Storage.RetentionPolicy
+		// 7 days of raw samples and 23 days of samples left once per 5 minutes instead of 30 seconds
+		assert.Equal(t, "1533Mi", status.EstimatedDiskUsage.String())
+		assert.Equal(t, "767Mi", status.EstimatedDiskUsagePerNode.String())
+		assert.Equal(t, "The estimated disk usage 767Mi of a storage node exceeds the requested storage 512Mi", status.Message)
+	})
+	t.Run("Test status is removed with the retention policy", func(t *testing.T) {
+		cr.Spec.Victoriametrics.RetentionPolicy = nil
+		r.setRetentionPolicyStatus(ctx, cr)
+		assert.Nil(t, cr.Status.Storage.RetentionPolicy)
+	})
+}
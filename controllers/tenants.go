@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// mapTenantNamespace returns requests for PlatformMonitoring instances which tenants select the changed namespace,
+// so vmagent writes metrics of the namespace to its tenant without waiting for the next periodic reconciliation.
+// Only the vmagent component is reconciled. Updates are mapped for old and new labels of the namespace,
+// so namespaces which are no longer selected are handled too.
+func (r *PlatformMonitoringReconciler) mapTenantNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &qubershiporgv1.PlatformMonitoringList{}
+	if err := r.Client.List(ctx, list); err != nil {
+		r.Log.Error(err, "Failed to list PlatformMonitoring instances")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		if !selectsNamespace(&list.Items[i], obj.GetLabels()) {
+			continue
+		}
+		name := types.NamespacedName{Namespace: list.Items[i].GetNamespace(), Name: list.Items[i].GetName()}
+		r.owned.reconcileComponent(name, vmAgentComponent)
+		requests = append(requests, reconcile.Request{NamespacedName: name})
+	}
+	return requests
+}
+
+// selectsNamespace checks that one of tenants of the custom resource instance selects the namespace with labels
+func selectsNamespace(cr *qubershiporgv1.PlatformMonitoring, namespaceLabels map[string]string) bool {
+	for _, tenant := range victoriametrics.GetVmclusterTenants(cr) {
+		if tenant.NamespaceSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(tenant.NamespaceSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(namespaceLabels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantNamespacePredicate passes created and deleted namespaces and changes of their labels
+func tenantNamespacePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !equality.Semantic.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestMapTenantNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := qubershiporgv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	instance := func(name string, tenants ...qubershiporgv1.VmTenant) *qubershiporgv1.PlatformMonitoring {
+		return &qubershiporgv1.PlatformMonitoring{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring"},
+			Spec: qubershiporgv1.PlatformMonitoringSpec{Victoriametrics: &qubershiporgv1.Victoriametrics{
+				VmCluster: qubershiporgv1.VmCluster{
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+				},
+				Tenants: tenants,
+			}},
+		}
+	}
+	teamA := qubershiporgv1.VmTenant{Name: "team-a", ID: "1", NamespaceSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "a"},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(instance("platformmonitoring", teamA), instance("without-selectors", qubershiporgv1.VmTenant{Name: "team-b", ID: "2"})).
+		Build()
+	r := &PlatformMonitoringReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test"), owned: newOwnedObjects()}
+	ctx := context.Background()
+
+	t.Run("Test namespace selected by the tenant", func(t *testing.T) {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}}
+		requests := r.mapTenantNamespace(ctx, ns)
+		name := types.NamespacedName{Namespace: "monitoring", Name: "platformmonitoring"}
+		if assert.Len(t, requests, 1) {
+			assert.Equal(t, name, requests[0].NamespacedName)
+		}
+		// Only vmagent is reconciled
+		assert.Equal(t, map[string]struct{}{vmAgentComponent: {}}, r.owned.take(name))
+	})
+	t.Run("Test namespace not selected by tenants", func(t *testing.T) {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-c", Labels: map[string]string{"team": "c"}}}
+		assert.Empty(t, r.mapTenantNamespace(ctx, ns))
+	})
+	t.Run("Test changes of labels of namespaces", func(t *testing.T) {
+		old := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+		labelled := old.DeepCopy()
+		labelled.Labels = map[string]string{"team": "a"}
+		annotated := old.DeepCopy()
+		annotated.Annotations = map[string]string{"description": "Team A"}
+		p := tenantNamespacePredicate()
+		assert.True(t, p.Create(event.CreateEvent{Object: labelled}))
+		assert.True(t, p.Delete(event.DeleteEvent{Object: labelled}))
+		assert.True(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: labelled}))
+		assert.False(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: annotated}))
+	})
+}
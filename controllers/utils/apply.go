@@ -0,0 +1,455 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// FieldManager is the name of the field manager used by the operator for server-side apply
+	FieldManager = "monitoring-operator"
+	// AppliedHashAnnotation contains the hash of the last applied manifest of the object.
+	// The object is not applied again while the manifest is not changed.
+	AppliedHashAnnotation = "monitoring.qubership.org/applied-hash"
+)
+
+// legacyFieldManagers contains names of field managers which previous versions of the operator
+// used to create and update objects. Their fields are transferred to the FieldManager before the apply.
+var legacyFieldManagers = sets.New("manager")
+
+// ApplyResource creates or updates the object with server-side apply under the FieldManager.
+// Only fields which are set in the object are owned by the operator, so fields set by other controllers
+// (e.g. annotations injected by service meshes) are kept.
+// The write is skipped if the manifest was not changed and the operator still owns the same fields
+// as after the last apply, so changes of other fields (e.g. made by other controllers) don't cause writes.
+// The operator never takes fields from other field managers: if fields are managed by other controllers
+// (e.g. spec.replicas of a Deployment scaled by HPA), the conflict is reported as an event of the custom resource
+// and the object is applied again without these fields, so they are left to their managers.
+// Fields owned by legacy field managers of the operator are transferred to the FieldManager before the apply.
+func (r *ComponentReconciler) ApplyResource(cr *v1alpha1.PlatformMonitoring, o K8sResource, setRefOptional ...bool) error {
+	gvk, err := apiutil.GVKForObject(o, r.Scheme)
+	if err != nil {
+		return err
+	}
+	o.GetObjectKind().SetGroupVersionKind(gvk)
+	res := gvk.Kind
+
+	setRef := true
+	if len(setRefOptional) > 0 {
+		setRef = setRefOptional[0]
+	}
+	if setRef {
+		if err = controllerutil.SetControllerReference(cr, o, r.Scheme); err != nil {
+			if !(strings.Contains(err.Error(), "cluster-scoped resource must not have a namespace-scoped owner") ||
+				strings.Contains(err.Error(), "cross-namespace owner references are disallowed")) {
+				return err
+			}
+		}
+	}
+	// Server-side apply rejects objects with managed fields and checks resourceVersion if it is set
+	o.SetManagedFields(nil)
+	o.SetResourceVersion("")
+
+	hash, err := appliedHash(o)
+	if err != nil {
+		return err
+	}
+	annotations := o.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AppliedHashAnnotation] = hash
+	o.SetAnnotations(annotations)
+
+	// The object can be unavailable in the cache (e.g. in other namespace), in this case it is applied anyway
+	existing, err := r.existingObject(o)
+	if err != nil {
+		r.Log.V(1).Info("Failed to get current state of resource: "+err.Error(), ResourceKey, res, "name", o.GetName())
+	}
+	if existing != nil && existing.GetAnnotations()[AppliedHashAnnotation] == hash && ownedFieldsKept(existing) {
+		r.Log.V(1).Info("Skip applying unchanged resource", ResourceKey, res, "name", o.GetName())
+		if k, ok := r.Client.(objectKeeper); ok {
+			k.keep(o)
+		}
+		return nil
+	}
+
+	if existing != nil && !r.IsDryRun() {
+		if err = r.upgradeManagedFields(existing); err != nil {
+			return fmt.Errorf("failed to transfer fields of legacy field managers of %s %s: %w", res, o.GetName(), err)
+		}
+	}
+
+	err = r.Client.Patch(context.TODO(), o, client.Apply, client.FieldOwner(FieldManager))
+	if errors.IsConflict(err) {
+		conflicts := fieldConflicts(err)
+		if len(conflicts) == 0 {
+			return err
+		}
+		descriptions := make([]string, 0, len(conflicts))
+		paths := make([]string, 0, len(conflicts))
+		for _, c := range conflicts {
+			descriptions = append(descriptions, c.String())
+			paths = append(paths, c.Field)
+		}
+		var withoutConflicts *unstructured.Unstructured
+		if withoutConflicts, err = withoutFields(o, paths); err != nil {
+			return fmt.Errorf("%s %s has fields managed by other controllers: %s: %w",
+				res, o.GetName(), strings.Join(descriptions, ", "), err)
+		}
+		r.Event(cr, corev1.EventTypeWarning, "ApplyConflict",
+			fmt.Sprintf("%s %s has fields managed by other controllers, they are left to them: %s",
+				res, o.GetName(), strings.Join(descriptions, ", ")))
+		if err = r.Client.Patch(context.TODO(), withoutConflicts, client.Apply, client.FieldOwner(FieldManager)); err != nil {
+			return fmt.Errorf("failed to apply %s %s without fields managed by other controllers: %w", res, o.GetName(), err)
+		}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(withoutConflicts.Object, o)
+	}
+	if err != nil {
+		return err
+	}
+	if !r.IsDryRun() {
+		rememberOwnedFields(o)
+	}
+	r.Log.Info("Successful applying", ResourceKey, res)
+	return nil
+}
+
+// ApplyResourceLabels creates the object if it doesn't exist, otherwise applies only its labels.
+// It is used for objects which content can be changed manually after creation
+// (e.g. Secrets with configuration), so the operator must not own and overwrite it.
+func (r *ComponentReconciler) ApplyResourceLabels(cr *v1alpha1.PlatformMonitoring, o K8sResource, setRefOptional ...bool) error {
+	gvk, err := apiutil.GVKForObject(o, r.Scheme)
+	if err != nil {
+		return err
+	}
+	o.GetObjectKind().SetGroupVersionKind(gvk)
+	existing, err := r.existingObject(o)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return r.CreateResource(cr, o, setRefOptional...)
+	}
+
+	newObj, err := r.Scheme.New(gvk)
+	if err != nil {
+		return err
+	}
+	labelsOnly, ok := newObj.(K8sResource)
+	if !ok {
+		return fmt.Errorf("%T is not a K8sResource", newObj)
+	}
+	labelsOnly.SetName(o.GetName())
+	labelsOnly.SetNamespace(o.GetNamespace())
+	labelsOnly.SetLabels(o.GetLabels())
+	return r.ApplyResource(cr, labelsOnly, setRefOptional...)
+}
+
+// dryRunner is implemented by clients which don't persist changes of objects,
+// e.g. when manifests are rendered or changes are planned
+type dryRunner interface {
+	DryRun() bool
+}
+
+// IsDryRun returns true if changes made through the client are not persisted
+func IsDryRun(c client.Client) bool {
+	d, ok := c.(dryRunner)
+	return ok && d.DryRun()
+}
+
+// IsDryRun returns true if changes of objects made by the component are not persisted,
+// so the component must not wait for them or make other changes outside the client.
+func (r *ComponentReconciler) IsDryRun() bool {
+	return IsDryRun(r.Client)
+}
+
+// appliedFields contains fields owned by the FieldManager after the last apply of objects by their UIDs
+var appliedFields sync.Map
+
+// appliedEntry returns the entry of managed fields of server-side applies of the operator or nil if there is no one.
+// Changes of subresources (e.g. status) are not considered.
+func appliedEntry(o client.Object) *metav1.ManagedFieldsEntry {
+	entries := o.GetManagedFields()
+	for i := range entries {
+		if entries[i].Manager == FieldManager && entries[i].Operation == metav1.ManagedFieldsOperationApply && entries[i].Subresource == "" {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// ownedFields returns fields owned by the operator in the FieldsV1 format or an empty string
+// if the operator has never applied the object
+func ownedFields(o client.Object) string {
+	if entry := appliedEntry(o); entry != nil && entry.FieldsV1 != nil {
+		return string(entry.FieldsV1.Raw)
+	}
+	return ""
+}
+
+// rememberOwnedFields remembers fields owned by the operator after the apply of the object
+func rememberOwnedFields(o client.Object) {
+	if fields := ownedFields(o); fields != "" && o.GetUID() != "" {
+		appliedFields.Store(o.GetUID(), fields)
+	}
+}
+
+// ownedFieldsKept returns true if the operator owns the same fields of the object as after its last apply,
+// so other field managers didn't take or remove them. Objects which were not applied since the start
+// of the operator are considered as changed, so they are applied once.
+func ownedFieldsKept(o client.Object) bool {
+	fields, ok := appliedFields.Load(o.GetUID())
+	return ok && fields == ownedFields(o)
+}
+
+// OwnedFieldsChanged returns true if other field managers (e.g. manually with kubectl) took or removed fields
+// owned by the operator between the old and the new state of the object. Changes of fields which are not owned
+// by the operator (e.g. spec.replicas scaled by HPA) and applies of the operator itself are not considered.
+func OwnedFieldsChanged(oldObj, newObj client.Object) bool {
+	oldEntry, newEntry := appliedEntry(oldObj), appliedEntry(newObj)
+	switch {
+	case oldEntry == nil || newEntry == nil:
+		// Fields are lost only if the operator owned them before
+		return oldEntry != nil
+	case !oldEntry.Time.Equal(newEntry.Time):
+		// The time of the entry changes only with applies of the operator
+		return false
+	}
+	return ownedFields(oldObj) != ownedFields(newObj)
+}
+
+// upgradeManagedFields transfers fields owned by legacy field managers of the operator with update operations
+// to the FieldManager, so they don't conflict with server-side apply of the operator
+func (r *ComponentReconciler) upgradeManagedFields(o client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(o, legacyFieldManagers, FieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	return r.Client.Patch(context.TODO(), o, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// existingObject returns the current state of the object or nil if it doesn't exist
+func (r *ComponentReconciler) existingObject(o K8sResource) (client.Object, error) {
+	newObj, err := r.Scheme.New(o.GetObjectKind().GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	existing, ok := newObj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a client.Object", newObj)
+	}
+	if err = r.Client.Get(context.TODO(), client.ObjectKeyFromObject(o), existing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return existing, nil
+}
+
+// appliedHash returns the hash of the manifest without the AppliedHashAnnotation
+func appliedHash(o K8sResource) (string, error) {
+	annotations := o.GetAnnotations()
+	if _, ok := annotations[AppliedHashAnnotation]; ok {
+		copied := make(map[string]string, len(annotations))
+		for k, v := range annotations {
+			copied[k] = v
+		}
+		delete(copied, AppliedHashAnnotation)
+		o.SetAnnotations(copied)
+		defer o.SetAnnotations(annotations)
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fieldConflict is the field of the object which is managed by other field manager
+type fieldConflict struct {
+	// Field is the path of the field, e.g. .spec.template.spec.containers[name="grafana"].image
+	Field string
+	// Message describes the manager of the field, e.g. conflict with "kube-controller-manager" using apps/v1
+	Message string
+}
+
+func (c fieldConflict) String() string {
+	return c.Field + " (" + c.Message + ")"
+}
+
+// fieldConflicts returns conflicting fields with their managers from the error of server-side apply
+func fieldConflicts(err error) []fieldConflict {
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return nil
+	}
+	var conflicts []fieldConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			conflicts = append(conflicts, fieldConflict{Field: cause.Field, Message: cause.Message})
+		}
+	}
+	return conflicts
+}
+
+// withoutFields returns the copy of the object without fields with given paths of server-side apply
+func withoutFields(o K8sResource, paths []string) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if err = removeField(content, path); err != nil {
+			return nil, err
+		}
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+	return u, nil
+}
+
+// removeField removes the field by its path in the format of conflicts of server-side apply.
+// Path consists of field names (.spec), keys of associative lists ([name="grafana"]),
+// values of sets ([="value"]) and indexes of atomic lists ([0]).
+// Field names can contain dots (e.g. labels), so the longest name of existing field is used.
+func removeField(content map[string]interface{}, path string) error {
+	var current interface{} = content
+	set := func(interface{}) {}
+	var remove func()
+	for rest := path; rest != ""; {
+		switch rest[0] {
+		case '.':
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("field %s is not found", path)
+			}
+			name := ""
+			for key := range m {
+				after, found := strings.CutPrefix(rest[1:], key)
+				if found && len(key) > len(name) && (after == "" || after[0] == '.' || after[0] == '[') {
+					name = key
+				}
+			}
+			if name == "" {
+				return fmt.Errorf("field %s is not found", path)
+			}
+			current = m[name]
+			set = func(v interface{}) { m[name] = v }
+			remove = func() { delete(m, name) }
+			rest = rest[1+len(name):]
+		case '[':
+			list, ok := current.([]interface{})
+			end := closingBracket(rest)
+			if !ok || end < 0 {
+				return fmt.Errorf("field %s is not found", path)
+			}
+			i, err := listIndex(list, rest[1:end])
+			if err != nil {
+				return fmt.Errorf("field %s is not found: %w", path, err)
+			}
+			setList := set
+			current = list[i]
+			set = func(v interface{}) { list[i] = v }
+			remove = func() { setList(append(list[:i:i], list[i+1:]...)) }
+			rest = rest[end+1:]
+		default:
+			return fmt.Errorf("invalid path %s", path)
+		}
+	}
+	if remove == nil {
+		return fmt.Errorf("invalid path %s", path)
+	}
+	remove()
+	return nil
+}
+
+// closingBracket returns the index of the bracket which closes the first path element, quoted values are skipped
+func closingBracket(s string) int {
+	quoted := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// listIndex returns the index of the list item selected by the path element without brackets
+func listIndex(list []interface{}, selector string) (int, error) {
+	if value, ok := strings.CutPrefix(selector, "="); ok {
+		for i, item := range list {
+			if data, err := json.Marshal(item); err == nil && string(data) == value {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("value %s is not found", value)
+	}
+	if i, err := strconv.Atoi(selector); err == nil {
+		if i < 0 || i >= len(list) {
+			return 0, fmt.Errorf("index %d is out of range", i)
+		}
+		return i, nil
+	}
+	keys := map[string]string{}
+	quoted := false
+	start := 0
+	for i := 0; i <= len(selector); i++ {
+		switch {
+		case i == len(selector) || !quoted && selector[i] == ',':
+			name, value, found := strings.Cut(selector[start:i], "=")
+			if !found {
+				return 0, fmt.Errorf("invalid key %s", selector)
+			}
+			keys[name] = value
+			start = i + 1
+		case quoted && selector[i] == '\\':
+			i++
+		case selector[i] == '"':
+			quoted = !quoted
+		}
+	}
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matched := true
+		for name, value := range keys {
+			if data, err := json.Marshal(m[name]); err != nil || string(data) != value {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("item with key %s is not found", selector)
+}
@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestAppliedHash(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring"},
+		Data:       map[string]string{"key": "value"},
+	}
+	hash, err := appliedHash(cm)
+	assert.NoError(t, err)
+
+	cm.SetAnnotations(map[string]string{AppliedHashAnnotation: "previous"})
+	withAnnotation, err := appliedHash(cm)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, withAnnotation, "Hash should not depend on the annotation with hash")
+	assert.Equal(t, "previous", cm.GetAnnotations()[AppliedHashAnnotation], "Annotations should be restored")
+
+	cm.Data["key"] = "changed"
+	changed, err := appliedHash(cm)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+}
+
+func TestApplyResourceSkipsUnchanged(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"}}
+	manifest := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring"},
+			Data:       map[string]string{"key": "value"},
+		}
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	r := &ComponentReconciler{Scheme: scheme, Log: Logger("test")}
+
+	// Calculate hash of the object in the same way as ApplyResource does
+	applied := manifest()
+	applied.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	assert.NoError(t, controllerutil.SetControllerReference(cr, applied, r.Scheme))
+	hash, err := appliedHash(applied)
+	assert.NoError(t, err)
+	applied.SetAnnotations(map[string]string{AppliedHashAnnotation: hash})
+	applied.SetUID("0b6d6a2c-4f5e-4a8e-9d8f-1f2e3d4c5b6a")
+	appliedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	owned := &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}}}`)}
+	applied.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, Time: &appliedAt, FieldsV1: owned},
+		// Other managers change fields which are not owned by the operator
+		{Manager: "kubectl-label", Operation: metav1.ManagedFieldsOperationUpdate, Time: ptr.To(metav1.Now()),
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:team":{}}}}`)}},
+	})
+	rememberOwnedFields(applied)
+	defer appliedFields.Delete(applied.GetUID())
+
+	// Fake client doesn't support server-side apply, so ApplyResource fails if it tries to write the object
+	r.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(applied).Build()
+	assert.NoError(t, r.ApplyResource(cr, manifest()))
+
+	// The field owned by the operator is taken by another manager
+	applied.SetResourceVersion("")
+	applied.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, Time: &appliedAt, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{}`)}},
+		{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: ptr.To(metav1.Now()), FieldsV1: owned},
+	})
+	r.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(applied).Build()
+	assert.Error(t, r.ApplyResource(cr, manifest()), "the object should be applied again")
+}
+
+func TestOwnedFieldsChanged(t *testing.T) {
+	applied := metav1.NewTime(time.Now().Add(-time.Hour))
+	reapplied := metav1.NewTime(time.Now())
+	fields := func(raw string) *metav1.FieldsV1 { return &metav1.FieldsV1{Raw: []byte(raw)} }
+	configMap := func(entries ...metav1.ManagedFieldsEntry) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ManagedFields: entries}}
+	}
+	operator := func(time *metav1.Time, raw string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, Time: time, FieldsV1: fields(raw)}
+	}
+	const owned = `{"f:data":{"f:a":{},"f:b":{}}}`
+	other := metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &reapplied,
+		FieldsV1: fields(`{"f:data":{"f:c":{}}}`)}
+
+	assert.False(t, OwnedFieldsChanged(configMap(), configMap(other)), "object which was never applied")
+	assert.False(t, OwnedFieldsChanged(configMap(operator(&applied, owned)), configMap(operator(&applied, owned), other)),
+		"change of fields which are not owned by the operator")
+	assert.True(t, OwnedFieldsChanged(configMap(operator(&applied, owned)), configMap(operator(&applied, `{"f:data":{"f:a":{}}}`), other)),
+		"field taken or removed by another manager")
+	assert.True(t, OwnedFieldsChanged(configMap(operator(&applied, owned)), configMap(other)), "all fields taken by other managers")
+	assert.False(t, OwnedFieldsChanged(configMap(operator(&applied, owned)), configMap(operator(&reapplied, `{"f:data":{"f:a":{}}}`))),
+		"apply of the operator")
+}
+
+func TestFieldConflicts(t *testing.T) {
+	err := &errors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusConflict,
+		Reason: metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{
+			{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kube-controller-manager" using apps/v1`, Field: ".spec.replicas"},
+			{Type: metav1.CauseTypeFieldValueInvalid, Message: "invalid value", Field: ".spec.selector"},
+		}},
+	}}
+	conflicts := fieldConflicts(err)
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, `.spec.replicas (conflict with "kube-controller-manager" using apps/v1)`, conflicts[0].String())
+	assert.Empty(t, fieldConflicts(errors.NewConflict(schema.GroupResource{}, "grafana", nil)))
+}
+
+func TestRemoveField(t *testing.T) {
+	content := func() map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{"app.kubernetes.io/name": "grafana", "app": "grafana"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"containers": []interface{}{
+					map[string]interface{}{"name": "grafana", "image": "grafana:1", "ports": []interface{}{
+						map[string]interface{}{"containerPort": int64(3000), "protocol": "TCP"},
+					}},
+					map[string]interface{}{"name": "sidecar", "image": "sidecar:1"},
+				},
+				"finalizers": []interface{}{"a", "b"},
+			},
+		}
+	}
+
+	c := content()
+	assert.NoError(t, removeField(c, ".spec.replicas"))
+	assert.NotContains(t, c["spec"], "replicas")
+
+	c = content()
+	assert.NoError(t, removeField(c, ".metadata.labels.app.kubernetes.io/name"))
+	assert.Equal(t, map[string]interface{}{"app": "grafana"}, c["metadata"].(map[string]interface{})["labels"])
+
+	c = content()
+	assert.NoError(t, removeField(c, `.spec.containers[name="sidecar"].image`))
+	assert.Equal(t, map[string]interface{}{"name": "sidecar"}, c["spec"].(map[string]interface{})["containers"].([]interface{})[1])
+
+	c = content()
+	assert.NoError(t, removeField(c, `.spec.containers[name="grafana"].ports[containerPort=3000,protocol="TCP"]`))
+	assert.Empty(t, c["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})["ports"])
+
+	c = content()
+	assert.NoError(t, removeField(c, `.spec.finalizers[="a"]`))
+	assert.Equal(t, []interface{}{"b"}, c["spec"].(map[string]interface{})["finalizers"])
+
+	assert.Error(t, removeField(content(), ".spec.paused"))
+	assert.Error(t, removeField(content(), `.spec.containers[name="missing"].image`))
+}
+
+func TestApplyResourceLeavesFieldsOfOtherManagers(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"}}
+	manifest := func(image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "grafana", Image: image}},
+				}},
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	// HPA scaled the Deployment and owns spec.replicas
+	scaled := manifest("grafana:1")
+	scaled.Spec.Replicas = ptr.To[int32](3)
+	scaled.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "scale"},
+	})
+
+	// Fake client doesn't support server-side apply, so it is emulated: the apply without force fails
+	// if it changes replicas, otherwise fields of the applied object are merged into the stored object
+	var forced bool
+	c := interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(scaled).Build(), interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			for _, opt := range opts {
+				forced = forced || opt == client.ForceOwnership
+			}
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				return err
+			}
+			if replicas, found, _ := unstructured.NestedInt64(content, "spec", "replicas"); found && replicas != 3 {
+				return &errors.StatusError{ErrStatus: metav1.Status{
+					Status: metav1.StatusFailure,
+					Code:   http.StatusConflict,
+					Reason: metav1.StatusReasonConflict,
+					Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{
+						Type:    metav1.CauseTypeFieldManagerConflict,
+						Message: `conflict with "kube-controller-manager" using apps/v1`,
+						Field:   ".spec.replicas",
+					}}},
+				}}
+			}
+			data, err := json.Marshal(content)
+			if err != nil {
+				return err
+			}
+			return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+		},
+	})
+	recorder := record.NewFakeRecorder(10)
+	r := &ComponentReconciler{Client: c, Scheme: scheme, Recorder: recorder, Log: Logger("test")}
+
+	// Reconcile of the new version of the manifest
+	assert.NoError(t, r.ApplyResource(cr, manifest("grafana:2")))
+	assert.False(t, forced, "Ownership of fields should not be forced")
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "ApplyConflict")
+
+	stored := &appsv1.Deployment{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(scaled), stored))
+	assert.Equal(t, int32(3), *stored.Spec.Replicas, "Replicas managed by HPA should survive the reconcile")
+	assert.Equal(t, "grafana:2", stored.Spec.Template.Spec.Containers[0].Image)
+}
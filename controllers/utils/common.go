@@ -119,6 +119,14 @@ func (r *ComponentReconciler) GetResource(o K8sResource) error {
 	return nil
 }
 
+// WatchResource records the object which is read by the component but not managed by it,
+// e.g. the Secret with credentials. Changes of the object trigger reconciliation of the component.
+func (r *ComponentReconciler) WatchResource(o client.Object) {
+	if c, ok := r.Client.(objectReferrer); ok {
+		c.reference(o)
+	}
+}
+
 func (r *ComponentReconciler) UpdateResource(o K8sResource) error {
 	// Update object
 	if err := r.Client.Update(context.TODO(), o); err != nil {
@@ -137,6 +145,14 @@ func (r *ComponentReconciler) DeleteResource(o K8sResource) error {
 	return nil
 }
 
+// Event creates an event for the custom resource if the Recorder is set
+func (r *ComponentReconciler) Event(cr *v1alpha1.PlatformMonitoring, eventType, reason, message string) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Event(cr, eventType, reason, message)
+}
+
 // GetServerVersion allows to recognize OpenShift v4.5 or higher.
 func (r *ComponentReconciler) IsOpenShiftV4() (bool, error) {
 	isOpenShift := r.HasRouteApi()
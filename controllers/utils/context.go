@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// contextClient bounds all requests of a component to the context of its reconciliation,
+// so requests are cancelled when the component times out, even if they are made with context.TODO()
+type contextClient struct {
+	client.Client
+	ctx context.Context
+}
+
+// WithContext returns the client which cancels requests when the context is done.
+// Requests are also cancelled by their own contexts.
+func WithContext(ctx context.Context, c client.Client) client.Client {
+	return &contextClient{Client: c, ctx: ctx}
+}
+
+// bound returns the context of the request which is also cancelled when the context of the client is done
+func bound(parent, ctx context.Context) (context.Context, context.CancelFunc) {
+	bounded, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(parent, func() { cancel(context.Cause(parent)) })
+	if parent.Err() != nil {
+		cancel(context.Cause(parent))
+	}
+	return bounded, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+func (c *contextClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *contextClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *contextClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *contextClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *contextClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *contextClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *contextClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *contextClient) Status() client.SubResourceWriter {
+	return &contextSubResourceClient{writer: c.Client.Status(), ctx: c.ctx}
+}
+
+func (c *contextClient) SubResource(subResource string) client.SubResourceClient {
+	sc := c.Client.SubResource(subResource)
+	return &contextSubResourceClient{writer: sc, reader: sc, ctx: c.ctx}
+}
+
+// DryRun returns true if the wrapped client doesn't persist changes
+func (c *contextClient) DryRun() bool {
+	return IsDryRun(c.Client)
+}
+
+// contextSubResourceClient bounds requests to subresources to the context of the reconciliation of a component
+type contextSubResourceClient struct {
+	writer client.SubResourceWriter
+	// reader is nil for the status writer
+	reader client.SubResourceReader
+	ctx    context.Context
+}
+
+func (c *contextSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.reader.Get(ctx, obj, subResource, opts...)
+}
+
+func (c *contextSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.writer.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *contextSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.writer.Update(ctx, obj, opts...)
+}
+
+func (c *contextSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	ctx, cancel := bound(c.ctx, ctx)
+	defer cancel()
+	return c.writer.Patch(ctx, obj, patch, opts...)
+}
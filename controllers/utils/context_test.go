@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestWithContext(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	var requestCtx context.Context
+	c := interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build(), interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			requestCtx = ctx
+			return ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bounded := WithContext(ctx, c)
+	key := client.ObjectKey{Namespace: "monitoring", Name: "grafana"}
+	assert.NoError(t, client.IgnoreNotFound(bounded.Get(context.TODO(), key, &corev1.ConfigMap{})))
+	assert.ErrorIs(t, requestCtx.Err(), context.Canceled, "Context of finished request should be released")
+
+	cancel()
+	assert.ErrorIs(t, bounded.Get(context.TODO(), key, &corev1.ConfigMap{}), context.Canceled,
+		"Requests should be cancelled with the context of the client")
+	assert.False(t, IsDryRun(bounded))
+}
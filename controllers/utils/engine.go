@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+)
+
+var (
+	// ErrComponentTimeout is returned in ComponentResult if the component exceeded its timeout
+	ErrComponentTimeout = errors.New("component reconciliation timed out")
+	// ErrComponentPanic is returned in ComponentResult if the component panicked
+	ErrComponentPanic = errors.New("component reconciliation panicked")
+)
+
+// running contains channels which are closed when goroutines of components exit.
+// Goroutines of components which timed out keep running until they notice the cancellation of their context,
+// so the next reconciliation of the component waits for them and doesn't change the same objects concurrently.
+var running sync.Map
+
+// componentError describes a failure of the Engine to reconcile a component.
+// It can be checked with errors.Is against ErrComponentTimeout and ErrComponentPanic.
+type componentError struct {
+	cause error
+	msg   string
+}
+
+func (e *componentError) Error() string { return e.msg }
+
+func (e *componentError) Unwrap() error { return e.cause }
+
+// ComponentRunFunc reconciles a single component of the monitoring stack.
+type ComponentRunFunc func(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error
+
+// Component describes a component of the monitoring stack that can be reconciled by the Engine.
+type Component struct {
+	// Name is a unique name of the component, e.g. "prometheus-operator"
+	Name string
+	// DependsOn contains names of components which must be reconciled successfully
+	// before this component can be reconciled.
+	DependsOn []string
+	// Timeout overrides the default timeout of the Engine for this component.
+	Timeout time.Duration
+	// Run reconciles the component
+	Run ComponentRunFunc
+}
+
+// ComponentResult contains result of the reconciliation of a single component.
+type ComponentResult struct {
+	// Name of the component
+	Name string
+	// Err contains an error if the component reconciliation failed or timed out
+	Err error
+	// SkippedBy contains the name of the dependency which failed,
+	// if the component was not reconciled because of it
+	SkippedBy string
+	// Duration of the component reconciliation
+	Duration time.Duration
+}
+
+// Skipped returns true if the component was not reconciled because one of its dependencies failed.
+func (r ComponentResult) Skipped() bool {
+	return r.SkippedBy != ""
+}
+
+// Failed returns true if the component reconciliation failed or was skipped.
+func (r ComponentResult) Failed() bool {
+	return r.Err != nil || r.Skipped()
+}
+
+// SelectComponents returns only components with given names.
+// Dependencies on components which are not selected are removed, because such components
+// are not reconciled and considered as successful.
+func SelectComponents(components []Component, names map[string]struct{}) []Component {
+	var selected []Component
+	for _, c := range components {
+		if _, ok := names[c.Name]; !ok {
+			continue
+		}
+		var deps []string
+		for _, dep := range c.DependsOn {
+			if _, ok := names[dep]; ok {
+				deps = append(deps, dep)
+			}
+		}
+		c.DependsOn = deps
+		selected = append(selected, c)
+	}
+	return selected
+}
+
+// Engine reconciles components in dependency order.
+// Components which do not depend on each other are reconciled concurrently.
+type Engine struct {
+	components     []Component
+	defaultTimeout time.Duration
+	log            logr.Logger
+}
+
+// NewEngine creates an instance of Engine for given components.
+// Returns an error if a component has duplicated name, depends on an unknown component
+// or if there is a dependency cycle between components.
+func NewEngine(log logr.Logger, defaultTimeout time.Duration, components ...Component) (*Engine, error) {
+	byName := make(map[string]Component, len(components))
+	for _, c := range components {
+		if _, ok := byName[c.Name]; ok {
+			return nil, fmt.Errorf("component %s is declared more than once", c.Name)
+		}
+		byName[c.Name] = c
+	}
+	for _, c := range components {
+		for _, dep := range c.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("component %s depends on unknown component %s", c.Name, dep)
+			}
+		}
+	}
+	// Check that there are no cycles with depth-first search
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(components))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case inProgress:
+			return fmt.Errorf("dependency cycle detected on component %s", name)
+		case done:
+			return nil
+		}
+		state[name] = inProgress
+		for _, dep := range byName[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		return nil
+	}
+	for _, c := range components {
+		if err := visit(c.Name); err != nil {
+			return nil, err
+		}
+	}
+	return &Engine{
+		components:     components,
+		defaultTimeout: defaultTimeout,
+		log:            log,
+	}, nil
+}
+
+// Run reconciles all components and returns their results in the declaration order.
+// Each component gets its own copy of the custom resource, so components can't affect each other
+// by changing the spec in memory.
+// A component is skipped if any of its dependencies failed or was skipped.
+// A component which exceeds its timeout is reported as failed and its context is cancelled.
+// Run doesn't wait for it to finish, but the next run of the component waits until it exits.
+func (e *Engine) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) []ComponentResult {
+	finished := make(map[string]chan struct{}, len(e.components))
+	for _, c := range e.components {
+		finished[c.Name] = make(chan struct{})
+	}
+
+	var mu sync.Mutex
+	results := make(map[string]ComponentResult, len(e.components))
+
+	var wg sync.WaitGroup
+	for _, c := range e.components {
+		wg.Add(1)
+		go func(c Component) {
+			defer wg.Done()
+			defer close(finished[c.Name])
+
+			for _, dep := range c.DependsOn {
+				<-finished[dep]
+			}
+			mu.Lock()
+			var failedDep string
+			for _, dep := range c.DependsOn {
+				if results[dep].Failed() {
+					failedDep = dep
+					break
+				}
+			}
+			mu.Unlock()
+
+			var result ComponentResult
+			if failedDep != "" {
+				e.log.Info("Component skipped because of failed dependency", ComponentKey, c.Name, "dependency", failedDep)
+				result = ComponentResult{Name: c.Name, SkippedBy: failedDep}
+			} else {
+				result = e.runComponent(ctx, c, cr.DeepCopy())
+			}
+
+			mu.Lock()
+			results[c.Name] = result
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	ordered := make([]ComponentResult, 0, len(e.components))
+	for _, c := range e.components {
+		ordered = append(ordered, results[c.Name])
+	}
+	return ordered
+}
+
+// runComponent reconciles a single component with its timeout.
+// The goroutine of the component is started only after the goroutine of the previous run of the component exits.
+func (e *Engine) runComponent(ctx context.Context, c Component, cr *v1alpha1.PlatformMonitoring) ComponentResult {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = e.defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	key := cr.GetNamespace() + "/" + cr.GetName() + "/" + c.Name
+	if previous, ok := running.Load(key); ok {
+		select {
+		case <-previous.(chan struct{}):
+		case <-ctx.Done():
+			return ComponentResult{Name: c.Name, Duration: time.Since(start), Err: &componentError{
+				cause: ErrComponentTimeout,
+				msg:   fmt.Sprintf("previous reconciliation of component %s did not finish in %s", c.Name, timeout.Round(time.Second).String()),
+			}}
+		}
+	}
+	done := make(chan struct{})
+	running.Store(key, done)
+
+	// Buffered channel allows the goroutine to exit even if nobody waits for it after timeout
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			running.CompareAndDelete(key, done)
+			close(done)
+		}()
+		defer func() {
+			if p := recover(); p != nil {
+				errCh <- &componentError{cause: ErrComponentPanic, msg: fmt.Sprintf("reconciliation of component %s panicked: %v", c.Name, p)}
+			}
+		}()
+		errCh <- c.Run(ctx, cr)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = &componentError{cause: ErrComponentTimeout, msg: fmt.Sprintf("reconciliation of component %s did not finish in %s", c.Name, timeout.Round(time.Second).String())}
+	}
+	return ComponentResult{Name: c.Name, Err: err, Duration: time.Since(start)}
+}
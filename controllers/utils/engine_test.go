@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var engineCR = &v1alpha1.PlatformMonitoring{
+	ObjectMeta: metav1.ObjectMeta{
+		Namespace: "monitoring",
+	},
+}
+
+func succeed(context.Context, *v1alpha1.PlatformMonitoring) error { return nil }
+
+func TestNewEngine(t *testing.T) {
+	t.Run("Test unknown dependency", func(t *testing.T) {
+		_, err := NewEngine(Logger("test"), time.Second,
+			Component{Name: "a", DependsOn: []string{"b"}, Run: succeed})
+		assert.Error(t, err)
+	})
+	t.Run("Test duplicated component", func(t *testing.T) {
+		_, err := NewEngine(Logger("test"), time.Second,
+			Component{Name: "a", Run: succeed},
+			Component{Name: "a", Run: succeed})
+		assert.Error(t, err)
+	})
+	t.Run("Test dependency cycle", func(t *testing.T) {
+		_, err := NewEngine(Logger("test"), time.Second,
+			Component{Name: "a", DependsOn: []string{"c"}, Run: succeed},
+			Component{Name: "b", DependsOn: []string{"a"}, Run: succeed},
+			Component{Name: "c", DependsOn: []string{"b"}, Run: succeed})
+		assert.Error(t, err)
+	})
+}
+
+func TestEngineRun(t *testing.T) {
+	t.Run("Test dependency order", func(t *testing.T) {
+		var mu sync.Mutex
+		var order []string
+		record := func(name string) ComponentRunFunc {
+			return func(context.Context, *v1alpha1.PlatformMonitoring) error {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, name)
+				return nil
+			}
+		}
+		engine, err := NewEngine(Logger("test"), time.Second,
+			Component{Name: "grafana", DependsOn: []string{"grafana-operator"}, Run: record("grafana")},
+			Component{Name: "grafana-operator", DependsOn: []string{"prometheus-operator"}, Run: record("grafana-operator")},
+			Component{Name: "prometheus-operator", Run: record("prometheus-operator")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := engine.Run(context.Background(), engineCR)
+		assert.Equal(t, []string{"prometheus-operator", "grafana-operator", "grafana"}, order)
+		assert.Equal(t, "grafana", results[0].Name, "Results should be in declaration order")
+		for _, r := range results {
+			assert.False(t, r.Failed())
+		}
+	})
+	t.Run("Test independent components run concurrently", func(t *testing.T) {
+		started := make(chan struct{})
+		engine, err := NewEngine(Logger("test"), time.Second,
+			Component{Name: "a", Run: func(ctx context.Context, _ *v1alpha1.PlatformMonitoring) error {
+				// Wait until "b" is started, it would time out if components were run sequentially
+				select {
+				case <-started:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}},
+			Component{Name: "b", Run: func(context.Context, *v1alpha1.PlatformMonitoring) error {
+				close(started)
+				return nil
+			}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range engine.Run(context.Background(), engineCR) {
+			assert.NoError(t, r.Err)
+		}
+	})
+	t.Run("Test skipped dependents of failed component", func(t *testing.T) {
+		engine, err := NewEngine(Logger("test"), time.Second,
+			Component{Name: "vmoperator", Run: func(context.Context, *v1alpha1.PlatformMonitoring) error {
+				return errors.New("failed")
+			}},
+			Component{Name: "vmsingle", DependsOn: []string{"vmoperator"}, Run: succeed},
+			Component{Name: "vmagent", DependsOn: []string{"vmsingle"}, Run: succeed},
+			Component{Name: "grafana", Run: succeed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := engine.Run(context.Background(), engineCR)
+		assert.Error(t, results[0].Err)
+		assert.Equal(t, "vmoperator", results[1].SkippedBy)
+		assert.Equal(t, "vmsingle", results[2].SkippedBy)
+		assert.False(t, results[3].Failed())
+	})
+	t.Run("Test component timeout", func(t *testing.T) {
+		engine, err := NewEngine(Logger("test"), time.Hour,
+			Component{Name: "grafana", Timeout: 10 * time.Millisecond, Run: func(ctx context.Context, _ *v1alpha1.PlatformMonitoring) error {
+				time.Sleep(time.Second)
+				return nil
+			}},
+			Component{Name: "dashboards", DependsOn: []string{"grafana"}, Run: succeed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := engine.Run(context.Background(), engineCR)
+		assert.ErrorIs(t, results[0].Err, ErrComponentTimeout)
+		assert.True(t, results[1].Skipped())
+	})
+	t.Run("Test timed out component blocks its next run", func(t *testing.T) {
+		cr := engineCR.DeepCopy()
+		cr.Name = "blocked"
+		release := make(chan struct{})
+		var runs int32
+		var mu sync.Mutex
+		run := func(context.Context, *v1alpha1.PlatformMonitoring) error {
+			mu.Lock()
+			runs++
+			mu.Unlock()
+			// The component ignores the cancellation of its context
+			<-release
+			return nil
+		}
+		component := Component{Name: "grafana", Timeout: 20 * time.Millisecond, Run: run}
+		engine, err := NewEngine(Logger("test"), time.Hour, component)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.ErrorIs(t, engine.Run(context.Background(), cr)[0].Err, ErrComponentTimeout)
+
+		results := engine.Run(context.Background(), cr)
+		assert.ErrorIs(t, results[0].Err, ErrComponentTimeout)
+		assert.Contains(t, results[0].Err.Error(), "previous reconciliation")
+		mu.Lock()
+		assert.Equal(t, int32(1), runs, "Component should not be started while its previous run is in progress")
+		mu.Unlock()
+
+		close(release)
+		assert.NoError(t, engine.Run(context.Background(), cr)[0].Err)
+		mu.Lock()
+		assert.Equal(t, int32(2), runs)
+		mu.Unlock()
+	})
+	t.Run("Test components get own copy of custom resource", func(t *testing.T) {
+		cr := engineCR.DeepCopy()
+		cr.Spec.GrafanaDashboards = &v1alpha1.GrafanaDashboards{}
+		engine, err := NewEngine(Logger("test"), time.Second,
+			Component{Name: "grafana-operator", Run: func(_ context.Context, cr *v1alpha1.PlatformMonitoring) error {
+				cr.Spec.GrafanaDashboards.List = append(cr.Spec.GrafanaDashboards.List, "home-dashboard")
+				return nil
+			}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		engine.Run(context.Background(), cr)
+		assert.Empty(t, cr.Spec.GrafanaDashboards.List)
+	})
+}
+
+func TestSelectComponents(t *testing.T) {
+	components := []Component{
+		{Name: "vmoperator", Run: succeed},
+		{Name: "vmsingle", DependsOn: []string{"vmoperator"}, Run: succeed},
+		{Name: "vmagent", DependsOn: []string{"vmoperator", "vmsingle"}, Run: succeed},
+	}
+	selected := SelectComponents(components, map[string]struct{}{"vmagent": {}, "vmsingle": {}})
+	assert.Len(t, selected, 2)
+	assert.Equal(t, "vmsingle", selected[0].Name)
+	assert.Empty(t, selected[0].DependsOn)
+	assert.Equal(t, "vmagent", selected[1].Name)
+	assert.Equal(t, []string{"vmsingle"}, selected[1].DependsOn)
+	assert.Equal(t, []string{"vmoperator", "vmsingle"}, components[2].DependsOn, "Original components should not be changed")
+
+	_, err := NewEngine(Logger("test"), time.Minute, selected...)
+	assert.NoError(t, err)
+}
@@ -1,13 +1,20 @@
 package utils
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 var (
 	_defaultEnvValues = map[string]string{
-		"RECONCILIATION_INTERVAL": "60",
+		"RECONCILIATION_INTERVAL":          "600",
+		"COMPONENT_RECONCILIATION_TIMEOUT": "600",
 	}
 )
 
+// DefaultWatchNamespace is the namespace watched by the operator if WATCH_NAMESPACE is not set
+const DefaultWatchNamespace = "monitoring"
+
 func GetEnvWithDefaultValue(key string) string {
 	value := os.Getenv(key)
 	if len(value) == 0 {
@@ -15,3 +22,30 @@ func GetEnvWithDefaultValue(key string) string {
 	}
 	return value
 }
+
+// GetDefaultEnvValue returns the default value of the environment variable
+func GetDefaultEnvValue(key string) string {
+	return _defaultEnvValues[key]
+}
+
+// WatchNamespaces returns namespaces with PlatformMonitoring custom resources handled by the operator
+// from the comma-separated WATCH_NAMESPACE environment variable.
+// Returns nil if the variable is set to an empty value, that means all namespaces are watched.
+func WatchNamespaces() []string {
+	value, found := os.LookupEnv("WATCH_NAMESPACE")
+	if !found {
+		return []string{DefaultWatchNamespace}
+	}
+	return ParseNamespaces(value)
+}
+
+// ParseNamespaces splits the comma-separated list of namespaces, empty items are skipped
+func ParseNamespaces(value string) []string {
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
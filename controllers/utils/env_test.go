@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	assert.Nil(t, ParseNamespaces(""))
+	assert.Equal(t, []string{"monitoring"}, ParseNamespaces("monitoring"))
+	assert.Equal(t, []string{"team-a", "team-b"}, ParseNamespaces(" team-a, ,team-b,"))
+}
+
+func TestWatchNamespaces(t *testing.T) {
+	t.Setenv("WATCH_NAMESPACE", "team-a,team-b")
+	assert.Equal(t, []string{"team-a", "team-b"}, WatchNamespaces())
+	t.Setenv("WATCH_NAMESPACE", "")
+	assert.Nil(t, WatchNamespaces())
+}
@@ -12,6 +12,9 @@ import (
 var (
 	ComponentKey = "cmp"
 	ResourceKey  = "res"
+
+	// LogOutputPath is the path where logs are written, e.g. stderr if stdout is used for other output
+	LogOutputPath = "stdout"
 )
 
 func Logger(name string) logr.Logger {
@@ -29,7 +32,7 @@ func Logger(name string) logr.Logger {
 				encoder.AppendString(time.Format("2006-01-02T15:04:05.999"))
 			},
 		},
-		OutputPaths:      []string{"stdout"},
+		OutputPaths:      []string{LogOutputPath},
 		ErrorOutputPaths: []string{"stderr"},
 	}
 
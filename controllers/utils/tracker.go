@@ -0,0 +1,325 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ResourceTracker records objects which are created, updated or deleted by components
+// and reports these changes as events of the custom resource.
+// Cluster-scoped and cross-namespace objects can't have an owner reference to PlatformMonitoring,
+// so they are not removed by the garbage collector and have to be deleted by the operator.
+type ResourceTracker struct {
+	cr       *v1alpha1.PlatformMonitoring
+	recorder record.EventRecorder
+
+	mu sync.Mutex
+	// touched contains created or updated cluster-scoped and cross-namespace objects
+	// of each component in the order of calls
+	touched map[string][]v1alpha1.ManagedResource
+	// deleted contains keys of objects deleted by components
+	deleted map[v1alpha1.ManagedResource]struct{}
+	// managed contains keys of all objects created, updated or kept unchanged by each component
+	managed map[string]map[v1alpha1.ManagedResource]struct{}
+	// referenced contains keys of objects which are read by each component but not managed by it
+	referenced map[string]map[v1alpha1.ManagedResource]struct{}
+}
+
+// NewResourceTracker creates an instance of ResourceTracker for the custom resource.
+// Events are not created if the recorder is nil.
+func NewResourceTracker(cr *v1alpha1.PlatformMonitoring, recorder record.EventRecorder) *ResourceTracker {
+	return &ResourceTracker{
+		cr:       cr,
+		recorder: recorder,
+		touched:  map[string][]v1alpha1.ManagedResource{},
+		deleted:  map[v1alpha1.ManagedResource]struct{}{},
+		managed:  map[string]map[v1alpha1.ManagedResource]struct{}{},
+
+		referenced: map[string]map[v1alpha1.ManagedResource]struct{}{},
+	}
+}
+
+// Client returns the client which records objects of the component in the tracker.
+func (t *ResourceTracker) Client(c client.Client, component string) client.Client {
+	return &trackingClient{Client: c, tracker: t, component: component}
+}
+
+// Managed returns the number of objects which the component created, updated or kept unchanged.
+func (t *ResourceTracker) Managed(component string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.managed[component])
+}
+
+// ManagedObjects returns references to objects which the component created, updated or kept unchanged.
+func (t *ResourceTracker) ManagedObjects(component string) []v1alpha1.ManagedResource {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	objects := make([]v1alpha1.ManagedResource, 0, len(t.managed[component]))
+	for key := range t.managed[component] {
+		key.Component = component
+		objects = append(objects, key)
+	}
+	return objects
+}
+
+// ReferencedObjects returns references to objects which the component reads but doesn't manage,
+// e.g. Secrets with credentials.
+func (t *ResourceTracker) ReferencedObjects(component string) []v1alpha1.ManagedResource {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	objects := make([]v1alpha1.ManagedResource, 0, len(t.referenced[component]))
+	for key := range t.referenced[component] {
+		key.Component = component
+		objects = append(objects, key)
+	}
+	return objects
+}
+
+// Merge returns managed resources with changes recorded by the tracker.
+// Objects deleted by components are removed from the list. New objects are appended in the order
+// of given components, so the list can be processed in reverse order to delete dependent objects first.
+func (t *ResourceTracker) Merge(existing []v1alpha1.ManagedResource, components []string) []v1alpha1.ManagedResource {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := map[v1alpha1.ManagedResource]struct{}{}
+	var merged []v1alpha1.ManagedResource
+	add := func(res v1alpha1.ManagedResource) {
+		key := resourceKey(res)
+		if _, ok := t.deleted[key]; ok {
+			return
+		}
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		merged = append(merged, res)
+	}
+	for _, res := range existing {
+		add(res)
+	}
+	for _, component := range components {
+		for _, res := range t.touched[component] {
+			add(res)
+		}
+	}
+	return merged
+}
+
+// record adds the object to the tracker
+func (t *ResourceTracker) record(c client.Client, component string, obj client.Object) {
+	res, external, ok := t.managedResource(c, component, obj)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := resourceKey(res)
+	delete(t.deleted, key)
+	if t.managed[component] == nil {
+		t.managed[component] = map[v1alpha1.ManagedResource]struct{}{}
+	}
+	t.managed[component][key] = struct{}{}
+	if external {
+		t.touched[component] = append(t.touched[component], res)
+	}
+}
+
+// reference adds the object read by the component to the tracker
+func (t *ResourceTracker) reference(c client.Client, component string, obj client.Object) {
+	res, _, ok := t.managedResource(c, component, obj)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.referenced[component] == nil {
+		t.referenced[component] = map[v1alpha1.ManagedResource]struct{}{}
+	}
+	t.referenced[component][resourceKey(res)] = struct{}{}
+}
+
+// forget marks the object as deleted
+func (t *ResourceTracker) forget(c client.Client, component string, obj client.Object) {
+	res, _, ok := t.managedResource(c, component, obj)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := resourceKey(res)
+	t.deleted[key] = struct{}{}
+	delete(t.managed[component], key)
+}
+
+// event creates an event about the change of the object for the custom resource
+func (t *ResourceTracker) event(c client.Client, component string, obj client.Object, eventType, reason, message string) {
+	if t.recorder == nil || t.cr == nil {
+		return
+	}
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+	t.recorder.Event(t.cr, eventType, reason, fmt.Sprintf("%s: %s %s %s", component, kind, name, message))
+}
+
+// managedResource returns the reference to the object.
+// The second value is true if the object is cluster-scoped or placed in another namespace than the custom resource.
+func (t *ResourceTracker) managedResource(c client.Client, component string, obj client.Object) (v1alpha1.ManagedResource, bool, bool) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return v1alpha1.ManagedResource{}, false, false
+	}
+	namespaced, err := c.IsObjectNamespaced(obj)
+	if err != nil {
+		return v1alpha1.ManagedResource{}, false, false
+	}
+	res := v1alpha1.ManagedResource{
+		Component:  component,
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       obj.GetName(),
+	}
+	if namespaced {
+		res.Namespace = obj.GetNamespace()
+	}
+	return res, !namespaced || obj.GetNamespace() != t.cr.GetNamespace(), true
+}
+
+// resourceKey returns the key to compare references to the same object created by different components
+func resourceKey(res v1alpha1.ManagedResource) v1alpha1.ManagedResource {
+	res.Component = ""
+	return res
+}
+
+// objectKeeper is implemented by clients which have to know about objects
+// that are not written because they are not changed
+type objectKeeper interface {
+	keep(obj client.Object)
+}
+
+// objectReferrer is implemented by clients which have to know about objects
+// that are read by components, so changes of these objects trigger reconciliation
+type objectReferrer interface {
+	reference(obj client.Object)
+}
+
+// trackingClient records objects changed through the client in the ResourceTracker
+type trackingClient struct {
+	client.Client
+	tracker   *ResourceTracker
+	component string
+}
+
+func (c *trackingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		c.tracker.event(c.Client, c.component, obj, corev1.EventTypeWarning, "CreateFailed", "failed to create: "+err.Error())
+		return err
+	}
+	c.tracker.record(c.Client, c.component, obj)
+	c.tracker.event(c.Client, c.component, obj, corev1.EventTypeNormal, "Created", "created")
+	return nil
+}
+
+func (c *trackingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		c.tracker.event(c.Client, c.component, obj, corev1.EventTypeWarning, "UpdateFailed", "failed to update: "+err.Error())
+		return err
+	}
+	c.tracker.record(c.Client, c.component, obj)
+	c.tracker.event(c.Client, c.component, obj, corev1.EventTypeNormal, "Updated", "updated")
+	return nil
+}
+
+func (c *trackingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	apply := patch.Type() == types.ApplyPatchType
+	var exists bool
+	var resourceVersion string
+	if apply {
+		// Server-side apply creates the object if it doesn't exist
+		exists, resourceVersion = c.current(ctx, obj)
+	}
+	if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		// Conflicts of server-side apply are reported by ApplyResource, which applies the object without conflicting fields
+		switch {
+		case !apply:
+			c.tracker.event(c.Client, c.component, obj, corev1.EventTypeWarning, "UpdateFailed", "failed to update: "+err.Error())
+		case !errors.IsConflict(err):
+			c.tracker.event(c.Client, c.component, obj, corev1.EventTypeWarning, "ApplyFailed", "failed to apply: "+err.Error())
+		}
+		return err
+	}
+	c.tracker.record(c.Client, c.component, obj)
+	switch {
+	case apply && !exists:
+		c.tracker.event(c.Client, c.component, obj, corev1.EventTypeNormal, "Created", "created")
+	case apply && resourceVersion != "" && resourceVersion == obj.GetResourceVersion():
+		// Server-side apply doesn't write the object if nothing is changed
+	default:
+		c.tracker.event(c.Client, c.component, obj, corev1.EventTypeNormal, "Updated", "updated")
+	}
+	return nil
+}
+
+func (c *trackingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	if err == nil || errors.IsNotFound(err) {
+		c.tracker.forget(c.Client, c.component, obj)
+	}
+	switch {
+	case err == nil:
+		c.tracker.event(c.Client, c.component, obj, corev1.EventTypeNormal, "Deleted", "deleted")
+	case !errors.IsNotFound(err):
+		c.tracker.event(c.Client, c.component, obj, corev1.EventTypeWarning, "DeleteFailed", "failed to delete: "+err.Error())
+	}
+	return err
+}
+
+// DryRun returns true if the wrapped client doesn't persist changes
+func (c *trackingClient) DryRun() bool {
+	return IsDryRun(c.Client)
+}
+
+func (c *trackingClient) keep(obj client.Object) {
+	c.tracker.record(c.Client, c.component, obj)
+}
+
+func (c *trackingClient) reference(obj client.Object) {
+	c.tracker.reference(c.Client, c.component, obj)
+}
+
+// current returns false if the object is not found, otherwise true and the current resourceVersion of the object.
+// Other errors are ignored.
+func (c *trackingClient) current(ctx context.Context, obj client.Object) (bool, string) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return true, ""
+	}
+	newObj, err := c.Scheme().New(gvk)
+	if err != nil {
+		return true, ""
+	}
+	current, ok := newObj.(client.Object)
+	if !ok {
+		return true, ""
+	}
+	if err = c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return !errors.IsNotFound(err), ""
+	}
+	return true, current.GetResourceVersion()
+}
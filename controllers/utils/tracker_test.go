@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResourceTracker(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{rbacv1.SchemeGroupVersion, corev1.SchemeGroupVersion})
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithRESTMapper(mapper).Build()
+	cr := &v1alpha1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"}}
+	recorder := record.NewFakeRecorder(10)
+	tracker := NewResourceTracker(cr, recorder)
+	ctx := context.Background()
+
+	grafana := tracker.Client(c, "grafana")
+	prometheus := tracker.Client(c, "prometheus")
+	etcd := tracker.Client(c, "etcd-monitor")
+
+	assert.NoError(t, grafana.Create(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "grafana"}}))
+	assert.NoError(t, grafana.Create(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring"}}))
+	assert.NoError(t, etcd.Create(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "etcd", Namespace: "kube-system"}}))
+	clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "prometheus"}}
+	assert.NoError(t, prometheus.Create(ctx, clusterRole))
+	assert.NoError(t, prometheus.Delete(ctx, clusterRole))
+
+	existing := []v1alpha1.ManagedResource{
+		{Component: "prometheus", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "prometheus"},
+		{Component: "prometheus-operator", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "prometheus-operator"},
+	}
+	merged := tracker.Merge(existing, []string{"prometheus-operator", "etcd-monitor", "grafana"})
+	assert.Equal(t, []v1alpha1.ManagedResource{
+		{Component: "prometheus-operator", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "prometheus-operator"},
+		{Component: "etcd-monitor", APIVersion: "v1", Kind: "Service", Namespace: "kube-system", Name: "etcd"},
+		{Component: "grafana", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "grafana"},
+	}, merged)
+
+	assert.Equal(t, 2, tracker.Managed("grafana"))
+	assert.Equal(t, 0, tracker.Managed("prometheus"))
+
+	// Objects read by the component are not managed by it
+	r := &ComponentReconciler{Client: grafana}
+	r.WatchResource(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "loki-credentials", Namespace: "monitoring"}})
+	assert.Equal(t, []v1alpha1.ManagedResource{
+		{Component: "grafana", APIVersion: "v1", Kind: "Secret", Namespace: "monitoring", Name: "loki-credentials"},
+	}, tracker.ReferencedObjects("grafana"))
+	assert.Equal(t, 2, tracker.Managed("grafana"))
+	assert.Empty(t, tracker.ReferencedObjects("prometheus"))
+
+	assert.Error(t, grafana.Create(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring"}}))
+	close(recorder.Events)
+	var events []string
+	for e := range recorder.Events {
+		events = append(events, e)
+	}
+	assert.Equal(t, []string{
+		"Normal Created grafana: ClusterRole grafana created",
+		"Normal Created grafana: Service monitoring/grafana created",
+		"Normal Created etcd-monitor: Service kube-system/etcd created",
+		"Normal Created prometheus: ClusterRole prometheus created",
+		"Normal Deleted prometheus: ClusterRole prometheus deleted",
+		`Warning CreateFailed grafana: Service monitoring/grafana failed to create: services "grafana" already exists`,
+	}, events)
+}
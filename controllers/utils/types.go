@@ -5,6 +5,7 @@ import (
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -21,4 +22,6 @@ type ComponentReconciler struct {
 	Scheme *runtime.Scheme
 	Dc     discovery.DiscoveryInterface
 	Log    logr.Logger
+	// Recorder creates events for PlatformMonitoring, events are not created if it is nil
+	Recorder record.EventRecorder
 }
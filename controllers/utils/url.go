@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ValidateClusterLocalURL checks that the URL refers to an HTTP server inside the cluster,
+// so the operator doesn't download content from the internet. Allowed hosts are:
+// * hosts without dots, e.g. short names of Services in the same namespace;
+// * Services, e.g. name.namespace.svc or name.namespace.svc.cluster.local;
+// * loopback and private IP addresses.
+func ValidateClusterLocalURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https, got %q", u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("host must be set")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.IsLoopback() || ip.IsPrivate() {
+			return nil
+		}
+		return fmt.Errorf("IP address %s is not private", host)
+	}
+	host = strings.TrimSuffix(host, ".")
+	if !strings.Contains(host, ".") || strings.HasSuffix(host, ".svc") || strings.Contains(host, ".svc.") {
+		return nil
+	}
+	return fmt.Errorf("host %s is not a Service in the cluster", host)
+}
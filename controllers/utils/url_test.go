@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateClusterLocalURL(t *testing.T) {
+	for _, url := range []string{
+		"http://dashboards:8080/dashboards.tgz",
+		"http://dashboards.monitoring.svc/dashboards.tgz",
+		"https://dashboards.monitoring.svc.cluster.local./dashboard.json",
+		"http://10.0.0.10/dashboard.json",
+		"http://127.0.0.1:8080/dashboard.json",
+	} {
+		assert.NoError(t, ValidateClusterLocalURL(url), url)
+	}
+	for _, url := range []string{
+		"https://grafana.com/api/dashboards/1860",
+		"http://8.8.8.8/dashboard.json",
+		"ftp://dashboards/dashboard.json",
+		"file:///dashboards/dashboard.json",
+		"http:///dashboard.json",
+	} {
+		assert.Error(t, ValidateClusterLocalURL(url), url)
+	}
+}
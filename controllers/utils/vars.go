@@ -65,6 +65,10 @@ const (
 	VmClusterComponentName = "vmcluster"
 	VmSelectComponentName  = "vmselect"
 
+	VmMigrationComponentName = "vmctl-migration"
+
+	VmBackupComponentName = "vmbackup"
+
 	NginxIngressAppRootAnnotation = "nginx.ingress.kubernetes.io/app-root"
 
 	VmOperatorTLSSecret     = "vmoperator-tls-secret"
@@ -165,6 +169,10 @@ var (
 
 	VmUserAsset = BasePath + "vmuser.yaml"
 
+	VmMigrationJobAsset = BasePath + "job.yaml"
+
+	VmBackupCronJobAsset = BasePath + "cronjob.yaml"
+
 	// AlertManagerComponentName contains name of alertmanager pod
 	AlertManagerComponentName       = "alertmanager"
 	AlertManagerGroupName           = "AlertManager"
@@ -405,61 +413,6 @@ var (
 	// DashboardTemplateRightDelim defines right delimiter for Grafana dashboards to avoid using }}
 	DashboardTemplateRightDelim = "%}"
 
-	// PublicCloudDashboardsEnabled is a map[public-cloud-name]map[dashboard-name]enabling/disabling
-	// that configures enabling and disabling dashboards for specific public clouds.
-	// Set bool value to "true" if this dashboard must be always enabled in this public cloud.
-	// Set bool value to "false" if this dashboard must be always disabled in this public cloud.
-	PublicCloudDashboardsEnabled = map[string]map[string]bool{
-		"aws": {
-			"core-dns-dashboard": false,
-			"etcd-dashboard":     false,
-		},
-		"azure": {
-			"core-dns-dashboard": false,
-			"etcd-dashboard":     false,
-		},
-		"google": {
-			"core-dns-dashboard": false,
-			"etcd-dashboard":     false,
-		},
-	}
-	// PublicCloudRulesEnabled is a map[public-cloud-name]map[rule-group]enabling/disabling
-	// that configures enabling and disabling rule groups for specific public clouds.
-	// Set bool value to "true" if this group must be always enabled in this public cloud.
-	// Set bool value to "false" if this group must be always disabled in this public cloud.
-	PublicCloudRulesEnabled = map[string]map[string]bool{
-		"aws": {
-			"CoreDnsAlerts": false,
-			"Etcd":          false,
-		},
-		"azure": {
-			"CoreDnsAlerts": false,
-			"Etcd":          false,
-		},
-		"google": {
-			"CoreDnsAlerts": false,
-			"Etcd":          false,
-		},
-	}
-	// PublicCloudMonitorsEnabled is a map[public-cloud-name]map[monitor-name]enabling/disabling
-	// that configures enabling and disabling service and pod monitors for specific public clouds.
-	// Set bool value to "true" if this monitor must be always enabled in this public cloud.
-	// Set bool value to "false" if this monitor must be always disabled in this public cloud.
-	PublicCloudMonitorsEnabled = map[string]map[string]bool{
-		"aws": {
-			CoreDnsServiceMonitorName: false,
-			EtcdServiceMonitorName:    false,
-		},
-		"azure": {
-			CoreDnsServiceMonitorName: false,
-			EtcdServiceMonitorName:    false,
-		},
-		"google": {
-			CoreDnsServiceMonitorName: false,
-			EtcdServiceMonitorName:    false,
-		},
-	}
-
 	// PrivilegedRights indicates is extended privileges should be used for the monitoring components.
 	// If set to true, creates ClusterRole resources for services which needs it.
 	// If set to false, creates Role resources where it is possible and expects that ClusterRole resources
@@ -0,0 +1,182 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// BackupTargetLabel contains the storage node which is backed up by the CronJob and its Jobs
+	BackupTargetLabel = "monitoring.qubership.org/backup-target"
+	// BackupGenerationLabel contains the generation of backups which the Job writes to
+	BackupGenerationLabel = "monitoring.qubership.org/backup-generation"
+	// BackupTargetVmSingle is the target of backups of vmsingle. Targets of vmstorage are vmstorage-<index>.
+	BackupTargetVmSingle = "vmsingle"
+
+	// VmSingleDataVolumeName is the name of the data volume of vmsingle created by the VictoriaMetrics operator
+	VmSingleDataVolumeName = "data"
+	// BackupStoragePath is the path which the storage volume is mounted to in pods of vmbackup and vmrestore
+	BackupStoragePath = "/storage"
+	// backupVolumeName is the name of the filesystem volume of backups
+	backupVolumeName = "vmbackup-destination"
+	// backupVolumePath is the path which the filesystem volume of backups is mounted to
+	backupVolumePath = "/backups"
+	// restoreContainerName is the name of the init container of the storage which runs vmrestore
+	restoreContainerName = "vmrestore"
+	// restorePodIndex is expanded by the shell of the restore container into the index of the vmstorage pod,
+	// which is the suffix of the name of the pod of the StatefulSet
+	restorePodIndex = "${POD_NAME##*-}"
+)
+
+// GetBackup returns the backup of the storage or nil if backups are not set or no storage is installed
+func GetBackup(cr *v1alpha1.PlatformMonitoring) *v1alpha1.VmBackup {
+	if cr.Spec.Victoriametrics == nil || cr.Spec.Victoriametrics.Backup == nil {
+		return nil
+	}
+	vm := cr.Spec.Victoriametrics
+	if !vm.VmSingle.IsInstall() && !vm.VmCluster.IsInstall() {
+		return nil
+	}
+	return vm.Backup
+}
+
+// GetBackupCronJobName returns the name of the vmbackup CronJob of the target, e.g. vmbackup-vmstorage-0
+func GetBackupCronJobName(target string) string {
+	return utils.VmBackupComponentName + "-" + target
+}
+
+// GetBackupURL returns the URL of backups of the target without the generation,
+// e.g. s3://bucket/path/vmsingle or fs:///backups/path/vmstorage-0
+func GetBackupURL(backup *v1alpha1.VmBackup, target string) string {
+	if backup.S3 != nil {
+		return "s3://" + path.Join(backup.S3.Bucket, backup.S3.Path, target)
+	}
+	dir := backupVolumePath
+	if backup.Filesystem != nil {
+		dir = path.Join(dir, backup.Filesystem.Path)
+	}
+	return "fs://" + path.Join(dir, target)
+}
+
+// GetBackupGenerationURL returns the URL of the generation of backups of the target, e.g. s3://bucket/vmsingle/gen-1
+func GetBackupGenerationURL(backup *v1alpha1.VmBackup, target string, generation int32) string {
+	return fmt.Sprintf("%s/gen-%d", GetBackupURL(backup, target), generation)
+}
+
+// GetBackupFlags returns flags of vmbackup and vmrestore for the destination of backups
+func GetBackupFlags(backup *v1alpha1.VmBackup) map[string]string {
+	flags := map[string]string{}
+	if backup.S3 != nil && backup.S3.Endpoint != "" {
+		flags["customS3Endpoint"] = backup.S3.Endpoint
+	}
+	return flags
+}
+
+// FormatBackupFlags returns flags sorted by name in the format -name=value. Flags with empty values are skipped.
+func FormatBackupFlags(flags map[string]string) []string {
+	keys := make([]string, 0, len(flags))
+	for k, v := range flags {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	args := make([]string, 0, len(keys))
+	for _, k := range keys {
+		args = append(args, fmt.Sprintf("-%s=%s", k, flags[k]))
+	}
+	return args
+}
+
+// GetBackupEnvs returns environment variables with credentials of the S3-compatible storage from Secrets
+func GetBackupEnvs(backup *v1alpha1.VmBackup) []corev1.EnvVar {
+	if backup.S3 == nil {
+		return nil
+	}
+	var envs []corev1.EnvVar
+	if backup.S3.AccessKey != nil {
+		envs = append(envs, corev1.EnvVar{Name: "AWS_ACCESS_KEY_ID", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: backup.S3.AccessKey}})
+	}
+	if backup.S3.SecretKey != nil {
+		envs = append(envs, corev1.EnvVar{Name: "AWS_SECRET_ACCESS_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: backup.S3.SecretKey}})
+	}
+	if backup.S3.Region != "" {
+		envs = append(envs, corev1.EnvVar{Name: "AWS_REGION", Value: backup.S3.Region})
+	}
+	return envs
+}
+
+// GetBackupVolume returns the volume and its mount for the filesystem destination of backups
+// or nil if backups are written to the S3-compatible storage
+func GetBackupVolume(backup *v1alpha1.VmBackup) (*corev1.Volume, *corev1.VolumeMount) {
+	if backup.Filesystem == nil {
+		return nil, nil
+	}
+	return &corev1.Volume{Name: backupVolumeName, VolumeSource: backup.Filesystem.Volume.ToVolumeSource()},
+		&corev1.VolumeMount{Name: backupVolumeName, MountPath: backupVolumePath}
+}
+
+// GetRestoreContainer returns the init container of the storage which restores data of the storage volume
+// from the requested generation of backups with vmrestore. Returns nil if the restore is not requested.
+// vmrestore runs once for each ID of the restore: the file with the ID is created in the storage after the restore,
+// so restarts of the storage don't overwrite new data. Pods of vmstorage restore backups of their own index.
+func GetRestoreContainer(cr *v1alpha1.PlatformMonitoring, storageVolume string, vmstorage bool) *corev1.Container {
+	backup := GetBackup(cr)
+	if backup == nil || backup.Restore == nil {
+		return nil
+	}
+	target := BackupTargetVmSingle
+	if vmstorage {
+		target = "vmstorage-" + restorePodIndex
+	}
+	flags := GetBackupFlags(backup)
+	flags["src"] = GetBackupGenerationURL(backup, target, backup.Restore.Generation)
+	flags["storageDataPath"] = BackupStoragePath
+	args := FormatBackupFlags(flags)
+	for i := range args {
+		// Values of the custom resource are passed to the shell literally, only the index of the pod is expanded
+		args[i] = strings.ReplaceAll(shellQuote(args[i]), restorePodIndex, `'"`+restorePodIndex+`"'`)
+	}
+	marker := shellQuote(path.Join(BackupStoragePath, ".vmrestore-"+backup.Restore.ID))
+	script := strings.Join([]string{
+		"set -e",
+		fmt.Sprintf("if [ -f %s ]; then echo %s; exit 0; fi", marker, shellQuote("Restore "+backup.Restore.ID+" is already done")),
+		"/vmrestore-prod " + strings.Join(args, " "),
+		"touch " + marker,
+	}, "\n")
+
+	container := &corev1.Container{
+		Name:    restoreContainerName,
+		Image:   backup.RestoreImage,
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{script},
+		Env: append([]corev1.EnvVar{{
+			Name:      "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+		}}, GetBackupEnvs(backup)...),
+		VolumeMounts: []corev1.VolumeMount{{Name: storageVolume, MountPath: BackupStoragePath}},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		},
+	}
+	if backup.Resources.Size() > 0 {
+		container.Resources = backup.Resources
+	}
+	if _, mount := GetBackupVolume(backup); mount != nil {
+		container.VolumeMounts = append(container.VolumeMounts, *mount)
+	}
+	return container
+}
+
+// shellQuote returns the string in single quotes, so the shell doesn't interpret its characters
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,255 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// RetentionFilterFlag is the flag of vmsingle and vmstorage with retention periods of series
+	RetentionFilterFlag = "retentionFilter"
+	// DownsamplingPeriodFlag is the flag of vmsingle, vmstorage and vmselect with levels of downsampling
+	DownsamplingPeriodFlag = "downsampling.period"
+	// DedupIntervalFlag is the flag of vmsingle and vmstorage with the deduplication interval
+	DedupIntervalFlag = "dedup.minScrapeInterval"
+	// replicatedAgentDedupInterval is the deduplication interval of vmsingle if vmagent has several replicas
+	replicatedAgentDedupInterval = "30s"
+	// DefaultRetentionPeriod is the retention period of VictoriaMetrics if it is not set, 1 month
+	DefaultRetentionPeriod = "1"
+	// defaultScrapeInterval is the scrape interval of vmagent if it is not set
+	defaultScrapeInterval = 30 * time.Second
+)
+
+var (
+	// retentionPeriodRegexp matches values of the -retentionPeriod flag, a number without the suffix is a number of months
+	retentionPeriodRegexp = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)(s|m|h|d|w|y)?$`)
+	// imageVersionRegexp matches versions in tags of images of VictoriaMetrics, e.g. v1.101.0-enterprise-cluster
+	imageVersionRegexp = regexp.MustCompile(`^v?([0-9]+)\.([0-9]+)\.([0-9]+)`)
+)
+
+// Units of -retentionPeriod in the same way as VictoriaMetrics parses them
+var retentionPeriodUnits = map[string]time.Duration{
+	"":  31 * 24 * time.Hour,
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// GetRetentionPolicy returns the retention policy of the storage or nil if it is not set or no storage is installed
+func GetRetentionPolicy(cr *v1alpha1.PlatformMonitoring) *v1alpha1.VmRetentionPolicy {
+	if cr.Spec.Victoriametrics == nil || cr.Spec.Victoriametrics.RetentionPolicy == nil {
+		return nil
+	}
+	vm := cr.Spec.Victoriametrics
+	if !vm.VmSingle.IsInstall() && !vm.VmCluster.IsInstall() {
+		return nil
+	}
+	return vm.RetentionPolicy
+}
+
+// GetRetentionPeriod returns the global retention period of the installed storage
+func GetRetentionPeriod(cr *v1alpha1.PlatformMonitoring) string {
+	vm := cr.Spec.Victoriametrics
+	period := vm.VmSingle.RetentionPeriod
+	if vm.VmCluster.IsInstall() {
+		period = vm.VmCluster.RetentionPeriod
+	}
+	if period == "" {
+		return DefaultRetentionPeriod
+	}
+	return period
+}
+
+// GetVmSingleDedupInterval returns the value of -dedup.minScrapeInterval of vmsingle or the empty string
+// if deduplication is off. If vmagent has several replicas, the interval is 30s to deduplicate samples
+// written by each replica, otherwise the value is taken from extraArgs of vmsingle.
+func GetVmSingleDedupInterval(cr *v1alpha1.PlatformMonitoring) string {
+	vm := cr.Spec.Victoriametrics
+	if vm == nil {
+		return ""
+	}
+	if vm.VmAgent.Replicas != nil && *vm.VmAgent.Replicas > 1 {
+		return replicatedAgentDedupInterval
+	}
+	return vm.VmSingle.ExtraArgs[DedupIntervalFlag]
+}
+
+// ParseRetentionPeriod parses the value of -retentionPeriod, e.g. 14d or 3 (months)
+func ParseRetentionPeriod(value string) (time.Duration, error) {
+	match := retentionPeriodRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid retention period %q", value)
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(number * float64(retentionPeriodUnits[match[2]])), nil
+}
+
+// GetTenantFilter returns the series filter of the tenant of VmCluster by accountID and projectID pseudo-labels
+func GetTenantFilter(tenant string) string {
+	accountID, projectID, found := strings.Cut(tenant, ":")
+	if !found {
+		projectID = "0"
+	}
+	return fmt.Sprintf(`{vm_account_id="%s",vm_project_id="%s"}`, accountID, projectID)
+}
+
+// GetRetentionPolicyArgs returns flags of vmsingle or vmstorage generated from the retention policy.
+// Retention periods of tenants are used only if VmCluster is installed.
+func GetRetentionPolicyArgs(cr *v1alpha1.PlatformMonitoring) map[string]string {
+	policy := GetRetentionPolicy(cr)
+	if policy == nil {
+		return nil
+	}
+	args := map[string]string{}
+	var filters []string
+	for _, f := range policy.Filters {
+		filters = append(filters, f.Filter+":"+f.RetentionPeriod)
+	}
+	if cr.Spec.Victoriametrics.VmCluster.IsInstall() {
+		for _, t := range policy.Tenants {
+			filters = append(filters, GetTenantFilter(t.Tenant)+":"+t.RetentionPeriod)
+		}
+	}
+	if len(filters) > 0 {
+		args[RetentionFilterFlag] = strings.Join(filters, ",")
+	}
+	if downsampling := GetDownsamplingPeriod(policy); downsampling != "" {
+		args[DownsamplingPeriodFlag] = downsampling
+	}
+	return args
+}
+
+// GetDownsamplingPeriod returns the value of -downsampling.period. Levels keep their order,
+// because the first level with the matching filter is applied to series.
+func GetDownsamplingPeriod(policy *v1alpha1.VmRetentionPolicy) string {
+	levels := make([]string, 0, len(policy.Downsampling))
+	for _, d := range policy.Downsampling {
+		level := d.Offset + ":" + d.Interval
+		if d.Filter != "" {
+			level = d.Filter + ":" + level
+		}
+		levels = append(levels, level)
+	}
+	return strings.Join(levels, ",")
+}
+
+// ImageFeatures describes the distribution of VictoriaMetrics by the tag of its image
+type ImageFeatures struct {
+	// Versioned is false if the version can't be read from the tag, e.g. for latest or a digest
+	Versioned bool
+	// Enterprise is true for enterprise images, e.g. v1.101.0-enterprise-cluster
+	Enterprise bool
+	// Version is the major, minor and patch version of the image
+	Version [3]int
+}
+
+// Versions of VictoriaMetrics which support features of retention policies
+var (
+	// RetentionFiltersVersion supports -retentionFilter, including filters of tenants
+	RetentionFiltersVersion = [3]int{1, 83, 0}
+	// DownsamplingFiltersVersion supports series filters in -downsampling.period
+	DownsamplingFiltersVersion = [3]int{1, 100, 0}
+)
+
+// GetImageFeatures returns features of the image of VictoriaMetrics by its tag
+func GetImageFeatures(image string) ImageFeatures {
+	tag := utils.GetTagFromImage(image)
+	features := ImageFeatures{Enterprise: strings.Contains(tag, "enterprise")}
+	if match := imageVersionRegexp.FindStringSubmatch(tag); match != nil {
+		features.Versioned = true
+		for i := range features.Version {
+			features.Version[i], _ = strconv.Atoi(match[i+1])
+		}
+	}
+	return features
+}
+
+// AtLeast returns true if the version of the image is known and it is not older than the given version
+func (f ImageFeatures) AtLeast(version [3]int) bool {
+	if !f.Versioned {
+		return false
+	}
+	for i := range version {
+		if f.Version[i] != version[i] {
+			return f.Version[i] > version[i]
+		}
+	}
+	return true
+}
+
+// EstimateDiskUsage returns the estimated size in bytes of data ingested during the retention period
+// without replication. Downsampling levels without filters reduce the number of samples older than their offset
+// to one sample per interval. Retention filters and levels with filters are not taken into account,
+// because the share of matched series is unknown, so the estimate is the upper bound.
+// The scrape interval of vmagent is used as the interval between raw samples.
+func EstimateDiskUsage(cr *v1alpha1.PlatformMonitoring) (int64, error) {
+	policy := GetRetentionPolicy(cr)
+	if policy == nil || policy.Estimate == nil {
+		return 0, nil
+	}
+	retention, err := ParseRetentionPeriod(GetRetentionPeriod(cr))
+	if err != nil {
+		return 0, err
+	}
+	scrapeInterval := defaultScrapeInterval
+	if value := cr.Spec.Victoriametrics.VmAgent.ScrapeInterval; value != "" {
+		d, err := model.ParseDuration(value)
+		if err != nil {
+			return 0, err
+		}
+		scrapeInterval = time.Duration(d)
+	}
+
+	type level struct{ offset, interval time.Duration }
+	var levels []level
+	for _, d := range policy.Downsampling {
+		if d.Filter != "" {
+			continue
+		}
+		offset, err := model.ParseDuration(d.Offset)
+		if err != nil {
+			return 0, err
+		}
+		interval, err := model.ParseDuration(d.Interval)
+		if err != nil {
+			return 0, err
+		}
+		levels = append(levels, level{time.Duration(offset), time.Duration(interval)})
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].offset < levels[j].offset })
+
+	// Seconds of the retention period weighted by the share of samples which are left after downsampling
+	var seconds, share float64 = 0, 1
+	var previous time.Duration
+	for _, l := range levels {
+		if l.offset >= retention {
+			break
+		}
+		seconds += (l.offset - previous).Seconds() * share
+		previous = l.offset
+		if l.interval > scrapeInterval {
+			share = min(share, scrapeInterval.Seconds()/l.interval.Seconds())
+		}
+	}
+	seconds += (retention - previous).Seconds() * share
+
+	bytesPerSample := 1.0
+	if q := policy.Estimate.BytesPerSample; q != nil {
+		bytesPerSample = q.AsApproximateFloat64()
+	}
+	return int64(float64(policy.Estimate.IngestionRate) * bytesPerSample * seconds), nil
+}
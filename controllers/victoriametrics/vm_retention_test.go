@@ -0,0 +1,88 @@
+package victoriametrics
+
+import (
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+func TestParseRetentionPeriod(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"1":   31 * 24 * time.Hour,
+		"14d": 14 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"1y":  365 * 24 * time.Hour,
+		"1.5": 31 * 36 * time.Hour,
+	} {
+		d, err := ParseRetentionPeriod(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, d, value)
+	}
+	_, err := ParseRetentionPeriod("two weeks")
+	assert.Error(t, err)
+}
+
+func TestGetVmSingleDedupInterval(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{}
+	assert.Empty(t, GetVmSingleDedupInterval(cr))
+	cr.Spec.Victoriametrics = &v1alpha1.Victoriametrics{VmSingle: v1alpha1.VmSingle{ExtraArgs: map[string]string{DedupIntervalFlag: "1m"}}}
+	assert.Equal(t, "1m", GetVmSingleDedupInterval(cr))
+	// Samples written by replicas of vmagent are deduplicated
+	cr.Spec.Victoriametrics.VmAgent.Replicas = ptr.To[int32](2)
+	assert.Equal(t, "30s", GetVmSingleDedupInterval(cr))
+}
+
+func TestGetImageFeatures(t *testing.T) {
+	f := GetImageFeatures("victoriametrics/vmstorage:v1.101.0-enterprise-cluster")
+	assert.True(t, f.Enterprise)
+	assert.True(t, f.AtLeast(RetentionFiltersVersion))
+	assert.True(t, f.AtLeast(DownsamplingFiltersVersion))
+
+	f = GetImageFeatures("victoriametrics/victoria-metrics:v1.99.1")
+	assert.False(t, f.Enterprise)
+	assert.True(t, f.AtLeast(RetentionFiltersVersion))
+	assert.False(t, f.AtLeast(DownsamplingFiltersVersion))
+
+	f = GetImageFeatures("victoriametrics/victoria-metrics:latest")
+	assert.False(t, f.Versioned)
+	assert.False(t, f.AtLeast(RetentionFiltersVersion))
+}
+
+func TestEstimateDiskUsage(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{Spec: v1alpha1.PlatformMonitoringSpec{
+		Victoriametrics: &v1alpha1.Victoriametrics{
+			VmSingle: v1alpha1.VmSingle{Image: "victoriametrics/victoria-metrics:v1.103.0", RetentionPeriod: "30d"},
+			RetentionPolicy: &v1alpha1.VmRetentionPolicy{
+				Estimate: &v1alpha1.VmStorageEstimate{IngestionRate: 1000},
+			},
+		},
+	}}
+	bytes, err := EstimateDiskUsage(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000*30*24*3600), bytes)
+
+	// Samples older than 7 days are left once per 5 minutes instead of 30 seconds,
+	// levels with filters and levels after the retention period are ignored
+	cr.Spec.Victoriametrics.RetentionPolicy.Downsampling = []v1alpha1.VmDownsampling{
+		{Offset: "60d", Interval: "1h"},
+		{Filter: `{env="dev"}`, Offset: "1d", Interval: "1h"},
+		{Offset: "7d", Interval: "5m"},
+	}
+	bytes, err = EstimateDiskUsage(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000*(7*24*3600+23*24*3600/10)), bytes)
+
+	cr.Spec.Victoriametrics.RetentionPolicy.Estimate.BytesPerSample = ptr.To(resource.MustParse("500m"))
+	bytes, err = EstimateDiskUsage(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500*(7*24*3600+23*24*3600/10)), bytes)
+
+	cr.Spec.Victoriametrics.VmSingle.Install = ptr.To(false)
+	bytes, err = EstimateDiskUsage(cr)
+	assert.NoError(t, err)
+	assert.Zero(t, bytes)
+}
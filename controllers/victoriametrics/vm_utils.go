@@ -1,10 +1,17 @@
 package victoriametrics
 
 import (
+	"fmt"
+	"hash/fnv"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 )
 
+// DefaultTenant is the tenant of VmCluster which is used if the tenant is not set
+const DefaultTenant = "0"
+
 func GetVmalertTLSSecretName(vmalert v1alpha1.VmAlert) string {
 	if vmalert.TLSConfig != nil {
 		return vmalert.TLSConfig.SecretName
@@ -67,3 +74,121 @@ func GetVmstorageTLSSecretName(vmcluster v1alpha1.VmCluster) string {
 	}
 	return utils.VmStorageTLSSecret
 }
+
+// GetVmclusterTenant returns the tenant which vmagent writes to and vmalert and Grafana read from
+func GetVmclusterTenant(vmcluster v1alpha1.VmCluster) string {
+	if vmcluster.Tenant != "" {
+		return vmcluster.Tenant
+	}
+	return DefaultTenant
+}
+
+// GetVmclusterTenants returns tenants of VmCluster or nil if VmCluster is not installed
+func GetVmclusterTenants(cr *v1alpha1.PlatformMonitoring) []v1alpha1.VmTenant {
+	if cr.Spec.Victoriametrics == nil || !cr.Spec.Victoriametrics.VmCluster.IsInstall() {
+		return nil
+	}
+	return cr.Spec.Victoriametrics.Tenants
+}
+
+// GetTenantVmUserName returns the name of the VMUser of the tenant.
+// The Secret with credentials of the VMUser is named vmuser-<name>.
+func GetTenantVmUserName(tenant v1alpha1.VmTenant) string {
+	return utils.VmComponentName + "-" + tenant.Name
+}
+
+// GetVminsertURL returns the URL of the Prometheus API of vminsert for the tenant,
+// e.g. http://vminsert-k8s.monitoring.svc:8480/insert/0/prometheus
+func GetVminsertURL(cr *v1alpha1.PlatformMonitoring, tenant string) string {
+	vmCluster := vmClusterForURL(cr)
+	vmCluster.Spec.VMInsert = &vmetricsv1b1.VMInsert{}
+	if cr.Spec.Victoriametrics.VmCluster.VmInsert != nil {
+		vmCluster.Spec.VMInsert = cr.Spec.Victoriametrics.VmCluster.VmInsert.DeepCopy()
+	}
+	if cr.Spec.Victoriametrics.TLSEnabled {
+		if vmCluster.Spec.VMInsert.ExtraArgs == nil {
+			vmCluster.Spec.VMInsert.ExtraArgs = make(map[string]string)
+		}
+		vmCluster.Spec.VMInsert.ExtraArgs["tls"] = "true"
+	}
+	return vmCluster.VMInsertURL() + "/insert/" + tenant + "/prometheus"
+}
+
+// GetVmselectURL returns the URL of the Prometheus API of vmselect for the tenant,
+// e.g. http://vmselect-k8s.monitoring.svc:8481/select/0/prometheus
+func GetVmselectURL(cr *v1alpha1.PlatformMonitoring, tenant string) string {
+	vmCluster := vmClusterForURL(cr)
+	vmCluster.Spec.VMSelect = &vmetricsv1b1.VMSelect{}
+	if cr.Spec.Victoriametrics.VmCluster.VmSelect != nil {
+		vmCluster.Spec.VMSelect = cr.Spec.Victoriametrics.VmCluster.VmSelect.DeepCopy()
+	}
+	if cr.Spec.Victoriametrics.TLSEnabled {
+		if vmCluster.Spec.VMSelect.ExtraArgs == nil {
+			vmCluster.Spec.VMSelect.ExtraArgs = make(map[string]string)
+		}
+		vmCluster.Spec.VMSelect.ExtraArgs["tls"] = "true"
+	}
+	return vmCluster.VMSelectURL() + "/select/" + tenant + "/prometheus"
+}
+
+// GetVmsingleURL returns the URL of vmsingle, e.g. http://vmsingle-k8s.monitoring.svc:8429
+func GetVmsingleURL(cr *v1alpha1.PlatformMonitoring) string {
+	vmSingle := vmetricsv1b1.VMSingle{}
+	vmSingle.SetName(utils.VmComponentName)
+	vmSingle.SetNamespace(cr.GetNamespace())
+	if cr.Spec.Victoriametrics.TLSEnabled {
+		vmSingle.Spec.ExtraArgs = map[string]string{"tls": "true"}
+	}
+	return vmSingle.AsURL()
+}
+
+// GetMigrationDestination returns the storage backend which data is migrated to
+// or the empty string if the migration is not set or no backend is installed
+func GetMigrationDestination(cr *v1alpha1.PlatformMonitoring) v1alpha1.StorageBackend {
+	if cr.Spec.Victoriametrics == nil || cr.Spec.Victoriametrics.Migration == nil {
+		return ""
+	}
+	vm := cr.Spec.Victoriametrics
+	switch {
+	case vm.VmCluster.IsInstall() && !vm.VmSingle.IsInstall():
+		return v1alpha1.StorageBackendVMCluster
+	case vm.VmSingle.IsInstall() && !vm.VmCluster.IsInstall() && vm.Migration.Source != v1alpha1.VmMigrationSourceVMSingle:
+		return v1alpha1.StorageBackendVMSingle
+	}
+	return ""
+}
+
+// GetMigrationJobName returns the name of the vmctl Job of the data migration.
+// The name contains the hash of the source, the destination and the filters of migrated data,
+// so the migration is started again if any of them is changed.
+func GetMigrationJobName(cr *v1alpha1.PlatformMonitoring) string {
+	m := cr.Spec.Victoriametrics.Migration
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n%s", m.Source, GetMigrationDestination(cr), m.TimeStart, m.TimeEnd, m.Match)
+	return fmt.Sprintf("%s-%08x", utils.VmMigrationComponentName, h.Sum32())
+}
+
+// KeepMigrationSource returns true if the storage must not be deleted because it is the source of the data migration.
+// The source is kept until the current migration succeeds and the deletion of the source is confirmed.
+func KeepMigrationSource(cr *v1alpha1.PlatformMonitoring, source v1alpha1.VmMigrationSource) bool {
+	if GetMigrationDestination(cr) == "" || cr.Spec.Victoriametrics.Migration.Source != source {
+		return false
+	}
+	if !cr.Spec.Victoriametrics.Migration.ConfirmSourceDeletion {
+		return true
+	}
+	var status *v1alpha1.DataMigrationStatus
+	if cr.Status.Storage != nil {
+		status = cr.Status.Storage.DataMigration
+	}
+	return status == nil || status.Job != GetMigrationJobName(cr) || status.Phase != v1alpha1.DataMigrationSucceeded
+}
+
+// vmClusterForURL returns VMCluster with the name and the namespace which the operator uses for it.
+// Specs of vminsert and vmselect are copied into it, so the custom resource is not changed.
+func vmClusterForURL(cr *v1alpha1.PlatformMonitoring) *vmetricsv1b1.VMCluster {
+	vmCluster := &vmetricsv1b1.VMCluster{}
+	vmCluster.SetName(utils.VmComponentName)
+	vmCluster.SetNamespace(cr.GetNamespace())
+	return vmCluster
+}
@@ -1,8 +1,13 @@
 package vmagent
 
 import (
+	"context"
+	"fmt"
+	"sort"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
 	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -10,6 +15,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *VmAgentReconciler) handleServiceAccount(cr *v1alpha1.PlatformMonitoring) error {
@@ -25,21 +31,7 @@ func (r *VmAgentReconciler) handleServiceAccount(cr *v1alpha1.PlatformMonitoring
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAgent.Image)
 
-	e := &corev1.ServiceAccount{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -57,23 +49,7 @@ func (r *VmAgentReconciler) handleClusterRole(cr *v1alpha1.PlatformMonitoring) e
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAgent.Image)
 
-	e := &rbacv1.ClusterRole{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetName(m.GetName())
-	e.Rules = m.Rules
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -92,21 +68,7 @@ func (r *VmAgentReconciler) handleClusterRoleBinding(cr *v1alpha1.PlatformMonito
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAgent.Image)
 
-	e := &rbacv1.ClusterRoleBinding{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -125,23 +87,7 @@ func (r *VmAgentReconciler) handleRole(cr *v1alpha1.PlatformMonitoring) error {
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAgent.Image)
 
-	e := &rbacv1.Role{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetName(m.GetName())
-	e.Rules = m.Rules
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -160,57 +106,41 @@ func (r *VmAgentReconciler) handleRoleBinding(cr *v1alpha1.PlatformMonitoring) e
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAgent.Image)
 
-	e := &rbacv1.RoleBinding{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *VmAgentReconciler) handleVmAgent(cr *v1alpha1.PlatformMonitoring) error {
+func (r *VmAgentReconciler) handleVmAgent(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	m, err := vmAgent(r, cr)
 	if err != nil {
 		r.Log.Error(err, "Failed creating Vmagent manifest")
 		return err
 	}
+	namespaces, err := r.tenantNamespaces(ctx, cr)
+	if err != nil {
+		r.Log.Error(err, "Failed getting namespaces of tenants")
+		return err
+	}
+	setTenantRemoteWrites(cr, m, namespaces)
 	e := &vmetricsv1b1.VMAgent{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &vmetricsv1b1.VMAgent{ObjectMeta: metav1.ObjectMeta{
-				Name:      utils.VmAgentComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &vmetricsv1b1.VMAgent{ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.VmAgentComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -224,31 +154,21 @@ func (r *VmAgentReconciler) handleIngressV1beta1(cr *v1alpha1.PlatformMonitoring
 	}
 	e := &v1beta1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmAgentComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmAgentComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -262,31 +182,21 @@ func (r *VmAgentReconciler) handleIngressV1(cr *v1alpha1.PlatformMonitoring) err
 	}
 	e := &networkingv1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmAgentComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmAgentComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -445,3 +355,35 @@ func (r *VmAgentReconciler) deleteIngressV1(cr *v1alpha1.PlatformMonitoring) err
 	}
 	return nil
 }
+
+// tenantNamespaces returns names of namespaces selected by tenants of VmCluster by names of tenants.
+// Tenants without the namespace selector are skipped. Without privileged rights namespaces can't be listed,
+// so namespace selectors are ignored with the warning event instead of failing vmagent.
+func (r *VmAgentReconciler) tenantNamespaces(ctx context.Context, cr *v1alpha1.PlatformMonitoring) (map[string][]string, error) {
+	namespaces := make(map[string][]string)
+	for _, tenant := range victoriametrics.GetVmclusterTenants(cr) {
+		if tenant.NamespaceSelector == nil {
+			continue
+		}
+		if !utils.PrivilegedRights {
+			// Metrics of other namespaces are still written to the default tenant, so vmagent is updated
+			message := fmt.Sprintf("namespaceSelector of the tenant %s is ignored, because it requires privileged rights", tenant.Name)
+			r.Log.Info(message)
+			r.Event(cr, corev1.EventTypeWarning, "TenantNamespacesIgnored", message)
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(tenant.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant.Name, err)
+		}
+		list := &corev1.NamespaceList{}
+		if err = r.Client.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, ns := range list.Items {
+			namespaces[tenant.Name] = append(namespaces[tenant.Name], ns.GetName())
+		}
+		sort.Strings(namespaces[tenant.Name])
+	}
+	return namespaces, nil
+}
@@ -4,6 +4,7 @@ import (
 	"embed"
 	"errors"
 	"maps"
+	"slices"
 	"strings"
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
@@ -56,7 +57,7 @@ func vmAgentClusterRole(cr *v1alpha1.PlatformMonitoring, hasPsp, hasScc bool) (*
 			Resources:     []string{"securitycontextconstraints"},
 			Verbs:         []string{"use"},
 			APIGroups:     []string{"security.openshift.io"},
-			ResourceNames: []string{utils.VmOperatorComponentName},
+			ResourceNames: []string{cr.GetNamespace() + "-" + utils.VmOperatorComponentName},
 		})
 	}
 	return &clusterRole, nil
@@ -250,23 +251,16 @@ func vmAgent(r *VmAgentReconciler, cr *v1alpha1.PlatformMonitoring) (*vmetricsv1
 		}
 
 		if cr.Spec.Victoriametrics.VmOperator.IsInstall() && cr.Spec.Victoriametrics.VmCluster.IsInstall() {
-			vmCluster := vmetricsv1b1.VMCluster{}
-			vmCluster.SetName(utils.VmComponentName)
-			vmCluster.SetNamespace(cr.GetNamespace())
-			vmCluster.Spec.VMInsert = cr.Spec.Victoriametrics.VmCluster.VmInsert
 			vmagentRemoteWrite := vmetricsv1b1.VMAgentRemoteWriteSpec{}
-			if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.TLSEnabled {
-				if vmCluster.Spec.VMInsert.ExtraArgs == nil {
-					vmCluster.Spec.VMInsert.ExtraArgs = make(map[string]string)
-				}
-				maps.Copy(vmCluster.Spec.VMInsert.ExtraArgs, map[string]string{"tls": "true"})
+			if cr.Spec.Victoriametrics.TLSEnabled {
 				vmagentRemoteWrite.TLSConfig = &vmetricsv1b1.TLSConfig{
 					CAFile:   "/etc/vm/secrets/" + victoriametrics.GetVmagentTLSSecretName(cr.Spec.Victoriametrics.VmAgent) + "/ca.crt",
 					CertFile: "/etc/vm/secrets/" + victoriametrics.GetVmagentTLSSecretName(cr.Spec.Victoriametrics.VmAgent) + "/tls.crt",
 					KeyFile:  "/etc/vm/secrets/" + victoriametrics.GetVmagentTLSSecretName(cr.Spec.Victoriametrics.VmAgent) + "/tls.key",
 				}
 			}
-			vmagentRemoteWrite.URL = vmCluster.VMInsertURL() + "/insert/0/prometheus/api/v1/write"
+			tenant := victoriametrics.GetVmclusterTenant(cr.Spec.Victoriametrics.VmCluster)
+			vmagentRemoteWrite.URL = victoriametrics.GetVminsertURL(cr, tenant) + "/api/v1/write"
 			addVmInsert := true
 			for _, rw := range vmagent.Spec.RemoteWrite {
 				if rw.URL == vmagentRemoteWrite.URL {
@@ -526,3 +520,52 @@ func vmAgentIngressV1(cr *v1alpha1.PlatformMonitoring) (*networkingv1.Ingress, e
 	}
 	return &ingress, nil
 }
+
+// setTenantRemoteWrites adds remote writes to tenants of VmCluster. Metrics of namespaces selected by the tenant
+// are written only to the tenant: the remote write of the tenant keeps them and the remote write
+// of the default tenant drops them. Tenants without namespaces are skipped.
+func setTenantRemoteWrites(cr *v1alpha1.PlatformMonitoring, vmagent *vmetricsv1b1.VMAgent, namespaces map[string][]string) {
+	if !cr.Spec.Victoriametrics.VmOperator.IsInstall() {
+		return
+	}
+	defaultURL := victoriametrics.GetVminsertURL(cr, victoriametrics.GetVmclusterTenant(cr.Spec.Victoriametrics.VmCluster)) + "/api/v1/write"
+	// Remote writes can be shared with the custom resource, so they are copied before changes
+	vmagent.Spec.RemoteWrite = slices.Clone(vmagent.Spec.RemoteWrite)
+	var defaultRemoteWrite *vmetricsv1b1.VMAgentRemoteWriteSpec
+	for i := range vmagent.Spec.RemoteWrite {
+		if vmagent.Spec.RemoteWrite[i].URL == defaultURL {
+			defaultRemoteWrite = &vmagent.Spec.RemoteWrite[i]
+		}
+	}
+	if defaultRemoteWrite == nil {
+		return
+	}
+
+	var tenantRemoteWrites []vmetricsv1b1.VMAgentRemoteWriteSpec
+	var routed []string
+	for _, tenant := range victoriametrics.GetVmclusterTenants(cr) {
+		if len(namespaces[tenant.Name]) == 0 {
+			continue
+		}
+		remoteWrite := vmetricsv1b1.VMAgentRemoteWriteSpec{
+			URL:       victoriametrics.GetVminsertURL(cr, tenant.ID) + "/api/v1/write",
+			TLSConfig: defaultRemoteWrite.TLSConfig,
+			InlineUrlRelabelConfig: []vmetricsv1b1.RelabelConfig{{
+				SourceLabels: []string{"namespace"},
+				Regex:        vmetricsv1b1.StringOrArray(namespaces[tenant.Name]),
+				Action:       "keep",
+			}},
+		}
+		tenantRemoteWrites = append(tenantRemoteWrites, remoteWrite)
+		routed = append(routed, namespaces[tenant.Name]...)
+	}
+	if len(routed) == 0 {
+		return
+	}
+	defaultRemoteWrite.InlineUrlRelabelConfig = append(slices.Clone(defaultRemoteWrite.InlineUrlRelabelConfig), vmetricsv1b1.RelabelConfig{
+		SourceLabels: []string{"namespace"},
+		Regex:        vmetricsv1b1.StringOrArray(routed),
+		Action:       "drop",
+	})
+	vmagent.Spec.RemoteWrite = append(vmagent.Spec.RemoteWrite, tenantRemoteWrites...)
+}
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,13 +21,14 @@ type VmAgentReconciler struct {
 }
 
 // NewVmAgentReconciler creates an instance of VmAgentReconciler
-func NewVmAgentReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *VmAgentReconciler {
+func NewVmAgentReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *VmAgentReconciler {
 	return &VmAgentReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("vmagent_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("vmagent_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -63,7 +65,7 @@ func (r *VmAgentReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonito
 			}
 
 			// Reconcile vmAgent with creation and update
-			if err := r.handleVmAgent(cr); err != nil {
+			if err := r.handleVmAgent(ctx, cr); err != nil {
 				return err
 			}
 
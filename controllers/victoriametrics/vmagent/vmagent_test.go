@@ -1,11 +1,17 @@
 package vmagent
 
 import (
+	"context"
 	"testing"
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var (
@@ -69,6 +75,32 @@ func TestVmAgentManifests(t *testing.T) {
 		assert.Nil(t, m.Spec.MaxScrapeInterval)
 		assert.Nil(t, m.Spec.MinScrapeInterval)
 	})
+	t.Run("Test Vmagent remote write to the tenant of VmCluster", func(t *testing.T) {
+		clusterCR := &v1alpha1.PlatformMonitoring{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+			Spec: v1alpha1.PlatformMonitoringSpec{
+				Victoriametrics: &v1alpha1.Victoriametrics{
+					TLSEnabled: true,
+					VmOperator: v1alpha1.VmOperator{Image: "victoriametrics/operator:v0.48.3"},
+					VmAgent:    v1alpha1.VmAgent{Image: "victoriametrics/vmagent:v1.103.0"},
+					VmCluster: v1alpha1.VmCluster{
+						Tenant:         "1:2",
+						VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+						VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+						VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+					},
+				},
+			},
+		}
+		m, err := vmAgent(nil, clusterCR)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, m.Spec.RemoteWrite, 1)
+		assert.Equal(t, "https://vminsert-k8s.monitoring.svc:8480/insert/1:2/prometheus/api/v1/write", m.Spec.RemoteWrite[0].URL)
+		assert.NotNil(t, m.Spec.RemoteWrite[0].TLSConfig)
+		assert.Nil(t, clusterCR.Spec.Victoriametrics.VmCluster.VmInsert, "custom resource should not be changed")
+	})
 	cr = &v1alpha1.PlatformMonitoring{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "monitoring",
@@ -76,3 +108,68 @@ func TestVmAgentManifests(t *testing.T) {
 	}
 
 }
+
+func TestVmAgentTenantRemoteWrites(t *testing.T) {
+	clusterCR := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmOperator: v1alpha1.VmOperator{Image: "victoriametrics/operator:v0.48.3"},
+				VmAgent:    v1alpha1.VmAgent{Image: "victoriametrics/vmagent:v1.103.0"},
+				VmCluster: v1alpha1.VmCluster{
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+				},
+				Tenants: []v1alpha1.VmTenant{
+					{Name: "team-a", ID: "1", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+					{Name: "team-b", ID: "2", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}}},
+					{Name: "team-c", ID: "3"},
+				},
+			},
+		},
+	}
+	namespace := func(name, team string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		namespace("a-2", "a"),
+		namespace("a-1", "a"),
+		namespace("c-1", "c"),
+	).Build()
+	r := NewVmAgentReconciler(c, scheme.Scheme, nil, nil)
+
+	namespaces, err := r.tenantNamespaces(context.Background(), clusterCR)
+	assert.NoError(t, err)
+	assert.Empty(t, namespaces, "namespace selectors are ignored without privileged rights")
+
+	utils.PrivilegedRights = true
+	defer func() { utils.PrivilegedRights = false }()
+	namespaces, err = r.tenantNamespaces(context.Background(), clusterCR)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"team-a": {"a-1", "a-2"}}, namespaces)
+
+	m, err := vmAgent(nil, clusterCR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setTenantRemoteWrites(clusterCR, m, namespaces)
+	assert.Len(t, m.Spec.RemoteWrite, 2)
+	assert.Equal(t, "http://vminsert-k8s.monitoring.svc:8480/insert/0/prometheus/api/v1/write", m.Spec.RemoteWrite[0].URL)
+	assert.Equal(t, []vmetricsv1b1.RelabelConfig{
+		{SourceLabels: []string{"namespace"}, Regex: vmetricsv1b1.StringOrArray{"a-1", "a-2"}, Action: "drop"},
+	}, m.Spec.RemoteWrite[0].InlineUrlRelabelConfig)
+	assert.Equal(t, "http://vminsert-k8s.monitoring.svc:8480/insert/1/prometheus/api/v1/write", m.Spec.RemoteWrite[1].URL)
+	assert.Equal(t, []vmetricsv1b1.RelabelConfig{
+		{SourceLabels: []string{"namespace"}, Regex: vmetricsv1b1.StringOrArray{"a-1", "a-2"}, Action: "keep"},
+	}, m.Spec.RemoteWrite[1].InlineUrlRelabelConfig)
+
+	// Remote writes are not changed if no namespaces are selected
+	m, err = vmAgent(nil, clusterCR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setTenantRemoteWrites(clusterCR, m, map[string][]string{})
+	assert.Len(t, m.Spec.RemoteWrite, 1)
+	assert.Empty(t, m.Spec.RemoteWrite[0].InlineUrlRelabelConfig)
+}
@@ -25,21 +25,7 @@ func (r *VmAlertReconciler) handleServiceAccount(cr *v1alpha1.PlatformMonitoring
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlert.Image)
 
-	e := &corev1.ServiceAccount{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -57,23 +43,7 @@ func (r *VmAlertReconciler) handleClusterRole(cr *v1alpha1.PlatformMonitoring) e
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlert.Image)
 
-	e := &rbacv1.ClusterRole{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetName(m.GetName())
-	e.Rules = m.Rules
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -92,21 +62,7 @@ func (r *VmAlertReconciler) handleClusterRoleBinding(cr *v1alpha1.PlatformMonito
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlert.Image)
 
-	e := &rbacv1.ClusterRoleBinding{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -120,29 +76,21 @@ func (r *VmAlertReconciler) handleVmAlert(cr *v1alpha1.PlatformMonitoring) error
 	}
 	e := &vmetricsv1b1.VMAlert{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &vmetricsv1b1.VMAlert{ObjectMeta: metav1.ObjectMeta{
-				Name:      utils.VmAlertComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &vmetricsv1b1.VMAlert{ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.VmAlertComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -156,31 +104,21 @@ func (r *VmAlertReconciler) handleIngressV1beta1(cr *v1alpha1.PlatformMonitoring
 	}
 	e := &v1beta1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmAlertComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmAlertComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -194,31 +132,21 @@ func (r *VmAlertReconciler) handleIngressV1(cr *v1alpha1.PlatformMonitoring) err
 	}
 	e := &networkingv1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmAlertComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmAlertComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -62,7 +62,7 @@ func vmAlertClusterRole(cr *v1alpha1.PlatformMonitoring, hasPsp, hasScc bool) (*
 			Resources:     []string{"securitycontextconstraints"},
 			Verbs:         []string{"use"},
 			APIGroups:     []string{"security.openshift.io"},
-			ResourceNames: []string{utils.VmOperatorComponentName},
+			ResourceNames: []string{cr.GetNamespace() + "-" + utils.VmOperatorComponentName},
 		})
 	}
 
@@ -214,23 +214,17 @@ func vmAlert(r *VmAlertReconciler, cr *v1alpha1.PlatformMonitoring) (*vmetricsv1
 				}
 			}
 			if cr.Spec.Victoriametrics.VmOperator.IsInstall() && cr.Spec.Victoriametrics.VmCluster.IsInstall() {
-				vmCluster := vmetricsv1b1.VMCluster{}
-				vmCluster.SetName(utils.VmComponentName)
-				vmCluster.SetNamespace(cr.GetNamespace())
-				vmCluster.Spec.VMInsert = cr.Spec.Victoriametrics.VmCluster.VmInsert
-				if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.TLSEnabled {
-					if vmCluster.Spec.VMInsert.ExtraArgs == nil {
-						vmCluster.Spec.VMInsert.ExtraArgs = make(map[string]string)
-					}
-					maps.Copy(vmCluster.Spec.VMInsert.ExtraArgs, map[string]string{"tls": "true"})
-					vmalert.Spec.RemoteWrite = &vmetricsv1b1.VMAlertRemoteWriteSpec{URL: vmCluster.VMInsertURL() + "/insert/0/prometheus"}
+				tenant := victoriametrics.GetVmclusterTenant(cr.Spec.Victoriametrics.VmCluster)
+				remoteWriteURL := victoriametrics.GetVminsertURL(cr, tenant)
+				if cr.Spec.Victoriametrics.TLSEnabled {
+					vmalert.Spec.RemoteWrite = &vmetricsv1b1.VMAlertRemoteWriteSpec{URL: remoteWriteURL}
 					vmalert.Spec.RemoteWrite.TLSConfig = &vmetricsv1b1.TLSConfig{
 						CAFile:   "/etc/vm/secrets/" + victoriametrics.GetVmalertTLSSecretName(cr.Spec.Victoriametrics.VmAlert) + "/ca.crt",
 						CertFile: "/etc/vm/secrets/" + victoriametrics.GetVmalertTLSSecretName(cr.Spec.Victoriametrics.VmAlert) + "/tls.crt",
 						KeyFile:  "/etc/vm/secrets/" + victoriametrics.GetVmalertTLSSecretName(cr.Spec.Victoriametrics.VmAlert) + "/tls.key",
 					}
 				} else {
-					vmalert.Spec.RemoteWrite = &vmetricsv1b1.VMAlertRemoteWriteSpec{URL: vmCluster.VMInsertURL() + "/insert/0/prometheus"}
+					vmalert.Spec.RemoteWrite = &vmetricsv1b1.VMAlertRemoteWriteSpec{URL: remoteWriteURL}
 				}
 			}
 		}
@@ -364,23 +358,17 @@ func vmAlert(r *VmAlertReconciler, cr *v1alpha1.PlatformMonitoring) (*vmetricsv1
 				}
 			}
 			if cr.Spec.Victoriametrics.VmOperator.IsInstall() && cr.Spec.Victoriametrics.VmCluster.IsInstall() {
-				vmCluster := vmetricsv1b1.VMCluster{}
-				vmCluster.SetName(utils.VmComponentName)
-				vmCluster.SetNamespace(cr.GetNamespace())
-				vmCluster.Spec.VMSelect = cr.Spec.Victoriametrics.VmCluster.VmSelect
-				if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.TLSEnabled {
-					if vmCluster.Spec.VMSelect.ExtraArgs == nil {
-						vmCluster.Spec.VMSelect.ExtraArgs = make(map[string]string)
-					}
-					maps.Copy(vmCluster.Spec.VMSelect.ExtraArgs, map[string]string{"tls": "true"})
-					vmalert.Spec.Datasource = vmetricsv1b1.VMAlertDatasourceSpec{URL: vmCluster.VMSelectURL() + "/select/0/prometheus"}
+				tenant := victoriametrics.GetVmclusterTenant(cr.Spec.Victoriametrics.VmCluster)
+				datasourceURL := victoriametrics.GetVmselectURL(cr, tenant)
+				if cr.Spec.Victoriametrics.TLSEnabled {
+					vmalert.Spec.Datasource = vmetricsv1b1.VMAlertDatasourceSpec{URL: datasourceURL}
 					vmalert.Spec.Datasource.TLSConfig = &vmetricsv1b1.TLSConfig{
 						CAFile:   "/etc/vm/secrets/" + victoriametrics.GetVmalertTLSSecretName(cr.Spec.Victoriametrics.VmAlert) + "/ca.crt",
 						CertFile: "/etc/vm/secrets/" + victoriametrics.GetVmalertTLSSecretName(cr.Spec.Victoriametrics.VmAlert) + "/tls.crt",
 						KeyFile:  "/etc/vm/secrets/" + victoriametrics.GetVmalertTLSSecretName(cr.Spec.Victoriametrics.VmAlert) + "/tls.key",
 					}
 				} else {
-					vmalert.Spec.Datasource = vmetricsv1b1.VMAlertDatasourceSpec{URL: vmCluster.VMSelectURL() + "/select/0/prometheus"}
+					vmalert.Spec.Datasource = vmetricsv1b1.VMAlertDatasourceSpec{URL: datasourceURL}
 				}
 			}
 		}
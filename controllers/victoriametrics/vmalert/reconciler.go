@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,13 +21,14 @@ type VmAlertReconciler struct {
 }
 
 // NewVmAlertReconciler creates an instance of VmAlertManagerReconciler
-func NewVmAlertReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *VmAlertReconciler {
+func NewVmAlertReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *VmAlertReconciler {
 	return &VmAlertReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("vmalert_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("vmalert_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -32,6 +32,28 @@ func TestVmAlertManifests(t *testing.T) {
 		assert.NotNil(t, m.GetLabels())
 		assert.NotNil(t, m.GetAnnotations())
 	})
+	t.Run("Test vmAlert datasource and remote write of VmCluster", func(t *testing.T) {
+		clusterCR := &v1alpha1.PlatformMonitoring{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+			Spec: v1alpha1.PlatformMonitoringSpec{
+				Victoriametrics: &v1alpha1.Victoriametrics{
+					VmOperator: v1alpha1.VmOperator{Image: "victoriametrics/operator:v0.48.3"},
+					VmAlert:    v1alpha1.VmAlert{Image: "victoriametrics/vmalert:v1.103.0"},
+					VmCluster: v1alpha1.VmCluster{
+						VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+						VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+						VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+					},
+				},
+			},
+		}
+		m, err := vmAlert(nil, clusterCR)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "http://vmselect-k8s.monitoring.svc:8481/select/0/prometheus", m.Spec.Datasource.URL)
+		assert.Equal(t, "http://vminsert-k8s.monitoring.svc:8480/insert/0/prometheus", m.Spec.RemoteWrite.URL)
+	})
 	cr = &v1alpha1.PlatformMonitoring{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "monitoring",
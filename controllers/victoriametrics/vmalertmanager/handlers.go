@@ -25,21 +25,7 @@ func (r *VmAlertManagerReconciler) handleServiceAccount(cr *v1alpha1.PlatformMon
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlertManager.Image)
 
-	e := &corev1.ServiceAccount{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -58,23 +44,7 @@ func (r *VmAlertManagerReconciler) handleClusterRole(cr *v1alpha1.PlatformMonito
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlertManager.Image)
 
-	e := &rbacv1.ClusterRole{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetName(m.GetName())
-	e.Rules = m.Rules
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -93,21 +63,7 @@ func (r *VmAlertManagerReconciler) handleClusterRoleBinding(cr *v1alpha1.Platfor
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlertManager.Image)
 
-	e := &rbacv1.ClusterRoleBinding{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -126,23 +82,7 @@ func (r *VmAlertManagerReconciler) handleRole(cr *v1alpha1.PlatformMonitoring) e
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlertManager.Image)
 
-	e := &rbacv1.Role{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetName(m.GetName())
-	e.Rules = m.Rules
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -161,21 +101,7 @@ func (r *VmAlertManagerReconciler) handleRoleBinding(cr *v1alpha1.PlatformMonito
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 	m.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmAlertManager.Image)
 
-	e := &rbacv1.RoleBinding{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -189,29 +115,21 @@ func (r *VmAlertManagerReconciler) handleVmAlertManager(cr *v1alpha1.PlatformMon
 	}
 	e := &vmetricsv1b1.VMAlertmanager{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &vmetricsv1b1.VMAlertmanager{ObjectMeta: metav1.ObjectMeta{
-				Name:      utils.VmAlertManagerComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &vmetricsv1b1.VMAlertmanager{ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.VmAlertManagerComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -232,28 +150,21 @@ func (r *VmAlertManagerReconciler) handleSecret(cr *v1alpha1.PlatformMonitoring)
 
 	e := &corev1.Secret{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
-				Name:      "vmalertmanager-vmalertmanager-config",
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmalertmanager-vmalertmanager-config",
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -267,31 +178,21 @@ func (r *VmAlertManagerReconciler) handleIngressV1beta1(cr *v1alpha1.PlatformMon
 	}
 	e := &v1beta1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmAlertManagerComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmAlertManagerComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -305,31 +206,21 @@ func (r *VmAlertManagerReconciler) handleIngressV1(cr *v1alpha1.PlatformMonitori
 	}
 	e := &networkingv1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmAlertManagerComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmAlertManagerComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -55,7 +55,7 @@ func vmAlertManagerClusterRole(cr *v1alpha1.PlatformMonitoring, hasPsp, hasScc b
 			Resources:     []string{"securitycontextconstraints"},
 			Verbs:         []string{"use"},
 			APIGroups:     []string{"security.openshift.io"},
-			ResourceNames: []string{utils.VmOperatorComponentName},
+			ResourceNames: []string{cr.GetNamespace() + "-" + utils.VmOperatorComponentName},
 		})
 	}
 
@@ -59,7 +59,7 @@ func vmAuthClusterRole(cr *v1alpha1.PlatformMonitoring, hasPsp, hasScc bool) (*r
 			Resources:     []string{"securitycontextconstraints"},
 			Verbs:         []string{"use"},
 			APIGroups:     []string{"security.openshift.io"},
-			ResourceNames: []string{utils.VmOperatorComponentName},
+			ResourceNames: []string{cr.GetNamespace() + "-" + utils.VmOperatorComponentName},
 		})
 	}
 
@@ -0,0 +1,197 @@
+package vmbackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ComponentLabel marks CronJobs of backups and their Jobs
+const ComponentLabel = "app.kubernetes.io/component"
+
+// handleCronJob creates or updates the CronJob of backups of the target
+func (r *VmBackupReconciler) handleCronJob(ctx context.Context, cr *v1alpha1.PlatformMonitoring, target backupTarget) (string, error) {
+	generation, origin, err := r.nextGeneration(ctx, cr, target.name)
+	if err != nil {
+		return "", err
+	}
+	m, err := backupCronJob(cr, target, generation, origin)
+	if err != nil {
+		r.Log.Error(err, "Failed creating CronJob manifest")
+		return "", err
+	}
+	if err = r.ApplyResource(cr, m); err != nil {
+		return "", err
+	}
+	return m.GetName(), nil
+}
+
+// backupTargets returns storage nodes of the installed storage which are backed up.
+// Storage nodes are read from VMCluster or VMSingle, so the number of vmstorage replicas and volumes
+// set by the VictoriaMetrics operator are used. Returns no targets if the storage is not created yet.
+func (r *VmBackupReconciler) backupTargets(cr *v1alpha1.PlatformMonitoring) ([]backupTarget, error) {
+	if cr.Spec.Victoriametrics.VmCluster.IsInstall() {
+		vmCluster := &vmetricsv1b1.VMCluster{}
+		vmCluster.SetName(utils.VmComponentName)
+		vmCluster.SetNamespace(cr.GetNamespace())
+		if err := r.GetResource(vmCluster); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		return vmStorageTargets(vmCluster)
+	}
+	vmSingle := &vmetricsv1b1.VMSingle{}
+	vmSingle.SetName(utils.VmComponentName)
+	vmSingle.SetNamespace(cr.GetNamespace())
+	if err := r.GetResource(vmSingle); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	target, err := vmSingleTarget(vmSingle)
+	if err != nil {
+		return nil, err
+	}
+	return []backupTarget{target}, nil
+}
+
+// vmSingleTarget returns the target of backups of vmsingle
+func vmSingleTarget(vmSingle *vmetricsv1b1.VMSingle) (backupTarget, error) {
+	if vmSingle.Spec.Storage == nil || vmSingle.Spec.StorageDataPath != "" {
+		return backupTarget{}, errors.New("vmsingle has no persistent volume to back up, storage must be set")
+	}
+	return backupTarget{
+		name:        victoriametrics.BackupTargetVmSingle,
+		claimName:   vmSingle.PrefixedName(),
+		snapshotURL: vmSingle.AsURL() + "/snapshot/create",
+		podLabels:   vmSingle.SelectorLabels(),
+		tolerations: vmSingle.Spec.Tolerations,
+	}, nil
+}
+
+// vmStorageTargets returns targets of backups of each replica of vmstorage
+func vmStorageTargets(vmCluster *vmetricsv1b1.VMCluster) ([]backupTarget, error) {
+	vmStorage := vmCluster.Spec.VMStorage
+	if vmStorage == nil {
+		return nil, nil
+	}
+	if vmStorage.Storage == nil {
+		return nil, errors.New("vmstorage has no persistent volumes to back up, storage must be set")
+	}
+	scheme := "http"
+	if vmStorage.ExtraArgs["tls"] == "true" {
+		scheme = "https"
+	}
+	port := vmStorage.Port
+	if port == "" {
+		port = "8482"
+	}
+	name := vmStorage.GetNameWithPrefix(vmCluster.GetName())
+	replicas := ptr.Deref(vmStorage.ReplicaCount, 1)
+	targets := make([]backupTarget, 0, replicas)
+	for i := int32(0); i < replicas; i++ {
+		pod := fmt.Sprintf("%s-%d", name, i)
+		targets = append(targets, backupTarget{
+			name:      fmt.Sprintf("vmstorage-%d", i),
+			claimName: fmt.Sprintf("%s-%s", vmStorage.GetStorageVolumeName(), pod),
+			// Pods of vmstorage are available by names in its headless Service
+			snapshotURL: fmt.Sprintf("%s://%s.%s.%s.svc:%s/snapshot/create", scheme, pod, name, vmCluster.GetNamespace(), port),
+			podLabels:   map[string]string{"statefulset.kubernetes.io/pod-name": pod},
+			tolerations: vmStorage.Tolerations,
+		})
+	}
+	return targets, nil
+}
+
+// nextGeneration returns the generation which the next backup of the target is written to
+// and the generation of the last successful backup which is used as the origin of the new generation.
+// Generations are used in turn after each successful backup. The last successful backup is found by Jobs of the target
+// or by the status if Jobs were removed.
+func (r *VmBackupReconciler) nextGeneration(ctx context.Context, cr *v1alpha1.PlatformMonitoring, target string) (int32, *int32, error) {
+	generations := cr.Spec.Victoriametrics.Backup.GetGenerations()
+	last, err := r.lastGeneration(ctx, cr, target)
+	if err != nil {
+		return 0, nil, err
+	}
+	if last == nil || *last >= generations {
+		return 0, nil, nil
+	}
+	next := (*last + 1) % generations
+	if next == *last {
+		return next, nil, nil
+	}
+	return next, last, nil
+}
+
+// lastGeneration returns the generation of the last successful backup of the target or nil if there is no such backup
+func (r *VmBackupReconciler) lastGeneration(ctx context.Context, cr *v1alpha1.PlatformMonitoring, target string) (*int32, error) {
+	list := &batchv1.JobList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()),
+		client.MatchingLabels{ComponentLabel: utils.VmBackupComponentName, victoriametrics.BackupTargetLabel: target}); err != nil {
+		return nil, err
+	}
+	var last *batchv1.Job
+	for i := range list.Items {
+		job := &list.Items[i]
+		if !jobSucceeded(job) || job.Status.CompletionTime == nil {
+			continue
+		}
+		if last == nil || last.Status.CompletionTime.Before(job.Status.CompletionTime) {
+			last = job
+		}
+	}
+	if last != nil {
+		if generation, err := strconv.ParseInt(last.Labels[victoriametrics.BackupGenerationLabel], 10, 32); err == nil {
+			return ptr.To(int32(generation)), nil
+		}
+	}
+	if cr.Status.Storage != nil {
+		for _, status := range cr.Status.Storage.Backups {
+			if status.Target == target {
+				return status.Generation, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// jobSucceeded returns true if the Job completed successfully
+func jobSucceeded(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteStaleCronJobs removes CronJobs of backups except the kept ones, e.g. of removed replicas of vmstorage.
+// Jobs and pods of CronJobs are deleted in background.
+func (r *VmBackupReconciler) deleteStaleCronJobs(ctx context.Context, cr *v1alpha1.PlatformMonitoring, keep map[string]bool) error {
+	list := &batchv1.CronJobList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()),
+		client.MatchingLabels{ComponentLabel: utils.VmBackupComponentName}); err != nil {
+		return err
+	}
+	var errs []error
+	for i := range list.Items {
+		cronJob := &list.Items[i]
+		if keep[cronJob.GetName()] {
+			continue
+		}
+		r.Log.Info("Delete CronJob of backups", "name", cronJob.GetName())
+		if err := r.Client.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package vmbackup
+
+import (
+	"embed"
+	"strconv"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+//go:embed  assets/*.yaml
+var assets embed.FS
+
+// storageVolumeName is the name of the volume with data of the storage node in the pod of the backup
+const storageVolumeName = "storage"
+
+// backupTarget is a storage node which data is backed up
+type backupTarget struct {
+	// name is vmsingle or vmstorage-<index>
+	name string
+	// claimName is the name of the PersistentVolumeClaim with data of the storage node
+	claimName string
+	// snapshotURL is the URL of the API of the storage node which creates snapshots
+	snapshotURL string
+	// podLabels select the pod of the storage node, so the pod of the backup runs on the same node
+	// and can mount the volume of the storage node
+	podLabels map[string]string
+	// tolerations of the pod of the storage node
+	tolerations []corev1.Toleration
+}
+
+// backupCronJob returns the CronJob which backs up the target to the generation of backups with vmbackup.
+// Data of the previous generation is copied on the side of the destination if the generation is written the first time.
+func backupCronJob(cr *v1alpha1.PlatformMonitoring, target backupTarget, generation int32, origin *int32) (*batchv1.CronJob, error) {
+	cronJob := batchv1.CronJob{}
+	if err := yaml.NewYAMLOrJSONDecoder(utils.MustAssetReader(assets, utils.VmBackupCronJobAsset), 100).Decode(&cronJob); err != nil {
+		return nil, err
+	}
+	backup := cr.Spec.Victoriametrics.Backup
+
+	//Set parameters
+	cronJob.SetGroupVersionKind(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"})
+	cronJob.SetName(victoriametrics.GetBackupCronJobName(target.name))
+	cronJob.SetNamespace(cr.GetNamespace())
+	cronJob.Labels["app.kubernetes.io/name"] = cronJob.GetName()
+	cronJob.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(cronJob.GetName(), cronJob.GetNamespace())
+	cronJob.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(backup.Image)
+	cronJob.Labels[victoriametrics.BackupTargetLabel] = target.name
+	cronJob.Spec.Schedule = backup.Schedule
+	cronJob.Spec.Suspend = &backup.Suspend
+
+	jobLabels := cronJob.Spec.JobTemplate.Labels
+	jobLabels[victoriametrics.BackupTargetLabel] = target.name
+	jobLabels[victoriametrics.BackupGenerationLabel] = strconv.Itoa(int(generation))
+
+	flags := victoriametrics.GetBackupFlags(backup)
+	flags["storageDataPath"] = victoriametrics.BackupStoragePath
+	flags["snapshot.createURL"] = target.snapshotURL
+	flags["dst"] = victoriametrics.GetBackupGenerationURL(backup, target.name, generation)
+	if origin != nil {
+		flags["origin"] = victoriametrics.GetBackupGenerationURL(backup, target.name, *origin)
+	}
+	if cr.Spec.Victoriametrics.TLSEnabled {
+		flags["snapshot.tlsInsecureSkipVerify"] = "true"
+	}
+	for k, v := range backup.ExtraArgs {
+		flags[k] = v
+	}
+
+	podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	podSpec.Volumes = []corev1.Volume{{
+		Name: storageVolumeName,
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: target.claimName,
+			ReadOnly:  true,
+		}},
+	}}
+	mounts := []corev1.VolumeMount{{Name: storageVolumeName, MountPath: victoriametrics.BackupStoragePath, ReadOnly: true}}
+	if volume, mount := victoriametrics.GetBackupVolume(backup); volume != nil {
+		podSpec.Volumes = append(podSpec.Volumes, *volume)
+		mounts = append(mounts, *mount)
+	}
+	for it := range podSpec.Containers {
+		c := &podSpec.Containers[it]
+		if c.Name == "vmbackup" {
+			c.Image = backup.Image
+			c.Args = victoriametrics.FormatBackupFlags(flags)
+			c.Env = victoriametrics.GetBackupEnvs(backup)
+			c.VolumeMounts = mounts
+			if backup.Resources.Size() > 0 {
+				c.Resources = backup.Resources
+			}
+			break
+		}
+	}
+	// The volume of the storage node can be mounted only on its node
+	podSpec.Affinity = &corev1.Affinity{PodAffinity: &corev1.PodAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: target.podLabels},
+			TopologyKey:   "kubernetes.io/hostname",
+		}},
+	}}
+	podSpec.Tolerations = target.tolerations
+	// Set security context
+	if backup.SecurityContext != nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{
+			RunAsUser: backup.SecurityContext.RunAsUser,
+			FSGroup:   backup.SecurityContext.FSGroup,
+		}
+	}
+	return &cronJob, nil
+}
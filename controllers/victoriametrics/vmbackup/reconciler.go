@@ -0,0 +1,74 @@
+package vmbackup
+
+import (
+	"context"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VmBackupReconciler provides methods to reconcile backups of the storage of VictoriaMetrics with vmbackup
+type VmBackupReconciler struct {
+	*utils.ComponentReconciler
+}
+
+// NewVmBackupReconciler creates an instance of VmBackupReconciler
+func NewVmBackupReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *VmBackupReconciler {
+	return &VmBackupReconciler{
+		ComponentReconciler: &utils.ComponentReconciler{
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("vmbackup_reconciler"),
+			Recorder: rec,
+		},
+	}
+}
+
+// Run reconciles backups of the storage.
+// Creates a CronJob for vmsingle or for each replica of vmstorage and removes CronJobs of storage nodes which don't exist.
+// The restore of the storage is the part of VMSingle and VMCluster, so it is reconciled by their components.
+// Results of backups are reported in the status of the custom resource.
+func (r *VmBackupReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	r.Log.Info("Reconciling component")
+
+	if victoriametrics.GetBackup(cr) == nil {
+		r.Log.Info("Uninstalling component if exists")
+		r.uninstall(ctx, cr)
+		r.Log.Info("Component reconciled")
+		return nil
+	}
+
+	targets, err := r.backupTargets(cr)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		r.Log.Info("Waiting for the storage to be created before backups")
+	}
+	keep := map[string]bool{}
+	for _, target := range targets {
+		name, err := r.handleCronJob(ctx, cr, target)
+		if err != nil {
+			return err
+		}
+		keep[name] = true
+	}
+	if err = r.deleteStaleCronJobs(ctx, cr, keep); err != nil {
+		return err
+	}
+	r.Log.Info("Component reconciled")
+	return nil
+}
+
+// uninstall deletes all resources related to the component
+func (r *VmBackupReconciler) uninstall(ctx context.Context, cr *v1alpha1.PlatformMonitoring) {
+	if err := r.deleteStaleCronJobs(ctx, cr, nil); err != nil {
+		r.Log.Error(err, "Can not delete CronJobs of backups")
+	}
+}
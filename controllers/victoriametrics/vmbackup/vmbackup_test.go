@@ -0,0 +1,208 @@
+package vmbackup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func backupCR() *v1alpha1.PlatformMonitoring {
+	return &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmSingle: v1alpha1.VmSingle{Image: "victoriametrics/victoria-metrics:v1.103.0"},
+				Backup: &v1alpha1.VmBackup{
+					Image:        "victoriametrics/vmbackup:v1.103.0",
+					RestoreImage: "victoriametrics/vmrestore:v1.103.0",
+					Schedule:     "0 1 * * *",
+					S3: &v1alpha1.VmBackupS3{
+						Bucket:    "backups",
+						Path:      "monitoring",
+						Endpoint:  "http://minio.minio.svc:9000",
+						AccessKey: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s3"}, Key: "access"},
+						SecretKey: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s3"}, Key: "secret"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func backupJob(target string, generation string, completed time.Time, conditionType batchv1.JobConditionType) *batchv1.Job {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name: victoriametrics.GetBackupCronJobName(target) + "-" + generation, Namespace: "monitoring",
+		Labels: map[string]string{
+			ComponentLabel:                        utils.VmBackupComponentName,
+			victoriametrics.BackupTargetLabel:     target,
+			victoriametrics.BackupGenerationLabel: generation,
+		},
+	}}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+	if conditionType == batchv1.JobComplete {
+		job.Status.CompletionTime = &metav1.Time{Time: completed}
+	}
+	return job
+}
+
+func TestBackupCronJobManifest(t *testing.T) {
+	t.Run("Test vmsingle is backed up to S3 with the previous generation as the origin", func(t *testing.T) {
+		cr := backupCR()
+		vmSingle := &vmetricsv1b1.VMSingle{ObjectMeta: metav1.ObjectMeta{Name: utils.VmComponentName, Namespace: "monitoring"}}
+		vmSingle.Spec.Storage = &corev1.PersistentVolumeClaimSpec{}
+		target, err := vmSingleTarget(vmSingle)
+		assert.NoError(t, err)
+		cronJob, err := backupCronJob(cr, target, 2, ptr.To(int32(1)))
+		assert.NoError(t, err)
+		assert.Equal(t, "vmbackup-vmsingle", cronJob.GetName())
+		assert.Equal(t, "0 1 * * *", cronJob.Spec.Schedule)
+		assert.Equal(t, "2", cronJob.Spec.JobTemplate.Labels[victoriametrics.BackupGenerationLabel])
+		assert.Equal(t, "vmsingle", cronJob.Spec.JobTemplate.Labels[victoriametrics.BackupTargetLabel])
+		podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		assert.Equal(t, "vmsingle-k8s", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		assert.Equal(t, vmSingle.SelectorLabels(), podSpec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector.MatchLabels)
+		container := podSpec.Containers[0]
+		assert.Equal(t, "victoriametrics/vmbackup:v1.103.0", container.Image)
+		assert.Equal(t, []string{
+			"-customS3Endpoint=http://minio.minio.svc:9000",
+			"-dst=s3://backups/monitoring/vmsingle/gen-2",
+			"-origin=s3://backups/monitoring/vmsingle/gen-1",
+			"-snapshot.createURL=http://vmsingle-k8s.monitoring.svc:8429/snapshot/create",
+			"-storageDataPath=/storage",
+		}, container.Args)
+		assert.Equal(t, []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"}, []string{container.Env[0].Name, container.Env[1].Name})
+	})
+	t.Run("Test each vmstorage is backed up to the filesystem", func(t *testing.T) {
+		cr := backupCR()
+		cr.Spec.Victoriametrics.TLSEnabled = true
+		cr.Spec.Victoriametrics.Backup.S3 = nil
+		cr.Spec.Victoriametrics.Backup.Filesystem = &v1alpha1.VmBackupFilesystem{
+			Volume: v1alpha1.VmBackupVolume{HostPath: &corev1.HostPathVolumeSource{Path: "/var/backups"}},
+			Path:   "vm",
+		}
+		vmCluster := &vmetricsv1b1.VMCluster{ObjectMeta: metav1.ObjectMeta{Name: utils.VmComponentName, Namespace: "monitoring"}}
+		vmCluster.Spec.VMStorage = &vmetricsv1b1.VMStorage{Storage: &vmetricsv1b1.StorageSpec{}}
+		vmCluster.Spec.VMStorage.ReplicaCount = ptr.To(int32(2))
+		vmCluster.Spec.VMStorage.ExtraArgs = map[string]string{"tls": "true"}
+		targets, err := vmStorageTargets(vmCluster)
+		assert.NoError(t, err)
+		assert.Len(t, targets, 2)
+		cronJob, err := backupCronJob(cr, targets[1], 0, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "vmbackup-vmstorage-1", cronJob.GetName())
+		podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		assert.Equal(t, "vmstorage-db-vmstorage-k8s-1", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		assert.Equal(t, "/var/backups", podSpec.Volumes[1].HostPath.Path)
+		assert.Equal(t, []string{
+			"-dst=fs:///backups/vm/vmstorage-1/gen-0",
+			"-snapshot.createURL=https://vmstorage-k8s-1.vmstorage-k8s.monitoring.svc:8482/snapshot/create",
+			"-snapshot.tlsInsecureSkipVerify=true",
+			"-storageDataPath=/storage",
+		}, podSpec.Containers[0].Args)
+	})
+	t.Run("Test storage without persistent volumes can't be backed up", func(t *testing.T) {
+		_, err := vmSingleTarget(&vmetricsv1b1.VMSingle{})
+		assert.Error(t, err)
+		vmCluster := &vmetricsv1b1.VMCluster{}
+		vmCluster.Spec.VMStorage = &vmetricsv1b1.VMStorage{}
+		_, err = vmStorageTargets(vmCluster)
+		assert.Error(t, err)
+	})
+}
+
+func TestRestoreContainer(t *testing.T) {
+	cr := backupCR()
+	assert.Nil(t, victoriametrics.GetRestoreContainer(cr, victoriametrics.VmSingleDataVolumeName, false))
+	cr.Spec.Victoriametrics.Backup.Restore = &v1alpha1.VmRestore{ID: "2024-05-01", Generation: 1}
+	container := victoriametrics.GetRestoreContainer(cr, victoriametrics.VmSingleDataVolumeName, false)
+	assert.Equal(t, "victoriametrics/vmrestore:v1.103.0", container.Image)
+	assert.Equal(t, []string{"/bin/sh", "-c"}, container.Command)
+	assert.Equal(t, "set -e\n"+
+		"if [ -f '/storage/.vmrestore-2024-05-01' ]; then echo 'Restore 2024-05-01 is already done'; exit 0; fi\n"+
+		"/vmrestore-prod '-customS3Endpoint=http://minio.minio.svc:9000' '-src=s3://backups/monitoring/vmsingle/gen-1' '-storageDataPath=/storage'\n"+
+		"touch '/storage/.vmrestore-2024-05-01'", container.Args[0])
+	assert.Equal(t, []corev1.VolumeMount{{Name: "data", MountPath: "/storage"}}, container.VolumeMounts)
+
+	// Values of the custom resource are not interpreted by the shell
+	cr.Spec.Victoriametrics.Backup.S3.Path = `it's/$(reboot)/"path"`
+	container = victoriametrics.GetRestoreContainer(cr, victoriametrics.VmSingleDataVolumeName, false)
+	assert.Contains(t, container.Args[0], `'-src=s3://backups/it'\''s/$(reboot)/"path"/vmsingle/gen-1'`)
+}
+
+func TestVmBackupReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, vmetricsv1b1.AddToScheme(scheme))
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	cr := backupCR()
+	now := time.Now()
+	staleCronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{
+		Name: victoriametrics.GetBackupCronJobName("vmstorage-2"), Namespace: "monitoring",
+		Labels: map[string]string{ComponentLabel: utils.VmBackupComponentName},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr, staleCronJob).Build()
+	r := &VmBackupReconciler{ComponentReconciler: &utils.ComponentReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}}
+	ctx := context.Background()
+
+	t.Run("Test the first backup is written to the first generation", func(t *testing.T) {
+		generation, origin, err := r.nextGeneration(context.Background(), cr, "vmsingle")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), generation)
+		assert.Nil(t, origin)
+	})
+	t.Run("Test generations are used in turn after successful backups", func(t *testing.T) {
+		for _, job := range []*batchv1.Job{
+			backupJob("vmsingle", "1", now.Add(-2*time.Hour), batchv1.JobComplete),
+			backupJob("vmsingle", "2", now.Add(-time.Hour), batchv1.JobComplete),
+			backupJob("vmsingle", "0", time.Time{}, batchv1.JobFailed),
+		} {
+			status := job.Status
+			assert.NoError(t, c.Create(ctx, job))
+			job.Status = status
+			assert.NoError(t, c.Status().Update(ctx, job))
+		}
+		generation, origin, err := r.nextGeneration(context.Background(), cr, "vmsingle")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), generation)
+		assert.Equal(t, ptr.To(int32(2)), origin)
+	})
+	t.Run("Test the last generation is taken from the status without Jobs", func(t *testing.T) {
+		cr.Status.Storage = &v1alpha1.StorageStatus{Backups: []v1alpha1.BackupStatus{{Target: "vmstorage-0", Generation: ptr.To(int32(0))}}}
+		generation, origin, err := r.nextGeneration(context.Background(), cr, "vmstorage-0")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), generation)
+		assert.Equal(t, ptr.To(int32(0)), origin)
+		cr.Spec.Victoriametrics.Backup.Generations = ptr.To(int32(1))
+		generation, origin, err = r.nextGeneration(context.Background(), cr, "vmstorage-0")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), generation)
+		assert.Nil(t, origin, "the only generation is updated incrementally")
+		cr.Spec.Victoriametrics.Backup.Generations = nil
+	})
+	t.Run("Test CronJobs are not created until the storage is created", func(t *testing.T) {
+		assert.NoError(t, r.Run(ctx, cr))
+		assert.Error(t, c.Get(ctx, client.ObjectKeyFromObject(staleCronJob), &batchv1.CronJob{}), "stale CronJob should be deleted")
+	})
+	t.Run("Test CronJobs are deleted with backups", func(t *testing.T) {
+		recreated := staleCronJob.DeepCopy()
+		recreated.SetResourceVersion("")
+		assert.NoError(t, c.Create(ctx, recreated))
+		cr.Spec.Victoriametrics.Backup = nil
+		assert.NoError(t, r.Run(ctx, cr))
+		assert.Error(t, c.Get(ctx, client.ObjectKeyFromObject(staleCronJob), &batchv1.CronJob{}))
+	})
+}
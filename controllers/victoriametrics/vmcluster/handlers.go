@@ -24,21 +24,7 @@ func (r *VmClusterReconciler) handleServiceAccount(cr *v1alpha1.PlatformMonitori
 	m.Labels["app.kubernetes.io/name"] = utils.TruncLabel(m.GetName())
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 
-	e := &corev1.ServiceAccount{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -55,23 +41,7 @@ func (r *VmClusterReconciler) handleClusterRole(cr *v1alpha1.PlatformMonitoring)
 	m.Labels["app.kubernetes.io/name"] = utils.TruncLabel(m.GetName())
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 
-	e := &rbacv1.ClusterRole{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetName(m.GetName())
-	e.Rules = m.Rules
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -89,21 +59,7 @@ func (r *VmClusterReconciler) handleClusterRoleBinding(cr *v1alpha1.PlatformMoni
 	m.Labels["app.kubernetes.io/name"] = utils.TruncLabel(m.GetName())
 	m.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(m.GetName(), m.GetNamespace())
 
-	e := &rbacv1.ClusterRoleBinding{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -117,31 +73,21 @@ func (r *VmClusterReconciler) handleIngressV1beta1(cr *v1alpha1.PlatformMonitori
 	}
 	e := &v1beta1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmSelectComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmSelectComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -155,31 +101,21 @@ func (r *VmClusterReconciler) handleIngressV1(cr *v1alpha1.PlatformMonitoring) e
 	}
 	e := &networkingv1.Ingress{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
-				Name:      cr.GetNamespace() + "-" + utils.VmSelectComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetNamespace() + "-" + utils.VmSelectComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.SetAnnotations(m.GetAnnotations())
-	e.Spec.Rules = m.Spec.Rules
-	e.Spec.TLS = m.Spec.TLS
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -193,29 +129,21 @@ func (r *VmClusterReconciler) handleVmCluster(cr *v1alpha1.PlatformMonitoring) e
 	}
 	e := &vmetricsv1b1.VMCluster{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			e = &vmetricsv1b1.VMCluster{ObjectMeta: metav1.ObjectMeta{
-				Name:      utils.VmClusterComponentName,
-				Namespace: cr.GetNamespace(),
-			}}
-			if err = r.GetResource(e); err == nil {
-				if err = r.DeleteResource(e); err != nil {
-					return err
-				}
-			}
-			if err = r.CreateResource(cr, m); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// Delete the object with the name used by previous versions
+		e = &vmetricsv1b1.VMCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.VmClusterComponentName,
+			Namespace: cr.GetNamespace(),
+		}}
+		if err = r.GetResource(e); err == nil {
+			if err = r.DeleteResource(e); err != nil {
 				return err
 			}
-			return nil
 		}
-		return err
 	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -56,7 +56,7 @@ func vmClusterClusterRole(cr *v1alpha1.PlatformMonitoring, hasPsp, hasScc bool)
 			Resources:     []string{"securitycontextconstraints"},
 			Verbs:         []string{"use"},
 			APIGroups:     []string{"security.openshift.io"},
-			ResourceNames: []string{utils.VmOperatorComponentName},
+			ResourceNames: []string{cr.GetNamespace() + "-" + utils.VmOperatorComponentName},
 		})
 	}
 
@@ -110,7 +110,7 @@ func vmCluster(cr *v1alpha1.PlatformMonitoring) (*vmetricsv1b1.VMCluster, error)
 		}
 
 		if cr.Spec.Victoriametrics.VmCluster.VmSelect != nil {
-			vmcluster.Spec.VMSelect = cr.Spec.Victoriametrics.VmCluster.VmSelect
+			vmcluster.Spec.VMSelect = cr.Spec.Victoriametrics.VmCluster.VmSelect.DeepCopy()
 			if cr.Spec.Victoriametrics.VmReplicas != nil {
 				vmcluster.Spec.VMSelect.ReplicaCount = cr.Spec.Victoriametrics.VmReplicas
 			}
@@ -139,7 +139,7 @@ func vmCluster(cr *v1alpha1.PlatformMonitoring) (*vmetricsv1b1.VMCluster, error)
 		}
 
 		if cr.Spec.Victoriametrics.VmCluster.VmStorage != nil {
-			vmcluster.Spec.VMStorage = cr.Spec.Victoriametrics.VmCluster.VmStorage
+			vmcluster.Spec.VMStorage = cr.Spec.Victoriametrics.VmCluster.VmStorage.DeepCopy()
 			if cr.Spec.Victoriametrics.VmReplicas != nil {
 				vmcluster.Spec.VMStorage.ReplicaCount = cr.Spec.Victoriametrics.VmReplicas
 			}
@@ -168,6 +168,33 @@ func vmCluster(cr *v1alpha1.PlatformMonitoring) (*vmetricsv1b1.VMCluster, error)
 			}}
 		}
 
+		// Set retention filters and downsampling of the retention policy.
+		// vmselect needs levels of downsampling to select the interval of samples on the queried time range.
+		if args := victoriametrics.GetRetentionPolicyArgs(cr); len(args) > 0 {
+			if vmcluster.Spec.VMStorage != nil {
+				if vmcluster.Spec.VMStorage.ExtraArgs == nil {
+					vmcluster.Spec.VMStorage.ExtraArgs = make(map[string]string)
+				}
+				maps.Copy(vmcluster.Spec.VMStorage.ExtraArgs, args)
+			}
+			if downsampling, ok := args[victoriametrics.DownsamplingPeriodFlag]; ok && vmcluster.Spec.VMSelect != nil {
+				if vmcluster.Spec.VMSelect.ExtraArgs == nil {
+					vmcluster.Spec.VMSelect.ExtraArgs = make(map[string]string)
+				}
+				vmcluster.Spec.VMSelect.ExtraArgs[victoriametrics.DownsamplingPeriodFlag] = downsampling
+			}
+		}
+
+		// Restore data from backups before vmstorage starts
+		if vmcluster.Spec.VMStorage != nil {
+			if restore := victoriametrics.GetRestoreContainer(cr, vmcluster.Spec.VMStorage.GetStorageVolumeName(), true); restore != nil {
+				vmcluster.Spec.VMStorage.InitContainers = append(vmcluster.Spec.VMStorage.InitContainers, *restore)
+				if volume, _ := victoriametrics.GetBackupVolume(cr.Spec.Victoriametrics.Backup); volume != nil {
+					vmcluster.Spec.VMStorage.Volumes = append(vmcluster.Spec.VMStorage.Volumes, *volume)
+				}
+			}
+		}
+
 		if cr.Spec.Victoriametrics.VmCluster.VmInsert != nil {
 			vmcluster.Spec.VMInsert = cr.Spec.Victoriametrics.VmCluster.VmInsert
 			if cr.Spec.Victoriametrics.VmReplicas != nil {
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -39,3 +41,71 @@ func TestVmClusterManifests(t *testing.T) {
 	}
 
 }
+
+func TestVmStorageRestore(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmCluster: v1alpha1.VmCluster{
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+					VmStorage:      &vmetricsv1b1.VMStorage{},
+				},
+				Backup: &v1alpha1.VmBackup{
+					Image:        "victoriametrics/vmbackup:v1.103.0",
+					RestoreImage: "victoriametrics/vmrestore:v1.103.0",
+					Schedule:     "0 1 * * *",
+					Filesystem: &v1alpha1.VmBackupFilesystem{
+						Volume: v1alpha1.VmBackupVolume{HostPath: &corev1.HostPathVolumeSource{Path: "/var/backups"}},
+					},
+					Restore: &v1alpha1.VmRestore{ID: "first", Generation: 1},
+				},
+			},
+		},
+	}
+	for range 2 {
+		m, err := vmCluster(cr)
+		assert.NoError(t, err)
+		assert.Len(t, m.Spec.VMStorage.InitContainers, 1)
+		restore := m.Spec.VMStorage.InitContainers[0]
+		assert.Equal(t, "victoriametrics/vmrestore:v1.103.0", restore.Image)
+		assert.Equal(t, "vmstorage-db", restore.VolumeMounts[0].Name)
+		assert.Contains(t, restore.Args[0], `'-src=fs:///backups/vmstorage-'"${POD_NAME##*-}"'/gen-1'`)
+		assert.Len(t, m.Spec.VMStorage.Volumes, 1)
+	}
+	assert.Empty(t, cr.Spec.Victoriametrics.VmCluster.VmStorage.InitContainers, "custom resource should not be changed")
+}
+
+func TestVmClusterRetentionPolicy(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmCluster: v1alpha1.VmCluster{
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-enterprise-cluster",
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-enterprise-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-enterprise-cluster",
+					VmSelect:       &vmetricsv1b1.VMSelect{},
+					VmStorage:      &vmetricsv1b1.VMStorage{},
+				},
+				RetentionPolicy: &v1alpha1.VmRetentionPolicy{
+					Filters: []v1alpha1.VmRetentionFilter{{Filter: `{env="dev"}`, RetentionPeriod: "7d"}},
+					Tenants: []v1alpha1.VmTenantRetention{{Tenant: "5", RetentionPeriod: "3d"}},
+					Downsampling: []v1alpha1.VmDownsampling{
+						{Filter: `{__name__=~"node_.*"}`, Offset: "1d", Interval: "1m"},
+						{Offset: "30d", Interval: "5m"},
+					},
+				},
+			},
+		},
+	}
+	m, err := vmCluster(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, `{env="dev"}:7d,{vm_account_id="5",vm_project_id="0"}:3d`, m.Spec.VMStorage.ExtraArgs["retentionFilter"])
+	assert.Equal(t, `{__name__=~"node_.*"}:1d:1m,30d:5m`, m.Spec.VMStorage.ExtraArgs["downsampling.period"])
+	assert.Equal(t, `{__name__=~"node_.*"}:1d:1m,30d:5m`, m.Spec.VMSelect.ExtraArgs["downsampling.period"])
+	assert.NotContains(t, m.Spec.VMSelect.ExtraArgs, "retentionFilter")
+	assert.Nil(t, cr.Spec.Victoriametrics.VmCluster.VmSelect.ExtraArgs, "custom resource should not be changed")
+}
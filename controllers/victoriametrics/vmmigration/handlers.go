@@ -0,0 +1,82 @@
+package vmmigration
+
+import (
+	"context"
+	"errors"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ComponentLabel marks Jobs of the data migration
+const ComponentLabel = "app.kubernetes.io/component"
+
+// handleJob creates the Job of the migration if it doesn't exist.
+// The template of the Job is immutable, so the existing Job is not updated.
+func (r *VmMigrationReconciler) handleJob(cr *v1alpha1.PlatformMonitoring) error {
+	m, err := migrationJob(cr)
+	if err != nil {
+		r.Log.Error(err, "Failed creating Job manifest")
+		return err
+	}
+	e := &batchv1.Job{}
+	e.SetName(m.GetName())
+	e.SetNamespace(m.GetNamespace())
+	if err = r.GetResource(e); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+	return r.CreateResource(cr, m)
+}
+
+// isDestinationReady returns true if the VMSingle or VMCluster which receives migrated data is operational
+func (r *VmMigrationReconciler) isDestinationReady(cr *v1alpha1.PlatformMonitoring, destination v1alpha1.StorageBackend) (bool, error) {
+	var status vmetricsv1b1.UpdateStatus
+	switch destination {
+	case v1alpha1.StorageBackendVMCluster:
+		vmCluster := &vmetricsv1b1.VMCluster{}
+		vmCluster.SetName(utils.VmComponentName)
+		vmCluster.SetNamespace(cr.GetNamespace())
+		if err := r.GetResource(vmCluster); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		status = vmCluster.Status.UpdateStatus
+	default:
+		vmSingle := &vmetricsv1b1.VMSingle{}
+		vmSingle.SetName(utils.VmComponentName)
+		vmSingle.SetNamespace(cr.GetNamespace())
+		if err := r.GetResource(vmSingle); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		status = vmSingle.Status.UpdateStatus
+	}
+	return status == vmetricsv1b1.UpdateStatusOperational, nil
+}
+
+// deleteStaleJobs removes Jobs of the migration except the kept one.
+// Pods of the Jobs are deleted in background.
+func (r *VmMigrationReconciler) deleteStaleJobs(ctx context.Context, cr *v1alpha1.PlatformMonitoring, keep string) error {
+	list := &batchv1.JobList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()),
+		client.MatchingLabels{ComponentLabel: utils.VmMigrationComponentName}); err != nil {
+		return err
+	}
+	var errs []error
+	for i := range list.Items {
+		job := &list.Items[i]
+		if job.GetName() == keep {
+			continue
+		}
+		r.Log.Info("Delete Job of the migration", "name", job.GetName())
+		if err := r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package vmmigration
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/prometheus"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+//go:embed  assets/*.yaml
+var assets embed.FS
+
+// defaultMatch selects all series of the source
+const defaultMatch = `{__name__!=""}`
+
+// migrationJob returns the Job which migrates data from the source to the storage of VictoriaMetrics with vmctl
+func migrationJob(cr *v1alpha1.PlatformMonitoring) (*batchv1.Job, error) {
+	job := batchv1.Job{}
+	if err := yaml.NewYAMLOrJSONDecoder(utils.MustAssetReader(assets, utils.VmMigrationJobAsset), 100).Decode(&job); err != nil {
+		return nil, err
+	}
+	migration := cr.Spec.Victoriametrics.Migration
+	args, err := vmctlArgs(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	//Set parameters
+	job.SetGroupVersionKind(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"})
+	job.SetName(victoriametrics.GetMigrationJobName(cr))
+	job.SetNamespace(cr.GetNamespace())
+	job.Labels["app.kubernetes.io/name"] = job.GetName()
+	job.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(job.GetName(), job.GetNamespace())
+	job.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(migration.Image)
+
+	if migration.BackoffLimit != nil {
+		job.Spec.BackoffLimit = migration.BackoffLimit
+	}
+	for it := range job.Spec.Template.Spec.Containers {
+		c := &job.Spec.Template.Spec.Containers[it]
+		if c.Name == "vmctl" {
+			c.Image = migration.Image
+			c.Args = args
+			if migration.Resources.Size() > 0 {
+				c.Resources = migration.Resources
+			}
+			break
+		}
+	}
+	// Set security context
+	if migration.SecurityContext != nil {
+		job.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
+			RunAsUser: migration.SecurityContext.RunAsUser,
+			FSGroup:   migration.SecurityContext.FSGroup,
+		}
+	}
+	return &job, nil
+}
+
+// vmctlArgs returns arguments of vmctl for the source and the destination of the migration.
+// VMSingle is migrated to vminsert with the native export and import, Prometheus is read with the remote read API.
+// Extra arguments override generated ones.
+func vmctlArgs(cr *v1alpha1.PlatformMonitoring) ([]string, error) {
+	migration := cr.Spec.Victoriametrics.Migration
+	destination := victoriametrics.GetMigrationDestination(cr)
+	if destination == "" {
+		return nil, fmt.Errorf("no storage of VictoriaMetrics to migrate data from %s to", migration.Source)
+	}
+	tlsEnabled := cr.Spec.Victoriametrics.TLSEnabled
+	tenant := victoriametrics.GetVmclusterTenant(cr.Spec.Victoriametrics.VmCluster)
+
+	var mode string
+	flags := map[string]string{}
+	switch migration.Source {
+	case v1alpha1.VmMigrationSourceVMSingle:
+		mode = "vm-native"
+		flags["vm-native-src-addr"] = victoriametrics.GetVmsingleURL(cr)
+		flags["vm-native-dst-addr"] = victoriametrics.GetVminsertURL(cr, tenant)
+		flags["vm-native-filter-time-start"] = migration.TimeStart
+		flags["vm-native-filter-time-end"] = migration.TimeEnd
+		flags["vm-native-filter-match"] = defaultMatch
+		if migration.Match != "" {
+			flags["vm-native-filter-match"] = migration.Match
+		}
+		if tlsEnabled {
+			flags["vm-native-src-insecure-skip-verify"] = "true"
+			flags["vm-native-dst-insecure-skip-verify"] = "true"
+		}
+	case v1alpha1.VmMigrationSourcePrometheus:
+		mode = "remote-read"
+		scheme := "http"
+		if prometheus.IsPrometheusTLSEnabled(cr) {
+			scheme = "https"
+			flags["remote-read-insecure-skip-verify"] = "true"
+		}
+		flags["remote-read-src-addr"] = fmt.Sprintf("%s://%s.%s.svc:9090", scheme, utils.PrometheusServiceName, cr.GetNamespace())
+		flags["remote-read-filter-time-start"] = migration.TimeStart
+		flags["remote-read-filter-time-end"] = migration.TimeEnd
+		flags["remote-read-step-interval"] = "day"
+		if destination == v1alpha1.StorageBackendVMCluster {
+			// vmctl adds the path of the tenant to the address of vminsert itself
+			flags["vm-addr"] = strings.TrimSuffix(victoriametrics.GetVminsertURL(cr, tenant), "/insert/"+tenant+"/prometheus")
+			flags["vm-account-id"] = tenant
+		} else {
+			flags["vm-addr"] = victoriametrics.GetVmsingleURL(cr)
+		}
+		if tlsEnabled {
+			flags["vm-insecure-skip-verify"] = "true"
+		}
+	default:
+		return nil, fmt.Errorf("unknown source of the migration %q", migration.Source)
+	}
+	for k, v := range migration.ExtraArgs {
+		flags[k] = v
+	}
+
+	keys := make([]string, 0, len(flags))
+	for k, v := range flags {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	// Silent mode disables interactive confirmations of vmctl
+	args := []string{mode, "-s"}
+	for _, k := range keys {
+		args = append(args, fmt.Sprintf("--%s=%s", k, flags[k]))
+	}
+	return args, nil
+}
@@ -0,0 +1,77 @@
+package vmmigration
+
+import (
+	"context"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VmMigrationReconciler provides methods to reconcile the data migration with vmctl
+type VmMigrationReconciler struct {
+	*utils.ComponentReconciler
+}
+
+// NewVmMigrationReconciler creates an instance of VmMigrationReconciler
+func NewVmMigrationReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *VmMigrationReconciler {
+	return &VmMigrationReconciler{
+		ComponentReconciler: &utils.ComponentReconciler{
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("vmmigration_reconciler"),
+			Recorder: rec,
+		},
+	}
+}
+
+// Run reconciles the data migration.
+// Creates the vmctl Job when the destination is ready and removes Jobs of previous parameters of the migration.
+// The Job is not created again after the source is deleted.
+// The progress of the Job is reported in the status of the custom resource.
+func (r *VmMigrationReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	r.Log.Info("Reconciling component")
+
+	destination := victoriametrics.GetMigrationDestination(cr)
+	if destination == "" {
+		r.Log.Info("Uninstalling component if exists")
+		r.uninstall(ctx, cr)
+		r.Log.Info("Component reconciled")
+		return nil
+	}
+
+	jobName := victoriametrics.GetMigrationJobName(cr)
+	if err := r.deleteStaleJobs(ctx, cr, jobName); err != nil {
+		return err
+	}
+	if status := cr.Status.Storage; status != nil && status.DataMigration != nil &&
+		status.DataMigration.Job == jobName && status.DataMigration.SourceDeleted {
+		r.Log.Info("Source of the migration is deleted, Job is not created again")
+		return nil
+	}
+	ready, err := r.isDestinationReady(cr, destination)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		r.Log.Info("Waiting for " + string(destination) + " to be operational before the migration")
+		return nil
+	}
+	if err = r.handleJob(cr); err != nil {
+		return err
+	}
+	r.Log.Info("Component reconciled")
+	return nil
+}
+
+// uninstall deletes all resources related to the component
+func (r *VmMigrationReconciler) uninstall(ctx context.Context, cr *v1alpha1.PlatformMonitoring) {
+	if err := r.deleteStaleJobs(ctx, cr, ""); err != nil {
+		r.Log.Error(err, "Can not delete Jobs of the migration")
+	}
+}
@@ -0,0 +1,146 @@
+package vmmigration
+
+import (
+	"context"
+	"testing"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func migrationCR(source v1alpha1.VmMigrationSource) *v1alpha1.PlatformMonitoring {
+	return &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmSingle: v1alpha1.VmSingle{Install: ptr.To(false), Image: "victoriametrics/victoria-metrics:v1.103.0"},
+				VmCluster: v1alpha1.VmCluster{
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+				},
+				Migration: &v1alpha1.VmMigration{
+					Source:    source,
+					Image:     "victoriametrics/vmctl:v1.104.0",
+					TimeStart: "2024-01-01T00:00:00Z",
+				},
+			},
+		},
+	}
+}
+
+func TestMigrationJobManifest(t *testing.T) {
+	t.Run("Test VMSingle is migrated to VMCluster with native export and import", func(t *testing.T) {
+		cr := migrationCR(v1alpha1.VmMigrationSourceVMSingle)
+		cr.Spec.Victoriametrics.Migration.BackoffLimit = ptr.To(int32(2))
+		cr.Spec.Victoriametrics.Migration.ExtraArgs = map[string]string{"vm-concurrency": "4"}
+		job, err := migrationJob(cr)
+		assert.NoError(t, err)
+		assert.Equal(t, victoriametrics.GetMigrationJobName(cr), job.GetName())
+		assert.Equal(t, "monitoring", job.GetNamespace())
+		assert.Equal(t, utils.VmMigrationComponentName, job.Labels[ComponentLabel])
+		assert.Equal(t, ptr.To(int32(2)), job.Spec.BackoffLimit)
+		container := job.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, "victoriametrics/vmctl:v1.104.0", container.Image)
+		assert.Equal(t, []string{
+			"vm-native",
+			"-s",
+			"--vm-concurrency=4",
+			"--vm-native-dst-addr=http://vminsert-k8s.monitoring.svc:8480/insert/0/prometheus",
+			`--vm-native-filter-match={__name__!=""}`,
+			"--vm-native-filter-time-start=2024-01-01T00:00:00Z",
+			"--vm-native-src-addr=http://vmsingle-k8s.monitoring.svc:8429",
+		}, container.Args)
+	})
+	t.Run("Test Prometheus is migrated to VMCluster with TLS by remote read", func(t *testing.T) {
+		cr := migrationCR(v1alpha1.VmMigrationSourcePrometheus)
+		cr.Spec.Victoriametrics.TLSEnabled = true
+		cr.Spec.Victoriametrics.VmCluster.Tenant = "5"
+		cr.Spec.Victoriametrics.Migration.TimeEnd = "2024-02-01T00:00:00Z"
+		args, err := vmctlArgs(cr)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"remote-read",
+			"-s",
+			"--remote-read-filter-time-end=2024-02-01T00:00:00Z",
+			"--remote-read-filter-time-start=2024-01-01T00:00:00Z",
+			"--remote-read-src-addr=http://prometheus-operated.monitoring.svc:9090",
+			"--remote-read-step-interval=day",
+			"--vm-account-id=5",
+			"--vm-addr=https://vminsert-k8s.monitoring.svc:8480",
+			"--vm-insecure-skip-verify=true",
+		}, args)
+	})
+	t.Run("Test Prometheus is migrated to VMSingle", func(t *testing.T) {
+		cr := migrationCR(v1alpha1.VmMigrationSourcePrometheus)
+		cr.Spec.Victoriametrics.VmSingle.Install = ptr.To(true)
+		cr.Spec.Victoriametrics.VmCluster.Install = ptr.To(false)
+		args, err := vmctlArgs(cr)
+		assert.NoError(t, err)
+		assert.Contains(t, args, "--vm-addr=http://vmsingle-k8s.monitoring.svc:8429")
+		assert.NotContains(t, args, "--vm-account-id=0")
+	})
+	t.Run("Test VMSingle can't be migrated without VMCluster", func(t *testing.T) {
+		cr := migrationCR(v1alpha1.VmMigrationSourceVMSingle)
+		cr.Spec.Victoriametrics.VmSingle.Install = ptr.To(true)
+		cr.Spec.Victoriametrics.VmCluster.Install = ptr.To(false)
+		_, err := migrationJob(cr)
+		assert.Error(t, err)
+	})
+}
+
+func TestVmMigrationReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, vmetricsv1b1.AddToScheme(scheme))
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	cr := migrationCR(v1alpha1.VmMigrationSourceVMSingle)
+	vmCluster := &vmetricsv1b1.VMCluster{ObjectMeta: metav1.ObjectMeta{Name: utils.VmComponentName, Namespace: "monitoring"}}
+	staleJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name: utils.VmMigrationComponentName + "-stale", Namespace: "monitoring",
+		Labels: map[string]string{ComponentLabel: utils.VmMigrationComponentName},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr, vmCluster, staleJob).Build()
+	r := &VmMigrationReconciler{ComponentReconciler: &utils.ComponentReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}}
+	ctx := context.Background()
+	jobKey := client.ObjectKey{Namespace: "monitoring", Name: victoriametrics.GetMigrationJobName(cr)}
+
+	t.Run("Test Job is not created until VMCluster is operational", func(t *testing.T) {
+		assert.NoError(t, r.Run(ctx, cr))
+		assert.Error(t, c.Get(ctx, jobKey, &batchv1.Job{}))
+		assert.Error(t, c.Get(ctx, client.ObjectKeyFromObject(staleJob), &batchv1.Job{}), "stale Job should be deleted")
+	})
+	t.Run("Test Job is created when VMCluster is operational", func(t *testing.T) {
+		vmCluster.Status.UpdateStatus = vmetricsv1b1.UpdateStatusOperational
+		assert.NoError(t, c.Update(ctx, vmCluster))
+		assert.NoError(t, r.Run(ctx, cr))
+		assert.NoError(t, c.Get(ctx, jobKey, &batchv1.Job{}))
+		assert.NoError(t, r.Run(ctx, cr), "existing Job should be kept")
+	})
+	t.Run("Test Job is not created again after the source is deleted", func(t *testing.T) {
+		assert.NoError(t, c.Delete(ctx, &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobKey.Name, Namespace: jobKey.Namespace}}))
+		cr.Status.Storage = &v1alpha1.StorageStatus{DataMigration: &v1alpha1.DataMigrationStatus{
+			Job: jobKey.Name, Phase: v1alpha1.DataMigrationSucceeded, SourceDeleted: true,
+		}}
+		assert.NoError(t, r.Run(ctx, cr))
+		assert.Error(t, c.Get(ctx, jobKey, &batchv1.Job{}))
+	})
+	t.Run("Test Jobs are deleted with the migration", func(t *testing.T) {
+		cr.Status.Storage = nil
+		assert.NoError(t, r.Run(ctx, cr))
+		assert.NoError(t, c.Get(ctx, jobKey, &batchv1.Job{}))
+		cr.Spec.Victoriametrics.Migration = nil
+		assert.NoError(t, r.Run(ctx, cr))
+		assert.Error(t, c.Get(ctx, jobKey, &batchv1.Job{}))
+	})
+}
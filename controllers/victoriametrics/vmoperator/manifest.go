@@ -332,14 +332,14 @@ func vmOperatorServiceMonitor(cr *v1alpha1.PlatformMonitoring) (*promv1.ServiceM
 	return &sm, nil
 }
 
-func vmOperatorSecurityContextConstraints() (*secv1.SecurityContextConstraints, error) {
+func vmOperatorSecurityContextConstraints(cr *v1alpha1.PlatformMonitoring) (*secv1.SecurityContextConstraints, error) {
 	scc := secv1.SecurityContextConstraints{}
 	if err := yaml.NewYAMLOrJSONDecoder(utils.MustAssetReader(assets, utils.VmOperatorSecurityContextConstraintsAsset), 100).Decode(&scc); err != nil {
 		return nil, err
 	}
 	//Set parameters
 	scc.SetGroupVersionKind(schema.GroupVersionKind{Group: "security.openshift.io", Version: "v1", Kind: "SecurityContextConstraints"})
-	scc.SetName(utils.VmOperatorComponentName)
+	scc.SetName(cr.GetNamespace() + "-" + utils.VmOperatorComponentName)
 
 	return &scc, nil
 }
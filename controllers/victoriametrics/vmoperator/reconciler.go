@@ -1,6 +1,8 @@
 package vmoperator
 
 import (
+	"context"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
 	secv1 "github.com/openshift/api/security/v1"
@@ -8,6 +10,7 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,14 +19,15 @@ type VmOperatorReconciler struct {
 	*utils.ComponentReconciler
 }
 
-func NewVmOperatorReconciler(c client.Client, s *runtime.Scheme, r *rest.Config, dc discovery.DiscoveryInterface) *VmOperatorReconciler {
+func NewVmOperatorReconciler(c client.Client, s *runtime.Scheme, r *rest.Config, dc discovery.DiscoveryInterface, rec record.EventRecorder) *VmOperatorReconciler {
 	clientSet, _ := kubernetes.NewForConfig(r)
 	return &VmOperatorReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("vmoperator_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("vmoperator_reconciler"),
+			Recorder: rec,
 		},
 		KubeClient: clientSet,
 	}
@@ -33,7 +37,7 @@ func NewVmOperatorReconciler(c client.Client, s *runtime.Scheme, r *rest.Config,
 // Creates new deployment, service, service account, cluster role and cluster role binding if they don't exist.
 // Updates deployment and service in case of any changes.
 // Returns true if need to requeue, false otherwise.
-func (r *VmOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
+func (r *VmOperatorReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
 	r.Log.Info("Reconciling component")
 
 	if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmOperator.IsInstall() {
@@ -88,7 +92,7 @@ func (r *VmOperatorReconciler) Run(cr *v1alpha1.PlatformMonitoring) error {
 			if err := r.handleKubeletService(cr); err != nil {
 				return err
 			}
-			if err := r.handleKubeletServiceEndpoints(cr); err != nil {
+			if err := r.handleKubeletServiceEndpoints(ctx, cr); err != nil {
 				return err
 			}
 			r.Log.Info("Component reconciled")
@@ -57,7 +57,7 @@ func vmSingleClusterRole(cr *v1alpha1.PlatformMonitoring, hasPsp, hasScc bool) (
 			Resources:     []string{"securitycontextconstraints"},
 			Verbs:         []string{"use"},
 			APIGroups:     []string{"security.openshift.io"},
-			ResourceNames: []string{utils.VmOperatorComponentName},
+			ResourceNames: []string{cr.GetNamespace() + "-" + utils.VmOperatorComponentName},
 		})
 	}
 
@@ -156,6 +156,13 @@ func vmSingle(r *VmSingleReconciler, cr *v1alpha1.PlatformMonitoring) (*vmetrics
 		if cr.Spec.Victoriametrics.VmSingle.StorageMetadata != nil {
 			vmsingle.Spec.StorageMetadata = *cr.Spec.Victoriametrics.VmSingle.StorageMetadata
 		}
+		// Restore data from backups before vmsingle starts
+		if restore := victoriametrics.GetRestoreContainer(cr, victoriametrics.VmSingleDataVolumeName, false); restore != nil {
+			vmsingle.Spec.InitContainers = append(vmsingle.Spec.InitContainers, *restore)
+			if volume, _ := victoriametrics.GetBackupVolume(cr.Spec.Victoriametrics.Backup); volume != nil {
+				vmsingle.Spec.Volumes = append(vmsingle.Spec.Volumes, *volume)
+			}
+		}
 		// Set nodeSelector for vmsingle deployment
 		if cr.Spec.Victoriametrics.VmSingle.NodeSelector != nil {
 			vmsingle.Spec.NodeSelector = cr.Spec.Victoriametrics.VmSingle.NodeSelector
@@ -168,6 +175,8 @@ func vmSingle(r *VmSingleReconciler, cr *v1alpha1.PlatformMonitoring) (*vmetrics
 		if cr.Spec.Victoriametrics.VmSingle.ExtraArgs != nil {
 			maps.Copy(vmsingle.Spec.ExtraArgs, cr.Spec.Victoriametrics.VmSingle.ExtraArgs)
 		}
+		// Set retention filters and downsampling of the retention policy
+		maps.Copy(vmsingle.Spec.ExtraArgs, victoriametrics.GetRetentionPolicyArgs(cr))
 
 		//A single-node VictoriaMetrics is capable of proxying requests to vmalert
 		//https://docs.victoriametrics.com/Single-server-VictoriaMetrics.html#vmalert
@@ -187,8 +196,8 @@ func vmSingle(r *VmSingleReconciler, cr *v1alpha1.PlatformMonitoring) (*vmetrics
 			maps.Copy(vmsingle.Spec.ExtraArgs, map[string]string{"vmalert.proxyURL": vmAlert.AsURL()})
 		}
 
-		if cr.Spec.Victoriametrics.VmAgent.Replicas != nil && *cr.Spec.Victoriametrics.VmAgent.Replicas > 1 {
-			maps.Copy(vmsingle.Spec.ExtraArgs, map[string]string{"dedup.minScrapeInterval": "30s"})
+		if dedup := victoriametrics.GetVmSingleDedupInterval(cr); dedup != "" {
+			maps.Copy(vmsingle.Spec.ExtraArgs, map[string]string{victoriametrics.DedupIntervalFlag: dedup})
 		}
 
 		if cr.Spec.Victoriametrics.VmSingle.ExtraEnvs != nil {
@@ -5,12 +5,14 @@ import (
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
 	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	secv1 "github.com/openshift/api/security/v1"
 	pspApi "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,13 +22,14 @@ type VmSingleReconciler struct {
 }
 
 // NewVmSingleReconciler creates an instance of VmSingleReconciler
-func NewVmSingleReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *VmSingleReconciler {
+func NewVmSingleReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *VmSingleReconciler {
 	return &VmSingleReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("vmsingle_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("vmsingle_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -95,6 +98,9 @@ func (r *VmSingleReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonit
 			r.Log.Info("Reconciling paused")
 			r.Log.Info("Component NOT reconciled")
 		}
+	} else if victoriametrics.KeepMigrationSource(cr, v1alpha1.VmMigrationSourceVMSingle) {
+		// vmsingle is not reconciled anymore, but its data is kept until it is migrated to vmcluster
+		r.Log.Info("Component is kept as the source of the data migration")
 	} else {
 		r.Log.Info("Uninstalling component if exists")
 		r.uninstall(cr)
@@ -63,3 +63,24 @@ func TestVmSingleManifests(t *testing.T) {
 	}
 
 }
+
+func TestVmSingleRetentionPolicy(t *testing.T) {
+	cr := &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmSingle: v1alpha1.VmSingle{Image: "victoriametrics/victoria-metrics:v1.103.0-enterprise"},
+				RetentionPolicy: &v1alpha1.VmRetentionPolicy{
+					Filters:      []v1alpha1.VmRetentionFilter{{Filter: `{env="dev"}`, RetentionPeriod: "7d"}},
+					Tenants:      []v1alpha1.VmTenantRetention{{Tenant: "5:1", RetentionPeriod: "3d"}},
+					Downsampling: []v1alpha1.VmDownsampling{{Offset: "30d", Interval: "5m"}},
+				},
+			},
+		},
+	}
+	m, err := vmSingle(nil, cr)
+	assert.NoError(t, err)
+	// Tenants are ignored by vmsingle
+	assert.Equal(t, `{env="dev"}:7d`, m.Spec.ExtraArgs["retentionFilter"])
+	assert.Equal(t, "30d:5m", m.Spec.ExtraArgs["downsampling.period"])
+}
@@ -1,9 +1,14 @@
 package vmuser
 
 import (
+	"context"
+	"errors"
+
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
 	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *VmUserReconciler) handleVmUser(cr *v1alpha1.PlatformMonitoring) error {
@@ -12,22 +17,7 @@ func (r *VmUserReconciler) handleVmUser(cr *v1alpha1.PlatformMonitoring) error {
 		r.Log.Error(err, "Failed creating vmuser manifest")
 		return err
 	}
-	e := &vmetricsv1b1.VMUser{ObjectMeta: m.ObjectMeta}
-	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
-			if err = r.CreateResource(cr, m); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
-	}
-
-	//Set parameters
-	e.SetLabels(m.GetLabels())
-	e.Spec = m.Spec
-
-	if err = r.UpdateResource(e); err != nil {
+	if err = r.ApplyResource(cr, m); err != nil {
 		return err
 	}
 	return nil
@@ -41,7 +31,7 @@ func (r *VmUserReconciler) deleteVmUser(cr *v1alpha1.PlatformMonitoring) error {
 	}
 	e := &vmetricsv1b1.VMUser{ObjectMeta: m.ObjectMeta}
 	if err = r.GetResource(e); err != nil {
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			return nil
 		}
 		return err
@@ -51,3 +41,46 @@ func (r *VmUserReconciler) deleteVmUser(cr *v1alpha1.PlatformMonitoring) error {
 	}
 	return nil
 }
+
+// handleTenantVmUsers creates or updates VMUsers of tenants of VmCluster
+// and removes VMUsers of tenants which are no longer in the custom resource
+func (r *VmUserReconciler) handleTenantVmUsers(ctx context.Context, cr *v1alpha1.PlatformMonitoring) error {
+	keep := make(map[string]struct{})
+	var errs []error
+	for _, tenant := range victoriametrics.GetVmclusterTenants(cr) {
+		m, err := tenantVmUser(cr, tenant)
+		if err != nil {
+			r.Log.Error(err, "Failed creating vmuser manifest of the tenant", "tenant", tenant.Name)
+			errs = append(errs, err)
+			continue
+		}
+		keep[tenant.Name] = struct{}{}
+		if err = r.ApplyResource(cr, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := r.deleteStaleTenantVmUsers(ctx, cr, keep); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// deleteStaleTenantVmUsers removes VMUsers of tenants which are not kept
+func (r *VmUserReconciler) deleteStaleTenantVmUsers(ctx context.Context, cr *v1alpha1.PlatformMonitoring, keep map[string]struct{}) error {
+	list := &vmetricsv1b1.VMUserList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(cr.GetNamespace()), client.HasLabels{TenantLabel}); err != nil {
+		return err
+	}
+	var errs []error
+	for i := range list.Items {
+		user := &list.Items[i]
+		if _, ok := keep[user.Labels[TenantLabel]]; ok {
+			continue
+		}
+		r.Log.Info("Delete vmuser of the tenant", "name", user.GetName())
+		if err := r.DeleteResource(user); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
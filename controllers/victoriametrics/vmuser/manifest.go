@@ -9,8 +9,12 @@ import (
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
 	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/ptr"
 )
 
+// TenantLabel contains the name of the tenant of VmCluster in the VMUser of the tenant
+const TenantLabel = "monitoring.qubership.org/vm-tenant"
+
 //go:embed  assets/*.yaml
 var assets embed.FS
 
@@ -180,19 +184,96 @@ func vmUser(cr *v1alpha1.PlatformMonitoring) (*vmetricsv1b1.VMUser, error) {
 	}
 
 	if cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.TLSEnabled {
-		vmuser.Spec.UserConfigOption = vmetricsv1b1.UserConfigOption{
-			TLSConfig: &vmetricsv1b1.TLSConfig{
-				InsecureSkipVerify: false,
-				CAFile:             "/etc/vm/secrets/" + victoriametrics.GetVmauthTLSSecretName(cr.Spec.Victoriametrics.VmAuth) + "/ca.crt",
-				CertFile:           "/etc/vm/secrets/" + victoriametrics.GetVmauthTLSSecretName(cr.Spec.Victoriametrics.VmAuth) + "/tls.crt",
-				KeyFile:            "/etc/vm/secrets/" + victoriametrics.GetVmauthTLSSecretName(cr.Spec.Victoriametrics.VmAuth) + "/tls.key",
+		vmuser.Spec.UserConfigOption = tlsUserConfigOption(cr)
+	}
+
+	return &vmuser, nil
+}
+
+// tenantVmUser returns the VMUser of the tenant of VmCluster.
+// Requests of the user are routed to /select/<id> of vmselect and /insert/<id> of vminsert,
+// so the user can read and write only metrics of the tenant.
+func tenantVmUser(cr *v1alpha1.PlatformMonitoring, tenant v1alpha1.VmTenant) (*vmetricsv1b1.VMUser, error) {
+	vmuser := vmetricsv1b1.VMUser{}
+	if err := yaml.NewYAMLOrJSONDecoder(utils.MustAssetReader(assets, utils.VmUserAsset), 100).Decode(&vmuser); err != nil {
+		return nil, err
+	}
+
+	vmuser.SetName(victoriametrics.GetTenantVmUserName(tenant))
+	vmuser.SetNamespace(cr.GetNamespace())
+	vmuser.Labels[TenantLabel] = tenant.Name
+	vmuser.Labels["app.kubernetes.io/instance"] = utils.GetInstanceLabel(vmuser.GetName(), vmuser.GetNamespace())
+	vmuser.Labels["app.kubernetes.io/version"] = utils.GetTagFromImage(cr.Spec.Victoriametrics.VmUser.Image)
+
+	vmuser.Spec.UserName = ptr.To(tenant.Name)
+	if tenant.UserName != nil {
+		vmuser.Spec.UserName = tenant.UserName
+	}
+	if tenant.PasswordRef != nil {
+		vmuser.Spec.PasswordRef = tenant.PasswordRef
+	} else {
+		vmuser.Spec.GeneratePassword = true
+	}
+	vmuser.Spec.TargetRefs = []vmetricsv1b1.TargetRef{
+		{
+			CRD: &vmetricsv1b1.CRDRef{
+				Kind:      "VMCluster/vmselect",
+				Name:      utils.VmComponentName,
+				Namespace: cr.GetNamespace(),
 			},
-		}
+			Paths:            tenantSelectPaths(),
+			TargetPathSuffix: "/select/" + tenant.ID + "/prometheus",
+		},
+		{
+			CRD: &vmetricsv1b1.CRDRef{
+				Kind:      "VMCluster/vminsert",
+				Name:      utils.VmComponentName,
+				Namespace: cr.GetNamespace(),
+			},
+			Paths:            tenantInsertPaths(),
+			TargetPathSuffix: "/insert/" + tenant.ID + "/prometheus",
+		},
 	}
 
+	if cr.Spec.Victoriametrics.TLSEnabled {
+		vmuser.Spec.UserConfigOption = tlsUserConfigOption(cr)
+	}
 	return &vmuser, nil
 }
 
+// tlsUserConfigOption returns the option of the VMUser which allows vmauth to connect to components with TLS
+func tlsUserConfigOption(cr *v1alpha1.PlatformMonitoring) vmetricsv1b1.UserConfigOption {
+	return vmetricsv1b1.UserConfigOption{
+		TLSConfig: &vmetricsv1b1.TLSConfig{
+			InsecureSkipVerify: false,
+			CAFile:             "/etc/vm/secrets/" + victoriametrics.GetVmauthTLSSecretName(cr.Spec.Victoriametrics.VmAuth) + "/ca.crt",
+			CertFile:           "/etc/vm/secrets/" + victoriametrics.GetVmauthTLSSecretName(cr.Spec.Victoriametrics.VmAuth) + "/tls.crt",
+			KeyFile:            "/etc/vm/secrets/" + victoriametrics.GetVmauthTLSSecretName(cr.Spec.Victoriametrics.VmAuth) + "/tls.key",
+		},
+	}
+}
+
+// tenantSelectPaths returns paths of the Prometheus API which are routed to vmselect for the tenant
+func tenantSelectPaths() []string {
+	return []string{
+		"/api/v1/query.*",
+		"/api/v1/label.*",
+		"/api/v1/series.*",
+		"/api/v1/metadata.*",
+		"/api/v1/status.*",
+		"/api/v1/export.*",
+		"/federate",
+	}
+}
+
+// tenantInsertPaths returns paths of the Prometheus API which are routed to vminsert for the tenant
+func tenantInsertPaths() []string {
+	return []string{
+		"/api/v1/write",
+		"/api/v1/import.*",
+	}
+}
+
 func vmSelectPaths() []string {
 	return []string{"/select/.*"}
 }
@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -18,13 +19,14 @@ type VmUserReconciler struct {
 }
 
 // NewVmUserReconciler creates an instance of VmUserReconciler
-func NewVmUserReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface) *VmUserReconciler {
+func NewVmUserReconciler(c client.Client, s *runtime.Scheme, dc discovery.DiscoveryInterface, rec record.EventRecorder) *VmUserReconciler {
 	return &VmUserReconciler{
 		ComponentReconciler: &utils.ComponentReconciler{
-			Client: c,
-			Scheme: s,
-			Dc:     dc,
-			Log:    utils.Logger("vmuser_reconciler"),
+			Client:   c,
+			Scheme:   s,
+			Dc:       dc,
+			Log:      utils.Logger("vmuser_reconciler"),
+			Recorder: rec,
 		},
 	}
 }
@@ -43,6 +45,10 @@ func (r *VmUserReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitor
 			if err := r.handleVmUser(cr); err != nil {
 				return err
 			}
+			// Reconcile vmUsers of tenants of VmCluster
+			if err := r.handleTenantVmUsers(ctx, cr); err != nil {
+				return err
+			}
 
 			r.Log.Info("Component reconciled")
 		} else {
@@ -51,14 +57,18 @@ func (r *VmUserReconciler) Run(ctx context.Context, cr *v1alpha1.PlatformMonitor
 		}
 	} else {
 		r.Log.Info("Uninstalling component if exists")
-		r.uninstall(cr)
+		r.uninstall(ctx, cr)
 		r.Log.Info("Component reconciled")
 	}
 	return nil
 }
 
 // uninstall deletes all resources related to the component
-func (r *VmUserReconciler) uninstall(cr *v1alpha1.PlatformMonitoring) {
+func (r *VmUserReconciler) uninstall(ctx context.Context, cr *v1alpha1.PlatformMonitoring) {
+	if err := r.deleteStaleTenantVmUsers(ctx, cr, nil); err != nil {
+		r.Log.Error(err, "Can not delete vmusers of tenants")
+	}
+
 	// Fetch the VMSingle instance
 	m, err := vmUser(cr)
 	if err != nil {
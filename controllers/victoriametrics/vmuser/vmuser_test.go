@@ -1,11 +1,18 @@
 package vmuser
 
 import (
+	"context"
 	"testing"
 
 	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var (
@@ -67,3 +74,90 @@ func TestVmUserManifests(t *testing.T) {
 	}
 
 }
+
+func tenantsCR() *v1alpha1.PlatformMonitoring {
+	return &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"},
+		Spec: v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmAuth: v1alpha1.VmAuth{Image: "victoriametrics/vmauth:v1.103.0"},
+				VmCluster: v1alpha1.VmCluster{
+					VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+					VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+					VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+				},
+				Tenants: []v1alpha1.VmTenant{
+					{Name: "team-a", ID: "1"},
+					{Name: "team-b", ID: "2:1", UserName: ptr.To("b"), PasswordRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "team-b"}, Key: "password",
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestTenantVmUserManifest(t *testing.T) {
+	cr := tenantsCR()
+	m, err := tenantVmUser(cr, cr.Spec.Victoriametrics.Tenants[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "k8s-team-a", m.GetName())
+	assert.Equal(t, "vmuser-k8s-team-a", m.SecretName())
+	assert.Equal(t, "team-a", m.Labels[TenantLabel])
+	assert.Equal(t, "team-a", *m.Spec.UserName)
+	assert.True(t, m.Spec.GeneratePassword)
+	assert.Len(t, m.Spec.TargetRefs, 2)
+	assert.Equal(t, "VMCluster/vmselect", m.Spec.TargetRefs[0].CRD.Kind)
+	assert.Equal(t, "/select/1/prometheus", m.Spec.TargetRefs[0].TargetPathSuffix)
+	assert.Equal(t, "VMCluster/vminsert", m.Spec.TargetRefs[1].CRD.Kind)
+	assert.Equal(t, "/insert/1/prometheus", m.Spec.TargetRefs[1].TargetPathSuffix)
+	assert.Nil(t, m.Spec.TLSConfig)
+
+	cr.Spec.Victoriametrics.TLSEnabled = true
+	m, err = tenantVmUser(cr, cr.Spec.Victoriametrics.Tenants[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "b", *m.Spec.UserName)
+	assert.False(t, m.Spec.GeneratePassword)
+	assert.Equal(t, "team-b", m.Spec.PasswordRef.Name)
+	assert.Equal(t, "/insert/2:1/prometheus", m.Spec.TargetRefs[1].TargetPathSuffix)
+	assert.NotNil(t, m.Spec.TLSConfig)
+}
+
+func TestDeleteStaleTenantVmUsers(t *testing.T) {
+	cr := tenantsCR()
+	vmUser := func(name, tenant string) *vmetricsv1b1.VMUser {
+		m := &vmetricsv1b1.VMUser{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring"}}
+		if tenant != "" {
+			m.Labels = map[string]string{TenantLabel: tenant}
+		}
+		return m
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, vmetricsv1b1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		vmUser("k8s", ""),
+		vmUser("k8s-team-a", "team-a"),
+		vmUser("k8s-team-c", "team-c"),
+	).Build()
+	r := &VmUserReconciler{ComponentReconciler: &utils.ComponentReconciler{Client: c, Scheme: scheme, Log: utils.Logger("test")}}
+	names := func() []string {
+		list := &vmetricsv1b1.VMUserList{}
+		assert.NoError(t, c.List(context.Background(), list))
+		var result []string
+		for _, m := range list.Items {
+			result = append(result, m.GetName())
+		}
+		return result
+	}
+
+	// The fake client doesn't support server-side apply, so only removal of objects is checked
+	assert.NoError(t, r.deleteStaleTenantVmUsers(context.Background(), cr, map[string]struct{}{"team-a": {}, "team-b": {}}))
+	assert.Equal(t, []string{"k8s", "k8s-team-a"}, names())
+
+	assert.NoError(t, r.deleteStaleTenantVmUsers(context.Background(), cr, nil))
+	assert.Equal(t, []string{"k8s"}, names())
+}
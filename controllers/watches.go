@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	grafv1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// watchedObjects contains types of objects created by components which are watched by the controller.
+// Changes of these objects made by other managers trigger reconciliation of the component which owns the object.
+var watchedObjects = []client.Object{
+	&appsv1.Deployment{},
+	&appsv1.DaemonSet{},
+	&appsv1.StatefulSet{},
+	&corev1.Service{},
+	&corev1.Secret{},
+	&promv1.ServiceMonitor{},
+	&promv1.PodMonitor{},
+	&promv1.Prometheus{},
+	&promv1.Alertmanager{},
+	&promv1.PrometheusRule{},
+	&vmetricsv1b1.VMAgent{},
+	&vmetricsv1b1.VMSingle{},
+	&vmetricsv1b1.VMAlert{},
+	&vmetricsv1b1.VMAlertmanager{},
+	&vmetricsv1b1.VMAuth{},
+	&vmetricsv1b1.VMUser{},
+	&vmetricsv1b1.VMCluster{},
+	&vmetricsv1b1.VMRule{},
+	&grafv1.Grafana{},
+	&grafv1.GrafanaDataSource{},
+}
+
+// objectKey identifies an object regardless of the version of its API
+type objectKey struct {
+	schema.GroupKind
+	types.NamespacedName
+}
+
+// objectOwner describes the custom resource instance and its component which manage an object
+type objectOwner struct {
+	cr        types.NamespacedName
+	component string
+}
+
+// ownedObjects maps objects managed by the operator to components which manage them
+// and collects components which have to be reconciled because their objects were changed.
+// Methods of nil ownedObjects do nothing, so the controller works without watches.
+type ownedObjects struct {
+	mu      sync.Mutex
+	owners  map[objectKey]objectOwner
+	pending map[types.NamespacedName]map[string]struct{}
+	// full contains custom resource instances which have to be reconciled completely
+	full map[types.NamespacedName]struct{}
+}
+
+func newOwnedObjects() *ownedObjects {
+	return &ownedObjects{
+		owners:  map[objectKey]objectOwner{},
+		pending: map[types.NamespacedName]map[string]struct{}{},
+		full:    map[types.NamespacedName]struct{}{},
+	}
+}
+
+// update replaces objects of reconciled components of the custom resource instance
+// with objects recorded by the tracker. Objects read by components are mapped to them too,
+// so their changes are handled like changes of managed objects.
+func (o *ownedObjects) update(cr types.NamespacedName, components []string, tracker *utils.ResourceTracker) {
+	if o == nil {
+		return
+	}
+	reconciled := make(map[string]struct{}, len(components))
+	for _, c := range components {
+		reconciled[c] = struct{}{}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for key, owner := range o.owners {
+		if _, ok := reconciled[owner.component]; ok && owner.cr == cr {
+			delete(o.owners, key)
+		}
+	}
+	for _, c := range components {
+		objects := append(tracker.ManagedObjects(c), tracker.ReferencedObjects(c)...)
+		for _, res := range objects {
+			gv, err := schema.ParseGroupVersion(res.APIVersion)
+			if err != nil {
+				continue
+			}
+			key := objectKey{
+				GroupKind:      schema.GroupKind{Group: gv.Group, Kind: res.Kind},
+				NamespacedName: types.NamespacedName{Namespace: res.Namespace, Name: res.Name},
+			}
+			o.owners[key] = objectOwner{cr: cr, component: c}
+		}
+	}
+}
+
+// take returns components of the custom resource instance which have to be reconciled and forgets them.
+// Returns nil if all components have to be reconciled.
+func (o *ownedObjects) take(cr types.NamespacedName) map[string]struct{} {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	components := o.pending[cr]
+	_, full := o.full[cr]
+	delete(o.pending, cr)
+	delete(o.full, cr)
+	if full || len(components) == 0 {
+		return nil
+	}
+	return components
+}
+
+// reconcileFully remembers that all components of the custom resource instance have to be reconciled
+func (o *ownedObjects) reconcileFully(cr types.NamespacedName) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.full[cr] = struct{}{}
+}
+
+// reconcileComponent remembers that the component of the custom resource instance has to be reconciled
+func (o *ownedObjects) reconcileComponent(cr types.NamespacedName, component string) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.pending[cr] == nil {
+		o.pending[cr] = map[string]struct{}{}
+	}
+	o.pending[cr][component] = struct{}{}
+}
+
+// enqueue returns requests for the custom resource instance which manages the object
+// and remembers the component which has to be reconciled
+func (o *ownedObjects) enqueue(key objectKey, controller *types.NamespacedName) []reconcile.Request {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if owner, ok := o.owners[key]; ok {
+		if o.pending[owner.cr] == nil {
+			o.pending[owner.cr] = map[string]struct{}{}
+		}
+		o.pending[owner.cr][owner.component] = struct{}{}
+		return []reconcile.Request{{NamespacedName: owner.cr}}
+	}
+	// The object is not known yet (e.g. right after the start of the operator),
+	// so the custom resource instance which controls it is reconciled completely
+	if controller != nil {
+		o.full[*controller] = struct{}{}
+		return []reconcile.Request{{NamespacedName: *controller}}
+	}
+	return nil
+}
+
+// mapOwnedObject returns a function which maps changed objects to requests of custom resource instances
+// which manage them
+func (o *ownedObjects) mapOwnedObject(scheme *runtime.Scheme) handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil
+		}
+		key := objectKey{
+			GroupKind:      gvk.GroupKind(),
+			NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+		var controller *types.NamespacedName
+		if ref := metav1.GetControllerOf(obj); ref != nil &&
+			ref.Kind == "PlatformMonitoring" && ref.APIVersion == qubershiporgv1.SchemeGroupVersion.String() {
+			// Owner references can point only to objects in the same namespace
+			controller = &types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}
+		}
+		return o.enqueue(key, controller)
+	}
+}
+
+// driftPredicate passes changes of fields owned by the operator made by other field managers.
+// Changes of status and fields which are not owned by the operator are ignored.
+func driftPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			// Objects are created by the operator, also all objects are listed after the start of the operator
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return utils.OwnedFieldsChanged(e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// watchOwnedObjects adds watches of objects created by components to the controller.
+// Objects which API is not available in the cluster are not watched.
+func (r *PlatformMonitoringReconciler) watchOwnedObjects(b *builder.Builder, mgr ctrl.Manager) *builder.Builder {
+	for _, obj := range watchedObjects {
+		gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+		if err != nil {
+			r.Log.Info("Skip watching of unknown type: " + err.Error())
+			continue
+		}
+		if _, err = mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			r.Log.V(1).Info("Skip watching of "+gvk.Kind+", API is not available", "error", err.Error())
+			continue
+		}
+		opts := []builder.WatchesOption{builder.WithPredicates(driftPredicate())}
+		switch obj.(type) {
+		case *corev1.Secret, *corev1.Service:
+			// Secrets and Services of the whole cluster are watched, so only their metadata is cached.
+			// Managed fields in metadata are enough to detect changes made by other managers.
+			opts = append(opts, builder.OnlyMetadata)
+		}
+		b = b.Watches(obj, handler.EnqueueRequestsFromMapFunc(r.owned.mapOwnedObject(mgr.GetScheme())), opts...)
+	}
+	return b
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qubershiporgv1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestOwnedObjects(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{appsv1.SchemeGroupVersion, corev1.SchemeGroupVersion})
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithRESTMapper(mapper).Build()
+	cr := &qubershiporgv1.PlatformMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"}}
+	crKey := types.NamespacedName{Namespace: "monitoring", Name: "platformmonitoring"}
+	ctx := context.Background()
+
+	tracker := utils.NewResourceTracker(cr, nil)
+	assert.NoError(t, tracker.Client(c, grafanaComponent).Create(ctx,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "grafana-deployment", Namespace: "monitoring"}}))
+	assert.NoError(t, tracker.Client(c, pushgatewayComponent).Create(ctx,
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "pushgateway", Namespace: "monitoring"}}))
+
+	(&utils.ComponentReconciler{Client: tracker.Client(c, grafanaComponent)}).WatchResource(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "loki-credentials", Namespace: "monitoring"}})
+
+	owned := newOwnedObjects()
+	owned.update(crKey, []string{grafanaComponent, pushgatewayComponent}, tracker)
+	mapFunc := owned.mapOwnedObject(clientgoscheme.Scheme)
+
+	t.Run("Test managed object", func(t *testing.T) {
+		requests := mapFunc(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "grafana-deployment", Namespace: "monitoring"}})
+		assert.Equal(t, []reconcile.Request{{NamespacedName: crKey}}, requests)
+		assert.Equal(t, map[string]struct{}{grafanaComponent: {}}, owned.take(crKey))
+		assert.Nil(t, owned.take(crKey))
+	})
+	t.Run("Test object read by component", func(t *testing.T) {
+		requests := mapFunc(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "loki-credentials", Namespace: "monitoring"}})
+		assert.Equal(t, []reconcile.Request{{NamespacedName: crKey}}, requests)
+		assert.Equal(t, map[string]struct{}{grafanaComponent: {}}, owned.take(crKey))
+	})
+	t.Run("Test unknown object", func(t *testing.T) {
+		assert.Empty(t, mapFunc(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring"}}))
+		// The object with the same name but another kind is not managed
+		assert.Empty(t, mapFunc(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "grafana-deployment", Namespace: "monitoring"}}))
+	})
+	t.Run("Test unknown object controlled by custom resource", func(t *testing.T) {
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: qubershiporgv1.SchemeGroupVersion.String(),
+				Kind:       "PlatformMonitoring",
+				Name:       "platformmonitoring",
+				Controller: ptr.To(true),
+			}},
+		}}
+		mapFunc(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "pushgateway", Namespace: "monitoring"}})
+		assert.Equal(t, []reconcile.Request{{NamespacedName: crKey}}, mapFunc(ctx, service))
+		// All components are reconciled
+		assert.Nil(t, owned.take(crKey))
+	})
+	t.Run("Test objects of reconciled components are replaced", func(t *testing.T) {
+		owned.update(crKey, []string{pushgatewayComponent}, utils.NewResourceTracker(cr, nil))
+		assert.Empty(t, mapFunc(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "pushgateway", Namespace: "monitoring"}}))
+		assert.NotEmpty(t, mapFunc(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "grafana-deployment", Namespace: "monitoring"}}))
+	})
+}
+
+func TestDriftPredicate(t *testing.T) {
+	p := driftPredicate()
+	applied := metav1.NewTime(time.Now().Add(-time.Minute))
+	edited := metav1.NewTime(time.Now())
+	deployment := func(generation int64, entries ...metav1.ManagedFieldsEntry) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "grafana-deployment", Namespace: "monitoring", Generation: generation, ManagedFields: entries,
+		}}
+	}
+	operatorEntry := func(time *metav1.Time, raw string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{Manager: utils.FieldManager, Operation: metav1.ManagedFieldsOperationApply, Time: time,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(raw)}}
+	}
+	const owned = `{"f:spec":{"f:replicas":{},"f:template":{}}}`
+	kubectlEntry := metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &edited,
+		FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)}}
+	hpaEntry := metav1.ManagedFieldsEntry{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Time: &edited,
+		FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)}, Subresource: "scale"}
+	statusEntry := metav1.ManagedFieldsEntry{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Time: &edited, Subresource: "status"}
+
+	assert.False(t, p.Create(event.CreateEvent{Object: deployment(1)}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: deployment(1)}))
+	assert.True(t, p.Update(event.UpdateEvent{
+		ObjectOld: deployment(1, operatorEntry(&applied, owned)),
+		ObjectNew: deployment(2, operatorEntry(&applied, `{"f:spec":{"f:template":{}}}`), kubectlEntry),
+	}), "manual change of the field owned by the operator")
+	assert.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: deployment(1, operatorEntry(&applied, `{"f:spec":{"f:template":{}}}`)),
+		ObjectNew: deployment(2, operatorEntry(&applied, `{"f:spec":{"f:template":{}}}`), hpaEntry),
+	}), "change of the field which is not owned by the operator")
+	assert.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: deployment(1, operatorEntry(&applied, owned)),
+		ObjectNew: deployment(2, operatorEntry(&edited, `{"f:spec":{"f:template":{}}}`)),
+	}), "change applied by the operator")
+	assert.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: deployment(1, operatorEntry(&applied, owned)),
+		ObjectNew: deployment(1, operatorEntry(&applied, owned), statusEntry),
+	}), "change of the status")
+	secret := func(entries ...metav1.ManagedFieldsEntry) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "grafana-admin-credentials", Namespace: "monitoring", ManagedFields: entries}}
+	}
+	assert.True(t, p.Update(event.UpdateEvent{
+		ObjectOld: secret(operatorEntry(&applied, `{"f:data":{"f:password":{}}}`)),
+		ObjectNew: secret(operatorEntry(&applied, `{"f:data":{}}`), kubectlEntry),
+	}), "manual change of the object watched by metadata")
+}
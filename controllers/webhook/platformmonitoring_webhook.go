@@ -0,0 +1,701 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/victoriametrics"
+	"github.com/VictoriaMetrics/metricsql"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// vmRetentionPeriodRegexp matches values of the -retentionPeriod flag of VictoriaMetrics.
+// A value without suffix is counted in months.
+var vmRetentionPeriodRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(s|m|h|d|w|y)?$`)
+
+// +kubebuilder:webhook:path=/validate-monitoring-qubership-org-v1alpha1-platformmonitoring,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.qubership.org,resources=platformmonitorings,verbs=create;update,versions=v1alpha1,name=vplatformmonitoring.monitoring.qubership.org,admissionReviewVersions=v1
+
+// PlatformMonitoringWebhook validates PlatformMonitoring on admission, so invalid values are rejected
+// before reconciliation. Defaults (e.g. images of the operator version or switching off Prometheus when
+// the VictoriaMetrics stack is installed) are not written to the stored object, otherwise they would stay
+// after upgrades of the operator and changes of other fields. They are filled in memory during reconciliation,
+// and effective values which differ from the spec are returned as warnings.
+type PlatformMonitoringWebhook struct{}
+
+// SetupWithManager registers the validating webhook of PlatformMonitoring in the webhook server of the manager.
+func (w *PlatformMonitoringWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.PlatformMonitoring{}).
+		WithValidator(w).
+		Complete()
+}
+
+// ValidateCreate validates the created object.
+func (w *PlatformMonitoringWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*v1alpha1.PlatformMonitoring)
+	if !ok {
+		return nil, fmt.Errorf("expected PlatformMonitoring, but got %T", obj)
+	}
+	return Validate(cr)
+}
+
+// ValidateUpdate validates the new state of the updated object.
+// Updates which don't change the spec (e.g. of finalizers or labels) are allowed for objects created
+// before the webhook was enabled even if their spec is invalid, invalid values are reported as warnings.
+func (w *PlatformMonitoringWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCR, ok := oldObj.(*v1alpha1.PlatformMonitoring)
+	if !ok {
+		return nil, fmt.Errorf("expected PlatformMonitoring, but got %T", oldObj)
+	}
+	cr, ok := newObj.(*v1alpha1.PlatformMonitoring)
+	if !ok {
+		return nil, fmt.Errorf("expected PlatformMonitoring, but got %T", newObj)
+	}
+	// Objects which are being deleted must not be blocked, otherwise the finalizer can't be removed
+	if !cr.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	warnings, err := Validate(cr)
+	if err != nil && equality.Semantic.DeepEqual(oldCR.Spec, cr.Spec) {
+		return append(warnings, err.Error()), nil
+	}
+	return warnings, err
+}
+
+// ValidateDelete allows deletion of any object.
+func (w *PlatformMonitoringWebhook) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// Validate checks values which can't be checked by the OpenAPI schema of the CRD.
+// Returns warnings about deprecated fields and an Invalid error if the spec contains invalid values
+// or impossible combinations of parameters.
+func Validate(cr *v1alpha1.PlatformMonitoring) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	if auth := cr.Spec.Auth; auth != nil {
+		if auth.ClientID != "" {
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated and ignored, store the client ID in the %s Secret as GF_AUTH_GENERIC_OAUTH_CLIENT_ID",
+				spec.Child("auth", "clientId"), utils.GrafanaExtraVarsSecret))
+		}
+		if auth.ClientSecret != "" {
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated and ignored, store the client secret in the %s Secret as GF_AUTH_GENERIC_OAUTH_CLIENT_SECRET",
+				spec.Child("auth", "clientSecret"), utils.GrafanaExtraVarsSecret))
+		}
+	}
+
+	if p := cr.Spec.Prometheus; p != nil {
+		path := spec.Child("prometheus")
+		if p.IsInstall() && cr.Spec.Victoriametrics != nil && cr.Spec.Victoriametrics.VmOperator.IsInstall() {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored because the VictoriaMetrics stack is installed, Prometheus is not installed",
+				path.Child("install")))
+		}
+		if p.Retention != "" {
+			errs = append(errs, validateDuration(path.Child("retention"), p.Retention)...)
+		}
+		interval, intervalErrs := parseOptionalDuration(path.Child("scrapeInterval"), p.ScrapeInterval)
+		timeout, timeoutErrs := parseOptionalDuration(path.Child("scrapeTimeout"), p.ScrapeTimeout)
+		_, evaluationErrs := parseOptionalDuration(path.Child("evaluationInterval"), p.EvaluationInterval)
+		errs = append(errs, intervalErrs...)
+		errs = append(errs, timeoutErrs...)
+		errs = append(errs, evaluationErrs...)
+		if interval > 0 && timeout > interval {
+			errs = append(errs, field.Invalid(path.Child("scrapeTimeout"), *p.ScrapeTimeout,
+				fmt.Sprintf("must not be greater than %s (%s)", path.Child("scrapeInterval"), *p.ScrapeInterval)))
+		}
+	}
+
+	if vm := cr.Spec.Victoriametrics; vm != nil {
+		path := spec.Child("victoriametrics")
+		if vm.VmSingle.RetentionPeriod != "" && !vmRetentionPeriodRegexp.MatchString(vm.VmSingle.RetentionPeriod) {
+			errs = append(errs, field.Invalid(path.Child("vmSingle", "retentionPeriod"), vm.VmSingle.RetentionPeriod,
+				"must be a number of months or a number with one of suffixes s, m, h, d, w, y"))
+		}
+		if vm.VmCluster.RetentionPeriod != "" && !vmRetentionPeriodRegexp.MatchString(vm.VmCluster.RetentionPeriod) {
+			errs = append(errs, field.Invalid(path.Child("vmCluster", "retentionPeriod"), vm.VmCluster.RetentionPeriod,
+				"must be a number of months or a number with one of suffixes s, m, h, d, w, y"))
+		}
+		if vm.VmSingle.IsInstall() && vm.VmCluster.IsInstall() {
+			errs = append(errs, field.Forbidden(path.Child("vmCluster", "install"),
+				fmt.Sprintf("VmCluster can't be installed together with VmSingle, set %s to false to switch to VmCluster",
+					path.Child("vmSingle", "install"))))
+		}
+		if vm.VmReplicas != nil && !vm.VmCluster.IsInstall() {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored because VmCluster is not installed", path.Child("vmReplicas")))
+		}
+		if len(vm.Tenants) > 0 {
+			if !vm.VmCluster.IsInstall() {
+				warnings = append(warnings, fmt.Sprintf("%s are ignored because VmCluster is not installed", path.Child("tenants")))
+			} else if !vm.VmUser.IsInstall() || !vm.VmAuth.IsInstall() {
+				warnings = append(warnings, fmt.Sprintf("VMUsers of %s are not created because vmUser or vmAuth is not installed", path.Child("tenants")))
+			}
+			errs = append(errs, validateVmTenants(path.Child("tenants"), vm)...)
+		}
+		if m := vm.Migration; m != nil {
+			if m.Source == v1alpha1.VmMigrationSourcePrometheus && victoriametrics.GetMigrationDestination(cr) == "" {
+				warnings = append(warnings, fmt.Sprintf("%s is ignored because neither VmSingle nor VmCluster is installed", path.Child("migration")))
+			}
+			if m.Source == v1alpha1.VmMigrationSourcePrometheus && m.Match != "" {
+				warnings = append(warnings, fmt.Sprintf("%s is ignored for Prometheus, use remote-read-filter-label and remote-read-filter-label-value in %s",
+					path.Child("migration", "match"), path.Child("migration", "extraArgs")))
+			}
+			errs = append(errs, validateVmMigration(path.Child("migration"), vm)...)
+		}
+		if vm.Backup != nil {
+			if !vm.VmSingle.IsInstall() && !vm.VmCluster.IsInstall() {
+				warnings = append(warnings, fmt.Sprintf("%s is ignored because neither VmSingle nor VmCluster is installed", path.Child("backup")))
+			}
+			errs = append(errs, validateVmBackup(path.Child("backup"), vm)...)
+		}
+		if vm.RetentionPolicy != nil {
+			if !vm.VmSingle.IsInstall() && !vm.VmCluster.IsInstall() {
+				warnings = append(warnings, fmt.Sprintf("%s is ignored because neither VmSingle nor VmCluster is installed", path.Child("retentionPolicy")))
+			} else {
+				policyErrs, policyWarnings := validateVmRetentionPolicy(path, cr)
+				errs = append(errs, policyErrs...)
+				warnings = append(warnings, policyWarnings...)
+			}
+		}
+		agent := path.Child("vmAgent")
+		if vm.VmAgent.ScrapeInterval != "" {
+			errs = append(errs, validateDuration(agent.Child("scrapeInterval"), vm.VmAgent.ScrapeInterval)...)
+		}
+		minInterval, minErrs := parseOptionalDuration(agent.Child("minScrapeInterval"), vm.VmAgent.MinScrapeInterval)
+		maxInterval, maxErrs := parseOptionalDuration(agent.Child("maxScrapeInterval"), vm.VmAgent.MaxScrapeInterval)
+		errs = append(errs, minErrs...)
+		errs = append(errs, maxErrs...)
+		if minInterval > 0 && maxInterval > 0 && minInterval > maxInterval {
+			errs = append(errs, field.Invalid(agent.Child("minScrapeInterval"), *vm.VmAgent.MinScrapeInterval,
+				fmt.Sprintf("must not be greater than %s (%s)", agent.Child("maxScrapeInterval"), *vm.VmAgent.MaxScrapeInterval)))
+		}
+		if vm.VmAlert.EvaluationInterval != "" {
+			errs = append(errs, validateDuration(path.Child("vmAlert", "evaluationInterval"), vm.VmAlert.EvaluationInterval)...)
+		}
+	}
+
+	if g := cr.Spec.Grafana; g != nil {
+		errs = append(errs, validateGrafanaFolders(spec.Child("grafana", "folders"), g.Folders, cr.Spec.Auth != nil)...)
+		errs = append(errs, validateGrafanaDatasources(spec.Child("grafana", "datasources"), g.Datasources)...)
+	}
+
+	if pr := cr.Spec.PrometheusRules; pr != nil {
+		path := spec.Child("prometheusRules")
+		errs = append(errs, validateRuleOverrides(path.Child("override"), pr.Override)...)
+		errs = append(errs, validateRuleGroups(path.Child("groups"), pr.Groups)...)
+	}
+
+	if gd := cr.Spec.GrafanaDashboards; gd != nil {
+		known := knownDashboards()
+		for i, name := range gd.List {
+			if _, ok := known[name]; !ok {
+				errs = append(errs, field.NotSupported(spec.Child("grafanaDashboards", "list").Index(i), name, sortedKeys(known)))
+			}
+		}
+		errs = append(errs, validateDashboardSources(spec.Child("grafanaDashboards", "sources"), gd.Sources)...)
+		if gd.IsInstall() && cr.Spec.Grafana != nil && !cr.Spec.Grafana.IsInstall() {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored because Grafana is not installed", spec.Child("grafanaDashboards")))
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, errors.NewInvalid(v1alpha1.SchemeGroupVersion.WithKind("PlatformMonitoring").GroupKind(), cr.GetName(), errs)
+	}
+	return warnings, nil
+}
+
+// validateDuration checks that the value is a duration in the format of Prometheus, e.g. 1h30m
+func validateDuration(path *field.Path, value string) field.ErrorList {
+	if _, err := model.ParseDuration(value); err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+	return nil
+}
+
+// parseOptionalDuration parses the duration in the format of Prometheus if it is set
+func parseOptionalDuration(path *field.Path, value *string) (time.Duration, field.ErrorList) {
+	if value == nil || *value == "" {
+		return 0, nil
+	}
+	d, err := model.ParseDuration(*value)
+	if err != nil {
+		return 0, field.ErrorList{field.Invalid(path, *value, err.Error())}
+	}
+	return time.Duration(d), nil
+}
+
+// validateDashboardSources checks that each source has a unique name and exactly one of configMaps, path and url
+func validateDashboardSources(path *field.Path, sources []v1alpha1.DashboardSource) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(sources))
+	for i, source := range sources {
+		p := path.Index(i)
+		if _, ok := names[source.Name]; ok {
+			errs = append(errs, field.Duplicate(p.Child("name"), source.Name))
+		}
+		names[source.Name] = struct{}{}
+		var set []string
+		if source.ConfigMaps != nil {
+			set = append(set, "configMaps")
+			if _, err := metav1.LabelSelectorAsSelector(source.ConfigMaps); err != nil {
+				errs = append(errs, field.Invalid(p.Child("configMaps"), source.ConfigMaps, err.Error()))
+			}
+		}
+		if source.Path != "" {
+			set = append(set, "path")
+			if !filepath.IsAbs(source.Path) {
+				errs = append(errs, field.Invalid(p.Child("path"), source.Path, "must be an absolute path"))
+			}
+		}
+		if source.URL != "" {
+			set = append(set, "url")
+			if err := utils.ValidateClusterLocalURL(source.URL); err != nil {
+				errs = append(errs, field.Invalid(p.Child("url"), source.URL, err.Error()))
+			}
+		}
+		if len(set) != 1 {
+			errs = append(errs, field.Invalid(p, strings.Join(set, ", "), "exactly one of configMaps, path and url must be set"))
+		}
+	}
+	return errs
+}
+
+// validateGrafanaFolders checks that titles of folders are unique, each dashboard is only in one folder
+// and each permission has exactly one of team, group and role
+func validateGrafanaFolders(path *field.Path, folders []v1alpha1.GrafanaFolder, hasAuth bool) field.ErrorList {
+	var errs field.ErrorList
+	titles := make(map[string]struct{}, len(folders))
+	dashboards := make(map[string]struct{})
+	for i, folder := range folders {
+		p := path.Index(i)
+		if _, ok := titles[folder.Title]; ok {
+			errs = append(errs, field.Duplicate(p.Child("title"), folder.Title))
+		}
+		titles[folder.Title] = struct{}{}
+		for j, name := range folder.Dashboards {
+			if _, ok := dashboards[name]; ok {
+				errs = append(errs, field.Duplicate(p.Child("dashboards").Index(j), name))
+			}
+			dashboards[name] = struct{}{}
+		}
+		for j, permission := range folder.Permissions {
+			pp := p.Child("permissions").Index(j)
+			var set []string
+			if permission.Team != "" {
+				set = append(set, "team")
+			}
+			if permission.Group != "" {
+				set = append(set, "group")
+				if !hasAuth {
+					errs = append(errs, field.Invalid(pp.Child("group"), permission.Group, "OAuth groups require spec.auth"))
+				}
+			}
+			if permission.Role != "" {
+				set = append(set, "role")
+			}
+			if len(set) != 1 {
+				errs = append(errs, field.Invalid(pp, strings.Join(set, ", "), "exactly one of team, group and role must be set"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateGrafanaDatasources checks that names and UIDs of datasources are unique, each datasource has
+// exactly one of url and discovery and only one datasource is default
+func validateGrafanaDatasources(path *field.Path, datasources []v1alpha1.GrafanaDatasource) field.ErrorList {
+	var errs field.ErrorList
+	names := map[string]struct{}{
+		"Platform Monitoring Prometheus": {},
+		"Platform Monitoring Promxy":     {},
+	}
+	uids := make(map[string]struct{}, len(datasources))
+	defaults := 0
+	for i, ds := range datasources {
+		p := path.Index(i)
+		if _, ok := names[ds.Name]; ok {
+			errs = append(errs, field.Duplicate(p.Child("name"), ds.Name))
+		}
+		names[ds.Name] = struct{}{}
+		if ds.UID != "" {
+			if _, ok := uids[ds.UID]; ok {
+				errs = append(errs, field.Duplicate(p.Child("uid"), ds.UID))
+			}
+			uids[ds.UID] = struct{}{}
+		}
+		if ds.IsDefault {
+			defaults++
+			if defaults > 1 {
+				errs = append(errs, field.Invalid(p.Child("isDefault"), ds.IsDefault, "only one datasource can be default"))
+			}
+		}
+		var set []string
+		if ds.URL != "" {
+			set = append(set, "url")
+		}
+		if d := ds.Discovery; d != nil {
+			set = append(set, "discovery")
+			if _, err := metav1.LabelSelectorAsSelector(&d.Selector); err != nil {
+				errs = append(errs, field.Invalid(p.Child("discovery", "selector"), d.Selector, err.Error()))
+			}
+			if d.NamespaceSelector != nil {
+				if _, err := metav1.LabelSelectorAsSelector(d.NamespaceSelector); err != nil {
+					errs = append(errs, field.Invalid(p.Child("discovery", "namespaceSelector"), d.NamespaceSelector, err.Error()))
+				}
+			}
+		}
+		if len(set) != 1 {
+			errs = append(errs, field.Invalid(p, strings.Join(set, ", "), "exactly one of url and discovery must be set"))
+		}
+	}
+	return errs
+}
+
+// validateVmTenants checks that names and IDs of tenants are unique and differ from the tenant of VmCluster,
+// which receives metrics of namespaces not selected by tenants
+func validateVmTenants(path *field.Path, vm *v1alpha1.Victoriametrics) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(vm.Tenants))
+	ids := make(map[string]struct{}, len(vm.Tenants))
+	defaultTenant := victoriametrics.GetVmclusterTenant(vm.VmCluster)
+	for i, tenant := range vm.Tenants {
+		p := path.Index(i)
+		if _, ok := names[tenant.Name]; ok {
+			errs = append(errs, field.Duplicate(p.Child("name"), tenant.Name))
+		}
+		names[tenant.Name] = struct{}{}
+		if _, ok := ids[tenant.ID]; ok {
+			errs = append(errs, field.Duplicate(p.Child("id"), tenant.ID))
+		}
+		ids[tenant.ID] = struct{}{}
+		if tenant.ID == defaultTenant {
+			errs = append(errs, field.Invalid(p.Child("id"), tenant.ID, "must differ from the tenant of vmCluster"))
+		}
+		if tenant.NamespaceSelector != nil {
+			if !utils.PrivilegedRights {
+				errs = append(errs, field.Forbidden(p.Child("namespaceSelector"),
+					"requires privileged rights of the operator, because namespaces are listed in the whole cluster"))
+			} else if _, err := metav1.LabelSelectorAsSelector(tenant.NamespaceSelector); err != nil {
+				errs = append(errs, field.Invalid(p.Child("namespaceSelector"), tenant.NamespaceSelector, err.Error()))
+			}
+		}
+	}
+	return errs
+}
+
+// validateVmMigration checks the time range of migrated data and that VmSingle is migrated only to VmCluster
+func validateVmMigration(path *field.Path, vm *v1alpha1.Victoriametrics) field.ErrorList {
+	var errs field.ErrorList
+	m := vm.Migration
+	if m.Source == v1alpha1.VmMigrationSourceVMSingle && (!vm.VmCluster.IsInstall() || vm.VmSingle.IsInstall()) {
+		errs = append(errs, field.Invalid(path.Child("source"), m.Source,
+			fmt.Sprintf("VmSingle can be migrated only to VmCluster, set %s to true and %s to false",
+				path.Root().Child("victoriametrics", "vmCluster", "install"), path.Root().Child("victoriametrics", "vmSingle", "install"))))
+	}
+	start, err := time.Parse(time.RFC3339, m.TimeStart)
+	if err != nil {
+		errs = append(errs, field.Invalid(path.Child("timeStart"), m.TimeStart, "must be a time in RFC3339 format"))
+	}
+	if m.TimeEnd != "" {
+		end, endErr := time.Parse(time.RFC3339, m.TimeEnd)
+		switch {
+		case endErr != nil:
+			errs = append(errs, field.Invalid(path.Child("timeEnd"), m.TimeEnd, "must be a time in RFC3339 format"))
+		case err == nil && !end.After(start):
+			errs = append(errs, field.Invalid(path.Child("timeEnd"), m.TimeEnd,
+				fmt.Sprintf("must be after %s (%s)", path.Child("timeStart"), m.TimeStart)))
+		}
+	}
+	return errs
+}
+
+// vmBackupGeneratedFlags are flags of vmbackup which are set by the operator and can't be overridden
+var vmBackupGeneratedFlags = []string{"dst", "origin", "storageDataPath", "snapshot.createURL"}
+
+// validateVmBackup checks the destination, the schedule and the restore of backups
+// and that the installed storage keeps data on persistent volumes
+func validateVmBackup(path *field.Path, vm *v1alpha1.Victoriametrics) field.ErrorList {
+	var errs field.ErrorList
+	b := vm.Backup
+	switch {
+	case b.S3 == nil && b.Filesystem == nil:
+		errs = append(errs, field.Required(path, "one of s3 and filesystem must be set"))
+	case b.S3 != nil && b.Filesystem != nil:
+		errs = append(errs, field.Forbidden(path.Child("filesystem"), "can't be set together with s3"))
+	}
+	if fs := b.Filesystem; fs != nil {
+		sources := 0
+		for _, set := range []bool{fs.Volume.HostPath != nil, fs.Volume.PersistentVolumeClaim != nil, fs.Volume.NFS != nil} {
+			if set {
+				sources++
+			}
+		}
+		switch {
+		case sources == 0:
+			errs = append(errs, field.Required(path.Child("filesystem", "volume"), "one of hostPath, persistentVolumeClaim and nfs must be set"))
+		case sources > 1:
+			errs = append(errs, field.Forbidden(path.Child("filesystem", "volume"), "only one of hostPath, persistentVolumeClaim and nfs can be set"))
+		}
+	}
+	if b.S3 != nil && (b.S3.AccessKey == nil) != (b.S3.SecretKey == nil) {
+		errs = append(errs, field.Required(path.Child("s3"), "accessKey and secretKey must be set together"))
+	}
+	if fields := strings.Fields(b.Schedule); !strings.HasPrefix(b.Schedule, "@") && len(fields) != 5 {
+		errs = append(errs, field.Invalid(path.Child("schedule"), b.Schedule, "must be a cron expression with 5 fields or a macro like @daily"))
+	}
+	for _, flag := range vmBackupGeneratedFlags {
+		if _, ok := b.ExtraArgs[flag]; ok {
+			errs = append(errs, field.Forbidden(path.Child("extraArgs").Key(flag), "is set by the operator"))
+		}
+	}
+	if r := b.Restore; r != nil {
+		if b.RestoreImage == "" {
+			errs = append(errs, field.Required(path.Child("restoreImage"), "must be set to restore the storage"))
+		}
+		if r.Generation >= b.GetGenerations() {
+			errs = append(errs, field.Invalid(path.Child("restore", "generation"), r.Generation,
+				fmt.Sprintf("must be less than the number of generations %d", b.GetGenerations())))
+		}
+	}
+	vmPath := path.Root().Child("victoriametrics")
+	switch {
+	case vm.VmCluster.IsInstall():
+		if vm.VmCluster.VmStorage != nil && vm.VmCluster.VmStorage.Storage == nil {
+			errs = append(errs, field.Required(vmPath.Child("vmCluster", "vmstorage", "storage"),
+				"persistent volumes of vmstorage are required for backups"))
+		}
+	case vm.VmSingle.IsInstall():
+		if vm.VmSingle.Storage == nil {
+			errs = append(errs, field.Required(vmPath.Child("vmSingle", "storage"), "a persistent volume of vmsingle is required for backups"))
+		} else if strings.TrimSpace(vm.VmSingle.StorageDataPath) != "" {
+			errs = append(errs, field.Forbidden(vmPath.Child("vmSingle", "storageDataPath"), "disables the persistent volume of vmsingle which is required for backups"))
+		}
+	}
+	return errs
+}
+
+// validateVmRetentionPolicy checks series filters and periods of the retention policy and that the image
+// of the installed storage supports them. Retention filters and downsampling require the enterprise version
+// of VictoriaMetrics, filters of downsampling levels require v1.100.0 or newer.
+// Images without the version in the tag are not checked.
+func validateVmRetentionPolicy(vmPath *field.Path, cr *v1alpha1.PlatformMonitoring) (field.ErrorList, []string) {
+	var errs field.ErrorList
+	var warnings []string
+	vm := cr.Spec.Victoriametrics
+	policy := vm.RetentionPolicy
+	path := vmPath.Child("retentionPolicy")
+
+	// The storage which receives flags of the retention policy
+	imagePath, image, extraArgs := vmPath.Child("vmSingle", "image"), vm.VmSingle.Image, vm.VmSingle.ExtraArgs
+	extraArgsPath := vmPath.Child("vmSingle", "extraArgs")
+	if vm.VmCluster.IsInstall() {
+		imagePath, image = vmPath.Child("vmCluster", "vmStorageImage"), vm.VmCluster.VmStorageImage
+		extraArgsPath, extraArgs = vmPath.Child("vmCluster", "vmstorage", "extraArgs"), nil
+		if vm.VmCluster.VmStorage != nil {
+			extraArgs = vm.VmCluster.VmStorage.ExtraArgs
+		}
+	} else if len(policy.Tenants) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s are ignored because VmCluster is not installed", path.Child("tenants")))
+	}
+	for _, flag := range []string{victoriametrics.RetentionFilterFlag, victoriametrics.DownsamplingPeriodFlag} {
+		if _, ok := extraArgs[flag]; ok {
+			errs = append(errs, field.Forbidden(extraArgsPath.Key(flag), fmt.Sprintf("is set by the operator from %s", path)))
+		}
+	}
+
+	retentionPeriod := victoriametrics.GetRetentionPeriod(cr)
+	retention, _ := victoriametrics.ParseRetentionPeriod(retentionPeriod)
+	validateRetention := func(p *field.Path, value string) {
+		period, err := victoriametrics.ParseRetentionPeriod(value)
+		switch {
+		case err != nil:
+			errs = append(errs, field.Invalid(p, value, "must be a number of months or a number with one of suffixes s, m, h, d, w, y"))
+		case retention > 0 && period > retention:
+			errs = append(errs, field.Invalid(p, value, fmt.Sprintf("must not be greater than the retention period %s", retentionPeriod)))
+		}
+	}
+	for i, f := range policy.Filters {
+		errs = append(errs, validateSeriesFilter(path.Child("filters").Index(i).Child("filter"), f.Filter)...)
+		validateRetention(path.Child("filters").Index(i).Child("retentionPeriod"), f.RetentionPeriod)
+	}
+	tenants := make(map[string]struct{}, len(policy.Tenants))
+	for i, t := range policy.Tenants {
+		p := path.Child("tenants").Index(i)
+		if _, ok := tenants[t.Tenant]; ok {
+			errs = append(errs, field.Duplicate(p.Child("tenant"), t.Tenant))
+		}
+		tenants[t.Tenant] = struct{}{}
+		validateRetention(p.Child("retentionPeriod"), t.RetentionPeriod)
+	}
+
+	// Intervals of downsampling and the effective deduplication interval must be multiples of each other
+	var intervals []time.Duration
+	dedup := extraArgs[victoriametrics.DedupIntervalFlag]
+	if !vm.VmCluster.IsInstall() {
+		dedup = victoriametrics.GetVmSingleDedupInterval(cr)
+	}
+	if d, err := model.ParseDuration(dedup); err == nil && d > 0 {
+		intervals = append(intervals, time.Duration(d))
+	}
+	for i, d := range policy.Downsampling {
+		p := path.Child("downsampling").Index(i)
+		if d.Filter != "" {
+			errs = append(errs, validateSeriesFilter(p.Child("filter"), d.Filter)...)
+		}
+		offset, offsetErrs := parseOptionalDuration(p.Child("offset"), &d.Offset)
+		interval, intervalErrs := parseOptionalDuration(p.Child("interval"), &d.Interval)
+		errs = append(errs, offsetErrs...)
+		errs = append(errs, intervalErrs...)
+		if len(offsetErrs)+len(intervalErrs) > 0 {
+			continue
+		}
+		if interval == 0 {
+			// filter:0s:0s excludes matched series from downsampling
+			if offset != 0 || d.Filter == "" {
+				errs = append(errs, field.Invalid(p.Child("interval"), d.Interval, "must be positive, only series matched by the filter can be excluded with 0s:0s"))
+			}
+			continue
+		}
+		for _, other := range intervals {
+			if max(interval, other)%min(interval, other) != 0 {
+				errs = append(errs, field.Invalid(p.Child("interval"), d.Interval,
+					fmt.Sprintf("must be a multiple or a divisor of other intervals of downsampling and dedup.minScrapeInterval, conflicts with %s", model.Duration(other))))
+				break
+			}
+		}
+		intervals = append(intervals, interval)
+	}
+
+	// Features of the image
+	retentionFilters := len(policy.Filters) > 0 || (vm.VmCluster.IsInstall() && len(policy.Tenants) > 0)
+	if !retentionFilters && len(policy.Downsampling) == 0 {
+		return errs, warnings
+	}
+	features := victoriametrics.GetImageFeatures(image)
+	if !features.Enterprise {
+		errs = append(errs, field.Invalid(imagePath, image, fmt.Sprintf("retention filters and downsampling of %s require the enterprise image of VictoriaMetrics", path)))
+		return errs, warnings
+	}
+	if !features.Versioned {
+		warnings = append(warnings, fmt.Sprintf("the version of %s can't be read from the tag, features of %s are not checked", imagePath, path))
+	}
+	if retentionFilters && features.Versioned && !features.AtLeast(victoriametrics.RetentionFiltersVersion) {
+		errs = append(errs, field.Invalid(imagePath, image, fmt.Sprintf("retention filters require VictoriaMetrics %s or newer", formatVersion(victoriametrics.RetentionFiltersVersion))))
+	}
+	for i, d := range policy.Downsampling {
+		if d.Filter != "" && features.Versioned && !features.AtLeast(victoriametrics.DownsamplingFiltersVersion) {
+			errs = append(errs, field.Invalid(path.Child("downsampling").Index(i).Child("filter"), d.Filter,
+				fmt.Sprintf("filters of downsampling require VictoriaMetrics %s or newer, %s is %s", formatVersion(victoriametrics.DownsamplingFiltersVersion), imagePath, image)))
+		}
+	}
+	licensed := false
+	for _, flag := range []string{"license", "licenseFile", "eula"} {
+		if _, ok := extraArgs[flag]; ok {
+			licensed = true
+		}
+	}
+	if !licensed {
+		warnings = append(warnings, fmt.Sprintf("the enterprise image requires license, licenseFile or eula in %s", extraArgsPath))
+	}
+	return errs, warnings
+}
+
+// validateSeriesFilter checks that the value is a series filter of VictoriaMetrics, e.g. {env="dev"}
+func validateSeriesFilter(path *field.Path, value string) field.ErrorList {
+	expr, err := metricsql.Parse(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+	if _, ok := expr.(*metricsql.MetricExpr); !ok {
+		return field.ErrorList{field.Invalid(path, value, `must be a series filter, e.g. {env="dev"}`)}
+	}
+	return nil
+}
+
+// formatVersion returns the version in the format of tags of VictoriaMetrics, e.g. v1.100.0
+func formatVersion(version [3]int) string {
+	return fmt.Sprintf("v%d.%d.%d", version[0], version[1], version[2])
+}
+
+// validateRuleOverrides checks that each override refers to a group and exactly one of alert and record
+// and has valid durations
+func validateRuleOverrides(path *field.Path, overrides []v1alpha1.PrometheusRule) field.ErrorList {
+	var errs field.ErrorList
+	for i, override := range overrides {
+		p := path.Index(i)
+		if override.Group == "" {
+			errs = append(errs, field.Required(p.Child("group"), "the group of the rule must be set"))
+		}
+		if (override.Alert == "") == (override.Record == "") {
+			errs = append(errs, field.Invalid(p, override.Alert+override.Record, "exactly one of alert and record must be set"))
+		}
+		if override.For != "" {
+			errs = append(errs, validateDuration(p.Child("for"), override.For)...)
+		}
+		if override.KeepFiringFor != "" {
+			errs = append(errs, validateDuration(p.Child("keepFiringFor"), override.KeepFiringFor)...)
+		}
+	}
+	return errs
+}
+
+// validateRuleGroups checks that names of groups are unique and each custom rule has an expression
+// and exactly one of alert and record
+func validateRuleGroups(path *field.Path, groups []v1alpha1.PrometheusRuleGroup) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(groups))
+	for i, group := range groups {
+		p := path.Index(i)
+		if _, ok := names[group.Name]; ok {
+			errs = append(errs, field.Duplicate(p.Child("name"), group.Name))
+		}
+		names[group.Name] = struct{}{}
+		if group.Interval != nil {
+			errs = append(errs, validateDuration(p.Child("interval"), string(*group.Interval))...)
+		}
+		for j, rule := range group.Rules {
+			rp := p.Child("rules").Index(j)
+			if (rule.Alert == "") == (rule.Record == "") {
+				errs = append(errs, field.Invalid(rp, rule.Alert+rule.Record, "exactly one of alert and record must be set"))
+			}
+			if rule.Expr == (intstr.IntOrString{}) || rule.Expr.String() == "" {
+				errs = append(errs, field.Required(rp.Child("expr"), "the expression of the rule must be set"))
+			}
+		}
+	}
+	return errs
+}
+
+// knownDashboards returns names of dashboards which can be installed by the operator
+func knownDashboards() map[string]struct{} {
+	known := make(map[string]struct{}, len(utils.GrafanaKubernetesDashboardsResources))
+	for _, resource := range utils.GrafanaKubernetesDashboardsResources {
+		known[strings.TrimSuffix(resource, ".yaml")] = struct{}{}
+	}
+	return known
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,528 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	v1alpha1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
+	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func newCR(spec v1alpha1.PlatformMonitoringSpec) *v1alpha1.PlatformMonitoring {
+	return &v1alpha1.PlatformMonitoring{
+		ObjectMeta: metav1.ObjectMeta{Name: "platformmonitoring", Namespace: "monitoring"},
+		Spec:       spec,
+	}
+}
+
+func TestEffectiveDefaults(t *testing.T) {
+	cr := newCR(v1alpha1.PlatformMonitoringSpec{
+		Prometheus:      &v1alpha1.Prometheus{Install: ptr.To(true)},
+		Victoriametrics: &v1alpha1.Victoriametrics{VmOperator: v1alpha1.VmOperator{Image: "vmoperator:v0.1"}},
+	})
+	warnings, err := (&PlatformMonitoringWebhook{}).ValidateCreate(context.Background(), cr)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"spec.prometheus.install is ignored because the VictoriaMetrics stack is installed, Prometheus is not installed"},
+		[]string(warnings))
+	// Defaults are not written to the object, so they are not kept when the VictoriaMetrics stack is disabled later
+	assert.True(t, cr.Spec.Prometheus.IsInstall())
+	assert.Empty(t, cr.Spec.Prometheus.Image)
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Test valid spec", func(t *testing.T) {
+		warnings, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Prometheus: &v1alpha1.Prometheus{
+				Retention:      "7d",
+				ScrapeInterval: ptr.To("30s"),
+				ScrapeTimeout:  ptr.To("10s"),
+			},
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmSingle: v1alpha1.VmSingle{RetentionPeriod: "14d"},
+				VmAgent:  v1alpha1.VmAgent{ScrapeInterval: "30s"},
+			},
+			GrafanaDashboards: &v1alpha1.GrafanaDashboards{List: []string{"alerts-overview", "etcd-dashboard"}},
+		}))
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+	t.Run("Test months in retentionPeriod of VmSingle", func(t *testing.T) {
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{VmSingle: v1alpha1.VmSingle{RetentionPeriod: "1"}},
+		}))
+		assert.NoError(t, err)
+	})
+	t.Run("Test invalid values", func(t *testing.T) {
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Prometheus: &v1alpha1.Prometheus{
+				Retention:      "7days",
+				ScrapeInterval: ptr.To("10s"),
+				ScrapeTimeout:  ptr.To("30s"),
+			},
+			Victoriametrics: &v1alpha1.Victoriametrics{
+				VmSingle: v1alpha1.VmSingle{RetentionPeriod: "two weeks"},
+				VmAgent: v1alpha1.VmAgent{
+					ScrapeInterval:    "30 seconds",
+					MinScrapeInterval: ptr.To("1m"),
+					MaxScrapeInterval: ptr.To("30s"),
+				},
+			},
+			GrafanaDashboards: &v1alpha1.GrafanaDashboards{List: []string{"alerts-overview", "unknown-dashboard"}},
+		}))
+		assert.True(t, errors.IsInvalid(err))
+		statusErr := err.(*errors.StatusError)
+		var fields []string
+		for _, cause := range statusErr.ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.prometheus.retention",
+			"spec.prometheus.scrapeTimeout",
+			"spec.victoriametrics.vmSingle.retentionPeriod",
+			"spec.victoriametrics.vmAgent.scrapeInterval",
+			"spec.victoriametrics.vmAgent.minScrapeInterval",
+			"spec.grafanaDashboards.list[1]",
+		}, fields)
+	})
+	t.Run("Test storage backends of VictoriaMetrics", func(t *testing.T) {
+		vmSingle := v1alpha1.VmSingle{Image: "victoriametrics/victoria-metrics:v1.103.0"}
+		vmCluster := v1alpha1.VmCluster{
+			RetentionPeriod: "14d",
+			VmSelectImage:   "victoriametrics/vmselect:v1.103.0-cluster",
+			VmInsertImage:   "victoriametrics/vminsert:v1.103.0-cluster",
+			VmStorageImage:  "victoriametrics/vmstorage:v1.103.0-cluster",
+		}
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{VmSingle: vmSingle, VmCluster: vmCluster},
+		}))
+		assert.True(t, errors.IsInvalid(err))
+		assert.Contains(t, err.Error(), "spec.victoriametrics.vmCluster.install")
+
+		vmSingle.Install = ptr.To(false)
+		warnings, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{VmReplicas: ptr.To[int32](2), VmSingle: vmSingle, VmCluster: vmCluster},
+		}))
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		vmCluster.RetentionPeriod = "two weeks"
+		vmCluster.Install = ptr.To(false)
+		warnings, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Victoriametrics: &v1alpha1.Victoriametrics{VmReplicas: ptr.To[int32](2), VmCluster: vmCluster},
+		}))
+		assert.True(t, errors.IsInvalid(err))
+		assert.Contains(t, err.Error(), "spec.victoriametrics.vmCluster.retentionPeriod")
+		assert.Len(t, warnings, 1)
+	})
+	t.Run("Test tenants of VictoriaMetrics", func(t *testing.T) {
+		vm := &v1alpha1.Victoriametrics{
+			VmSingle: v1alpha1.VmSingle{Install: ptr.To(false)},
+			VmAuth:   v1alpha1.VmAuth{Image: "victoriametrics/vmauth:v1.103.0"},
+			VmCluster: v1alpha1.VmCluster{
+				VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+				VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+				VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+			},
+			Tenants: []v1alpha1.VmTenant{
+				{Name: "team-a", ID: "1", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+				{Name: "team-b", ID: "2:1", Grafana: &v1alpha1.VmTenantGrafana{OrgID: 2}},
+			},
+		}
+		// Namespaces can't be listed without privileged rights
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.ErrorContains(t, err, "spec.victoriametrics.tenants[0].namespaceSelector: Forbidden")
+
+		utils.PrivilegedRights = true
+		defer func() { utils.PrivilegedRights = false }()
+		warnings, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		vm.Tenants = append(vm.Tenants,
+			v1alpha1.VmTenant{Name: "team-a", ID: "1"},
+			v1alpha1.VmTenant{Name: "team-c", ID: "0", NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Exists", Values: []string{"c"}}},
+			}},
+		)
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.victoriametrics.tenants[2].name",
+			"spec.victoriametrics.tenants[2].id",
+			"spec.victoriametrics.tenants[3].id",
+			"spec.victoriametrics.tenants[3].namespaceSelector",
+		}, fields)
+
+		vm.Tenants = vm.Tenants[:2]
+		vm.VmCluster.Install = ptr.To(false)
+		warnings, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "spec.victoriametrics.tenants are ignored")
+	})
+	t.Run("Test data migration of VictoriaMetrics", func(t *testing.T) {
+		vm := &v1alpha1.Victoriametrics{
+			VmSingle: v1alpha1.VmSingle{Install: ptr.To(false), Image: "victoriametrics/victoria-metrics:v1.103.0"},
+			VmCluster: v1alpha1.VmCluster{
+				VmSelectImage:  "victoriametrics/vmselect:v1.103.0-cluster",
+				VmInsertImage:  "victoriametrics/vminsert:v1.103.0-cluster",
+				VmStorageImage: "victoriametrics/vmstorage:v1.103.0-cluster",
+			},
+			Migration: &v1alpha1.VmMigration{
+				Source:    v1alpha1.VmMigrationSourceVMSingle,
+				Image:     "victoriametrics/vmctl:v1.104.0",
+				TimeStart: "2024-01-01T00:00:00Z",
+				TimeEnd:   "2024-06-01T00:00:00Z",
+			},
+		}
+		warnings, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		vm.VmCluster.Install = ptr.To(false)
+		vm.Migration.TimeStart = "2024-01-01"
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.victoriametrics.migration.source",
+			"spec.victoriametrics.migration.timeStart",
+		}, fields)
+
+		vm.Migration.TimeStart = "2024-07-01T00:00:00Z"
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.True(t, errors.IsInvalid(err))
+		fields = nil
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.victoriametrics.migration.source",
+			"spec.victoriametrics.migration.timeEnd",
+		}, fields)
+
+		vm.Migration.Source = v1alpha1.VmMigrationSourcePrometheus
+		vm.Migration.TimeEnd = ""
+		vm.Migration.Match = `{job="node-exporter"}`
+		warnings, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "spec.victoriametrics.migration is ignored")
+		assert.Contains(t, warnings[1], "spec.victoriametrics.migration.match is ignored")
+	})
+	t.Run("Test backups of VictoriaMetrics", func(t *testing.T) {
+		vm := &v1alpha1.Victoriametrics{
+			VmSingle: v1alpha1.VmSingle{Image: "victoriametrics/victoria-metrics:v1.103.0", Storage: &corev1.PersistentVolumeClaimSpec{}},
+			Backup: &v1alpha1.VmBackup{
+				Image:    "victoriametrics/vmbackup:v1.103.0",
+				Schedule: "0 1 * * *",
+				S3:       &v1alpha1.VmBackupS3{Bucket: "backups"},
+			},
+		}
+		warnings, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		vm.VmSingle.Storage = nil
+		vm.Backup.Schedule = "daily"
+		vm.Backup.Filesystem = &v1alpha1.VmBackupFilesystem{}
+		vm.Backup.S3.AccessKey = &corev1.SecretKeySelector{Key: "access"}
+		vm.Backup.ExtraArgs = map[string]string{"dst": "s3://other", "concurrency": "4"}
+		vm.Backup.Restore = &v1alpha1.VmRestore{ID: "first", Generation: 3}
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.victoriametrics.backup.filesystem",
+			"spec.victoriametrics.backup.filesystem.volume",
+			"spec.victoriametrics.backup.s3",
+			"spec.victoriametrics.backup.schedule",
+			"spec.victoriametrics.backup.extraArgs[dst]",
+			"spec.victoriametrics.backup.restoreImage",
+			"spec.victoriametrics.backup.restore.generation",
+			"spec.victoriametrics.vmSingle.storage",
+		}, fields)
+
+		vm.VmSingle.Install = ptr.To(false)
+		vm.Backup = &v1alpha1.VmBackup{Image: "victoriametrics/vmbackup:v1.103.0", Schedule: "@daily", S3: &v1alpha1.VmBackupS3{Bucket: "backups"}}
+		warnings, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "spec.victoriametrics.backup is ignored")
+
+		vm.VmSingle.Install = nil
+		vm.VmSingle.Storage = &corev1.PersistentVolumeClaimSpec{}
+		vm.Backup.S3 = nil
+		vm.Backup.Filesystem = &v1alpha1.VmBackupFilesystem{Volume: v1alpha1.VmBackupVolume{
+			HostPath: &corev1.HostPathVolumeSource{Path: "/var/backups"},
+			NFS:      &corev1.NFSVolumeSource{Server: "nfs", Path: "/backups"},
+		}}
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.ErrorContains(t, err, "spec.victoriametrics.backup.filesystem.volume: Forbidden")
+		vm.Backup.Filesystem.Volume.NFS = nil
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+	})
+	t.Run("Test retention policy of VictoriaMetrics", func(t *testing.T) {
+		vm := &v1alpha1.Victoriametrics{
+			VmSingle: v1alpha1.VmSingle{
+				Image:           "victoriametrics/victoria-metrics:v1.103.0-enterprise",
+				RetentionPeriod: "14d",
+				ExtraArgs:       map[string]string{"licenseFile": "/etc/vm/license", "dedup.minScrapeInterval": "30s"},
+			},
+			RetentionPolicy: &v1alpha1.VmRetentionPolicy{
+				Filters: []v1alpha1.VmRetentionFilter{{Filter: `{env="dev"}`, RetentionPeriod: "3d"}},
+				Downsampling: []v1alpha1.VmDownsampling{
+					{Filter: `{env="prod"}`, Offset: "0s", Interval: "0s"},
+					{Offset: "1d", Interval: "1m"},
+					{Offset: "7d", Interval: "5m"},
+				},
+			},
+		}
+		warnings, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		vm.VmSingle.Image = "victoriametrics/victoria-metrics:v1.103.0"
+		vm.VmSingle.ExtraArgs["retentionFilter"] = `{env="qa"}:1d`
+		vm.RetentionPolicy.Filters = append(vm.RetentionPolicy.Filters,
+			v1alpha1.VmRetentionFilter{Filter: "sum(up)", RetentionPeriod: "30d"})
+		vm.RetentionPolicy.Tenants = []v1alpha1.VmTenantRetention{{Tenant: "5", RetentionPeriod: "1d"}}
+		vm.RetentionPolicy.Downsampling = append(vm.RetentionPolicy.Downsampling,
+			v1alpha1.VmDownsampling{Offset: "30d", Interval: "7m"}, v1alpha1.VmDownsampling{Offset: "60d", Interval: "0s"})
+		warnings, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.victoriametrics.vmSingle.extraArgs[retentionFilter]",
+			"spec.victoriametrics.retentionPolicy.filters[1].filter",
+			"spec.victoriametrics.retentionPolicy.filters[1].retentionPeriod",
+			"spec.victoriametrics.retentionPolicy.downsampling[3].interval",
+			"spec.victoriametrics.retentionPolicy.downsampling[4].interval",
+			"spec.victoriametrics.vmSingle.image",
+		}, fields)
+		assert.ElementsMatch(t, []string{"spec.victoriametrics.retentionPolicy.tenants are ignored because VmCluster is not installed"}, warnings)
+
+		vm.VmSingle.Image = "victoriametrics/victoria-metrics:v1.99.0-enterprise"
+		vm.VmSingle.ExtraArgs = nil
+		vm.RetentionPolicy = &v1alpha1.VmRetentionPolicy{
+			Downsampling: []v1alpha1.VmDownsampling{{Filter: `{env="dev"}`, Offset: "1d", Interval: "5m"}},
+		}
+		warnings, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.True(t, errors.IsInvalid(err))
+		causes := err.(*errors.StatusError).ErrStatus.Details.Causes
+		assert.Len(t, causes, 1)
+		assert.Equal(t, "spec.victoriametrics.retentionPolicy.downsampling[0].filter", causes[0].Field)
+		assert.ElementsMatch(t, []string{"the enterprise image requires license, licenseFile or eula in spec.victoriametrics.vmSingle.extraArgs"}, warnings)
+
+		// The deduplication interval is set by the operator if vmagent has several replicas
+		vm.VmSingle.Image = "victoriametrics/victoria-metrics:v1.103.0-enterprise"
+		vm.VmAgent.Replicas = ptr.To[int32](2)
+		vm.RetentionPolicy = &v1alpha1.VmRetentionPolicy{Downsampling: []v1alpha1.VmDownsampling{{Offset: "1d", Interval: "45s"}}}
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.ErrorContains(t, err, "spec.victoriametrics.retentionPolicy.downsampling[0].interval: Invalid value: \"45s\"")
+		vm.RetentionPolicy.Downsampling[0].Interval = "1m"
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+
+		vm.VmSingle.Install = ptr.To(false)
+		warnings, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Victoriametrics: vm}))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"spec.victoriametrics.retentionPolicy is ignored because neither VmSingle nor VmCluster is installed"}, warnings)
+	})
+	t.Run("Test dashboard sources", func(t *testing.T) {
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			GrafanaDashboards: &v1alpha1.GrafanaDashboards{Sources: []v1alpha1.DashboardSource{
+				{Name: "team-a", ConfigMaps: &metav1.LabelSelector{MatchLabels: map[string]string{"dashboards": "team-a"}}},
+				{Name: "team-b", Path: "/dashboards/team-b.tar.gz"},
+				{Name: "team-c", URL: "http://dashboards.team-c.svc:8080/dashboards.tgz"},
+			}},
+		}))
+		assert.NoError(t, err)
+
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			GrafanaDashboards: &v1alpha1.GrafanaDashboards{Sources: []v1alpha1.DashboardSource{
+				{Name: "team-a", Path: "dashboards"},
+				{Name: "team-a", URL: "https://grafana.com/api/dashboards/1860"},
+				{Name: "team-b", Path: "/dashboards", URL: "http://dashboards"},
+				{Name: "team-c"},
+			}},
+		}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.grafanaDashboards.sources[0].path",
+			"spec.grafanaDashboards.sources[1].name",
+			"spec.grafanaDashboards.sources[1].url",
+			"spec.grafanaDashboards.sources[2]",
+			"spec.grafanaDashboards.sources[3]",
+		}, fields)
+	})
+	t.Run("Test Grafana folders", func(t *testing.T) {
+		folders := []v1alpha1.GrafanaFolder{
+			{
+				Title:      "Platform",
+				Dashboards: []string{"etcd-dashboard", "alerts-overview"},
+				Permissions: []v1alpha1.GrafanaFolderPermission{
+					{Role: "Viewer", Permission: v1alpha1.GrafanaPermissionView},
+					{Team: "platform", Permission: v1alpha1.GrafanaPermissionEdit},
+					{Group: "platform-admins", Permission: v1alpha1.GrafanaPermissionAdmin},
+				},
+			},
+		}
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Auth:    &v1alpha1.Auth{LoginURL: "https://idp/auth", TokenURL: "https://idp/token", UserInfoURL: "https://idp/userinfo"},
+			Grafana: &v1alpha1.Grafana{Folders: folders},
+		}))
+		assert.NoError(t, err)
+
+		folders = append(folders, v1alpha1.GrafanaFolder{
+			Title:       "Platform",
+			Dashboards:  []string{"etcd-dashboard"},
+			Permissions: []v1alpha1.GrafanaFolderPermission{{Team: "team-a", Role: "Viewer", Permission: v1alpha1.GrafanaPermissionView}},
+		})
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Grafana: &v1alpha1.Grafana{Folders: folders}}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.grafana.folders[0].permissions[2].group",
+			"spec.grafana.folders[1].title",
+			"spec.grafana.folders[1].dashboards[0]",
+			"spec.grafana.folders[1].permissions[0]",
+		}, fields)
+	})
+	t.Run("Test Grafana datasources", func(t *testing.T) {
+		datasources := []v1alpha1.GrafanaDatasource{
+			{Name: "Loki", Type: v1alpha1.GrafanaDatasourceLoki, UID: "loki", URL: "http://loki-gateway.logging.svc", IsDefault: true},
+			{Name: "Tempo", Type: v1alpha1.GrafanaDatasourceTempo, Discovery: &v1alpha1.DatasourceDiscovery{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": "tempo"}},
+			}},
+		}
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{Grafana: &v1alpha1.Grafana{Datasources: datasources}}))
+		assert.NoError(t, err)
+
+		datasources = append(datasources,
+			v1alpha1.GrafanaDatasource{Name: "Loki", Type: v1alpha1.GrafanaDatasourceLoki, UID: "loki", IsDefault: true},
+			v1alpha1.GrafanaDatasource{Name: "Platform Monitoring Prometheus", Type: v1alpha1.GrafanaDatasourcePrometheus,
+				URL: "http://prometheus:9090", Discovery: &v1alpha1.DatasourceDiscovery{
+					Selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}}},
+				}},
+		)
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{Grafana: &v1alpha1.Grafana{Datasources: datasources}}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.grafana.datasources[2].name",
+			"spec.grafana.datasources[2].uid",
+			"spec.grafana.datasources[2].isDefault",
+			"spec.grafana.datasources[2]",
+			"spec.grafana.datasources[3].name",
+			"spec.grafana.datasources[3].discovery.selector",
+			"spec.grafana.datasources[3]",
+		}, fields)
+	})
+	t.Run("Test Prometheus rules", func(t *testing.T) {
+		interval := promv1.Duration("1m")
+		rules := &v1alpha1.PrometheusRules{
+			Override: []v1alpha1.PrometheusRule{
+				{Group: "SelfMonitoring", Alert: "PrometheusJobMissing", Disabled: true},
+				{Group: "SelfMonitoring", Alert: "PrometheusTargetMissing", For: "10m", KeepFiringFor: "5m",
+					Labels: map[string]string{"team": "platform"}},
+			},
+			Groups: []v1alpha1.PrometheusRuleGroup{
+				{Name: "SelfMonitoring", Interval: &interval},
+				{Name: "Platform", Rules: []promv1.Rule{{Record: "job:up:sum", Expr: intstr.FromString("sum by (job) (up)")}}},
+			},
+		}
+		_, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{PrometheusRules: rules}))
+		assert.NoError(t, err)
+
+		invalid := promv1.Duration("one minute")
+		rules.Override = append(rules.Override,
+			v1alpha1.PrometheusRule{Alert: "PrometheusJobMissing", Record: "job:missing", For: "5 minutes"},
+			v1alpha1.PrometheusRule{Group: "SelfMonitoring", KeepFiringFor: "-"},
+		)
+		rules.Groups = append(rules.Groups, v1alpha1.PrometheusRuleGroup{
+			Name:     "Platform",
+			Interval: &invalid,
+			Rules:    []promv1.Rule{{Expr: intstr.FromString("vector(1)")}, {Alert: "Empty"}},
+		})
+		_, err = Validate(newCR(v1alpha1.PlatformMonitoringSpec{PrometheusRules: rules}))
+		assert.True(t, errors.IsInvalid(err))
+		var fields []string
+		for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"spec.prometheusRules.override[2].group",
+			"spec.prometheusRules.override[2]",
+			"spec.prometheusRules.override[2].for",
+			"spec.prometheusRules.override[3]",
+			"spec.prometheusRules.override[3].keepFiringFor",
+			"spec.prometheusRules.groups[2].name",
+			"spec.prometheusRules.groups[2].interval",
+			"spec.prometheusRules.groups[2].rules[0]",
+			"spec.prometheusRules.groups[2].rules[1].expr",
+		}, fields)
+	})
+	t.Run("Test deprecated fields", func(t *testing.T) {
+		warnings, err := Validate(newCR(v1alpha1.PlatformMonitoringSpec{
+			Auth: &v1alpha1.Auth{ClientID: "client", ClientSecret: "secret"},
+		}))
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "spec.auth.clientId is deprecated")
+	})
+	t.Run("Test update of invalid object without changes of spec", func(t *testing.T) {
+		oldCR := newCR(v1alpha1.PlatformMonitoringSpec{
+			Prometheus: &v1alpha1.Prometheus{Retention: "7days"},
+		})
+		cr := oldCR.DeepCopy()
+		cr.Finalizers = []string{"monitoring.qubership.org/cleanup"}
+		warnings, err := (&PlatformMonitoringWebhook{}).ValidateUpdate(context.Background(), oldCR, cr)
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 1)
+
+		cr.Spec.Prometheus.Retention = "8days"
+		_, err = (&PlatformMonitoringWebhook{}).ValidateUpdate(context.Background(), oldCR, cr)
+		assert.True(t, errors.IsInvalid(err))
+	})
+	t.Run("Test deleted object is not validated", func(t *testing.T) {
+		cr := newCR(v1alpha1.PlatformMonitoringSpec{
+			Prometheus: &v1alpha1.Prometheus{Retention: "7days"},
+		})
+		now := metav1.Now()
+		cr.DeletionTimestamp = &now
+		_, err := (&PlatformMonitoringWebhook{}).ValidateUpdate(context.Background(), cr, cr)
+		assert.NoError(t, err)
+	})
+}
@@ -17,27 +17,34 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"io"
 	_ "net/http/pprof"
 	"os"
 
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	qubershiporg1 "github.com/Netcracker/qubership-monitoring-operator/api/v1alpha1"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers"
 	"github.com/Netcracker/qubership-monitoring-operator/controllers/utils"
+	monitoringwebhook "github.com/Netcracker/qubership-monitoring-operator/controllers/webhook"
 	vmetricsv1b1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	apis "github.com/grafana-operator/grafana-operator/v4/api"
 	grafv1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
 	secv1 "github.com/openshift/api/security/v1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	extensionsobj "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/yaml"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -57,11 +64,21 @@ func init() {
 
 	utilruntime.Must(qubershiporg1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
+
+	// Add grafana, extention, prometheus, victoriametrics and scc schemes
+	utilruntime.Must(apis.AddToScheme(scheme))
+	utilruntime.Must(extensionsobj.AddToScheme(scheme))
+	utilruntime.Must(promv1.AddToScheme(scheme))
+	utilruntime.Must(vmetricsv1b1.AddToScheme(scheme))
+	utilruntime.Must(secv1.Install(scheme))
+	utilruntime.Must(grafv1.AddToScheme(scheme))
 }
 
 func main() {
-	var metricsAddr, probeAddr, pprofAddr string
-	var enableLeaderElection, pprofEnabled bool
+	var metricsAddr, probeAddr, pprofAddr, webhookCertDir, renderFile string
+	var enableLeaderElection, pprofEnabled, webhookEnabled bool
+	var webhookPort int
+	var renderOptions controllers.RenderOptions
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -71,14 +88,25 @@ func main() {
 	flag.BoolVar(&utils.PrivilegedRights, "privilegedRights", false, "Indicates is extended privileges should be used for the monitoring components")
 	flag.BoolVar(&pprofEnabled, "pprof-enable", false, "Enable pprof.")
 	flag.StringVar(&pprofAddr, "pprof-address", ":9180", "The pprof address.")
+	flag.BoolVar(&webhookEnabled, "webhook-enable", false, "Enable the validating webhook for PlatformMonitoring.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory with tls.crt and tls.key of the webhook server.")
+	flag.StringVar(&renderFile, "render", "", "Path to the PlatformMonitoring YAML file ('-' for stdin). "+
+		"If set, manifests of all objects created for it are written to stdout instead of running the operator.")
+	flag.StringVar(&renderOptions.KubernetesVersion, "render-kubernetes-version", "v1.30.0", "The Kubernetes version of the cluster manifests are rendered for.")
+	flag.BoolVar(&renderOptions.OpenShift, "render-openshift", false, "Render manifests for an OpenShift cluster.")
+	flag.StringVar(&renderOptions.IngressAPI, "render-ingress-api", controllers.IngressAPIV1, "The Ingress API version of the cluster manifests are rendered for: v1, v1beta1 or none.")
+	flag.BoolVar(&renderOptions.PodSecurityPolicy, "render-psp", false, "Render manifests for a cluster with the PodSecurityPolicy API.")
 	flag.Parse()
 
+	if renderFile != "" {
+		os.Exit(render(renderFile, renderOptions))
+	}
+
 	ctrl.SetLogger(utils.Logger(""))
 
-	namespace, found := os.LookupEnv("WATCH_NAMESPACE")
-	if !found {
-		namespace = "monitoring"
-	}
+	// PlatformMonitoring custom resources are handled in listed namespaces or in all namespaces if the list is empty
+	namespaces := utils.WatchNamespaces()
 
 	if !pprofEnabled {
 		pprofAddr = ""
@@ -89,10 +117,18 @@ func main() {
 		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
 		HealthProbeBindAddress: probeAddr,
 		PprofBindAddress:       pprofAddr,
+		WebhookServer:          webhook.NewServer(webhook.Options{Port: webhookPort, CertDir: webhookCertDir}),
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "b0cb59fe.qubership.org",
+		// Secrets are read directly from the API server, so data of Secrets is not cached for the whole cluster
+		Client: client.Options{Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}}},
 		NewCache: func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
-			opts.DefaultNamespaces = map[string]cache.Config{namespace: {}}
+			if len(namespaces) > 0 {
+				opts.DefaultNamespaces = make(map[string]cache.Config, len(namespaces))
+				for _, ns := range namespaces {
+					opts.DefaultNamespaces[ns] = cache.Config{}
+				}
+			}
 			return cache.New(config, opts)
 		},
 	})
@@ -102,40 +138,21 @@ func main() {
 	}
 	logger.Info("Registering Components.")
 
-	// Setup Scheme for all resources
-	if err = apis.AddToScheme(mgr.GetScheme()); err != nil {
-		logger.Error(err, "")
-		os.Exit(1)
-	}
-
-	// Add extention scheme
-	if err = extensionsobj.AddToScheme(mgr.GetScheme()); err != nil {
-		logger.Error(err, "")
-		os.Exit(1)
-	}
-
-	// Add prometheus scheme
-	if err = promv1.AddToScheme(mgr.GetScheme()); err != nil {
-		logger.Error(err, "")
+	// Cache of the manager is not started yet, so use the client without cache
+	apiClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		logger.Error(err, "Create client failed")
 		os.Exit(1)
 	}
-
-	// Add victoriametrics scheme
-	if err = vmetricsv1b1.AddToScheme(mgr.GetScheme()); err != nil {
-		logger.Error(err, "")
-		os.Exit(1)
+	migrateNamespaces := namespaces
+	if len(migrateNamespaces) == 0 {
+		// Custom resources are listed in all namespaces
+		migrateNamespaces = []string{""}
 	}
-
-	// Add scc scheme
-	if err = secv1.Install(mgr.GetScheme()); err != nil {
-		logger.Error(err, "")
-		os.Exit(1)
-	}
-
-	// Add grafana schema
-	if err = grafv1.AddToScheme(mgr.GetScheme()); err != nil {
-		logger.Error(err, "")
-		os.Exit(1)
+	for _, ns := range migrateNamespaces {
+		if err = controllers.MigrateLegacyStatus(context.Background(), apiClient, ns); err != nil {
+			logger.Error(err, "Migration of PlatformMonitoring status failed", "namespace", ns)
+		}
 	}
 
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
@@ -148,11 +165,19 @@ func main() {
 		Log:             utils.Logger("controller-platformmonitoring"),
 		Config:          mgr.GetConfig(),
 		DiscoveryClient: discoveryClient,
+		Recorder:        mgr.GetEventRecorderFor("monitoring-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PlatformMonitoring")
 		os.Exit(1)
 	}
 
+	if webhookEnabled {
+		if err = (&monitoringwebhook.PlatformMonitoringWebhook{}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PlatformMonitoring")
+			os.Exit(1)
+		}
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -161,3 +186,45 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// render writes manifests of objects created for the PlatformMonitoring from the file to stdout.
+// Returns the exit code of the operator.
+func render(file string, opts controllers.RenderOptions) int {
+	// Stdout is used for manifests
+	utils.LogOutputPath = "stderr"
+	log := utils.Logger("render")
+
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		log.Error(err, "Failed to read PlatformMonitoring")
+		return 1
+	}
+	cr := &qubershiporg1.PlatformMonitoring{}
+	if err = yaml.UnmarshalStrict(data, cr); err != nil {
+		log.Error(err, "Failed to parse PlatformMonitoring")
+		return 1
+	}
+	results, err := controllers.Render(context.Background(), cr, opts, scheme, os.Stdout)
+	if err != nil {
+		log.Error(err, "Failed to render manifests")
+		return 1
+	}
+	code := 0
+	for _, result := range results {
+		switch {
+		case result.Skipped():
+			log.Info("Component skipped because of failed dependency", utils.ComponentKey, result.Name, "dependency", result.SkippedBy)
+			code = 1
+		case result.Err != nil:
+			log.Error(result.Err, "Failed to render component, its manifests may be incomplete", utils.ComponentKey, result.Name)
+			code = 1
+		}
+	}
+	return code
+}
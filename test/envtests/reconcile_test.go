@@ -45,8 +45,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("Prometheus-operator", func() {
 		// Run Prometheus-operator
-		poReconciler := prometheus_operator.NewPrometheusOperatorReconciler(k8sClient, scheme.Scheme)
-		err = poReconciler.Run(&cr)
+		poReconciler := prometheus_operator.NewPrometheusOperatorReconciler(k8sClient, scheme.Scheme, nil)
+		err = poReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get Role
@@ -123,8 +123,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("KubernetesMonitors", func() {
 		//Run KubernetesMonitors reconcile
-		kubernetesMonitorsReconciler := kubernetes_monitors.NewKubernetesMonitorsReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = kubernetesMonitorsReconciler.Run(&cr)
+		kubernetesMonitorsReconciler := kubernetes_monitors.NewKubernetesMonitorsReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = kubernetesMonitorsReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ApiServerServiceMonitor
@@ -185,8 +185,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("Prometheus", func() {
 		//Run Prometheus reconcile
-		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = pReconciler.Run(&cr)
+		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = pReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ServiceAccount
@@ -254,8 +254,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("Alertmanager", func() {
 		// Run AlertManager reconcile
-		aReconciler := alertmanager.NewAlertManagerReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = aReconciler.Run(&cr)
+		aReconciler := alertmanager.NewAlertManagerReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = aReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get Secret
@@ -347,8 +347,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("KubeStateMetrics", func() {
 		// Run KubeStateMetrics reconcile
-		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = ksmReconciler.Run(&cr)
+		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = ksmReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ServiceAccount
@@ -411,8 +411,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("NodeExporter", func() {
 		//Run NodeExporter reconcile
-		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = neReconciler.Run(&cr)
+		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = neReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ServiceAccount
@@ -475,8 +475,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("Grafana-operator", func() {
 		// Run Grafana-operator reconcile
-		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = goReconciler.Run(&cr)
+		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = goReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get GrafanaDashboard
@@ -576,8 +576,8 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("Grafana", func() {
 		// Run Grafana reconcile
-		gReconciler := grafana.NewGrafanaReconciler(k8sClient, scheme.Scheme, discoveryClient, cfg)
-		err = gReconciler.Run(&cr)
+		gReconciler := grafana.NewGrafanaReconciler(k8sClient, scheme.Scheme, discoveryClient, cfg, nil)
+		err = gReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get Grafana
@@ -640,23 +640,17 @@ var _ = Describe("Reconcile", func() {
 	})
 	It("PrometheusRule", func() {
 		// Run PrometheusRule reconcile
-		prReconciler := prometheus_rules.NewPrometheusRulesReconciler(k8sClient, scheme.Scheme)
-		err = prReconciler.Run(&cr)
+		prReconciler := prometheus_rules.NewPrometheusRulesReconciler(k8sClient, scheme.Scheme, nil)
+		err = prReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
-		// Get PrometheusRule
-		rule := promv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
-			Name:      "prometheus-rules",
-			Namespace: cr.GetNamespace(),
-		},
-		}
-		err = k8sClient.Get(context.TODO(), client.ObjectKey{
-			Namespace: rule.Namespace,
-			Name:      rule.Name,
-		}, &rule)
+		// Get PrometheusRules of groups
+		rules := promv1.PrometheusRuleList{}
+		err = k8sClient.List(context.TODO(), &rules, client.InNamespace(cr.GetNamespace()),
+			client.HasLabels{prometheus_rules.RuleGroupLabel})
 		Expect(err).NotTo(HaveOccurred())
-		Expect(rule).NotTo(BeNil())
-		logf.Log.Info("Getting PrometheusRule successful")
+		Expect(rules.Items).NotTo(BeEmpty())
+		logf.Log.Info("Getting PrometheusRules successful")
 	})
 	It("Cleanup", func() {
 		flag := false
@@ -670,43 +664,43 @@ var _ = Describe("Reconcile", func() {
 		cr.Spec.PrometheusRules.Install = &flag
 
 		//Run KubernetesMonitors reconcile
-		kubernetesMonitorsReconciler := kubernetes_monitors.NewKubernetesMonitorsReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = kubernetesMonitorsReconciler.Run(&cr)
+		kubernetesMonitorsReconciler := kubernetes_monitors.NewKubernetesMonitorsReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = kubernetesMonitorsReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		//Run Prometheus reconcile
-		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = pReconciler.Run(&cr)
+		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = pReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Run AlertManager reconcile
-		aReconciler := alertmanager.NewAlertManagerReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = aReconciler.Run(&cr)
+		aReconciler := alertmanager.NewAlertManagerReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = aReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Run KubeStateMetrics reconcile
-		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = ksmReconciler.Run(&cr)
+		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = ksmReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		//Run NodeExporter reconcile
-		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = neReconciler.Run(&cr)
+		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = neReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Run Grafana-operator reconcile
-		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = goReconciler.Run(&cr)
+		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = goReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Run Grafana reconcile
-		gReconciler := grafana.NewGrafanaReconciler(k8sClient, scheme.Scheme, discoveryClient, cfg)
-		err = gReconciler.Run(&cr)
+		gReconciler := grafana.NewGrafanaReconciler(k8sClient, scheme.Scheme, discoveryClient, cfg, nil)
+		err = gReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Run PrometheusRule reconcile
-		prReconciler := prometheus_rules.NewPrometheusRulesReconciler(k8sClient, scheme.Scheme)
-		err = prReconciler.Run(&cr)
+		prReconciler := prometheus_rules.NewPrometheusRulesReconciler(k8sClient, scheme.Scheme, nil)
+		err = prReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
@@ -36,8 +36,8 @@ var _ = Describe("Reconcile with privilegedRights", func() {
 	})
 	It("Prometheus-operator", func() {
 		// Run Prometheus-operator
-		poReconciler := prometheus_operator.NewPrometheusOperatorReconciler(k8sClient, scheme.Scheme)
-		err = poReconciler.Run(&cr)
+		poReconciler := prometheus_operator.NewPrometheusOperatorReconciler(k8sClient, scheme.Scheme, nil)
+		err = poReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ClusterRole
@@ -70,8 +70,8 @@ var _ = Describe("Reconcile with privilegedRights", func() {
 	})
 	It("Prometheus", func() {
 		//Run Prometheus reconcile
-		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = pReconciler.Run(&cr)
+		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = pReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ClusterRole
@@ -104,8 +104,8 @@ var _ = Describe("Reconcile with privilegedRights", func() {
 	})
 	It("KubeStateMetrics", func() {
 		// Run KubeStateMetrics reconcile
-		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = ksmReconciler.Run(&cr)
+		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = ksmReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ClusterRole
@@ -138,8 +138,8 @@ var _ = Describe("Reconcile with privilegedRights", func() {
 	})
 	It("NodeExporter", func() {
 		//Run NodeExporter reconcile
-		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = neReconciler.Run(&cr)
+		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = neReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ClusterRole
@@ -172,8 +172,8 @@ var _ = Describe("Reconcile with privilegedRights", func() {
 	})
 	It("Grafana-operator", func() {
 		// Run Grafana-operator reconcile
-		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = goReconciler.Run(&cr)
+		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = goReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Get ClusterRole
@@ -216,28 +216,28 @@ var _ = Describe("Reconcile with privilegedRights", func() {
 		cr.Spec.PrometheusRules.Install = &flag
 
 		//Run KubernetesMonitors reconcile
-		kubernetesMonitorsReconciler := kubernetes_monitors.NewKubernetesMonitorsReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = kubernetesMonitorsReconciler.Run(&cr)
+		kubernetesMonitorsReconciler := kubernetes_monitors.NewKubernetesMonitorsReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = kubernetesMonitorsReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		//Run Prometheus reconcile
-		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = pReconciler.Run(&cr)
+		pReconciler := prometheus.NewPrometheusReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = pReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Run KubeStateMetrics reconcile
-		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = ksmReconciler.Run(&cr)
+		ksmReconciler := kubestatemetrics.NewKubeStateMetricsReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = ksmReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		//Run NodeExporter reconcile
-		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = neReconciler.Run(&cr)
+		neReconciler := nodeexporter.NewNodeExporterReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = neReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Run Grafana-operator reconcile
-		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient)
-		err = goReconciler.Run(&cr)
+		goReconciler := grafana_operator.NewGrafanaOperatorReconciler(k8sClient, scheme.Scheme, discoveryClient, nil)
+		err = goReconciler.Run(context.TODO(), &cr)
 		Expect(err).NotTo(HaveOccurred())
 	})
 })